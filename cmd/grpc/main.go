@@ -2,26 +2,184 @@ package main
 
 import (
 	"context"
+	"encoding/hex"
+	"errors"
 	"fmt"
 	"github.com/fasttrack-solutions/random"
+	"github.com/fasttrack-solutions/random/internal/audit"
+	"github.com/fasttrack-solutions/random/internal/commitment"
 	"github.com/fasttrack-solutions/random/internal/config"
+	"github.com/fasttrack-solutions/random/internal/crash"
+	"github.com/fasttrack-solutions/random/internal/deterministic"
+	"github.com/fasttrack-solutions/random/internal/idempotency"
+	"github.com/fasttrack-solutions/random/internal/keyring"
+	"github.com/fasttrack-solutions/random/internal/registry"
+	"github.com/fasttrack-solutions/random/internal/sequence"
+	"github.com/fasttrack-solutions/random/internal/storage"
+	"github.com/fasttrack-solutions/random/internal/unseal"
+	"github.com/fasttrack-solutions/random/pkg/hashchain"
 	"github.com/fasttrack-solutions/random/pkg/pb"
+	"github.com/fasttrack-solutions/random/pkg/receipt"
 	"github.com/grpc-ecosystem/go-grpc-middleware"
 	"github.com/grpc-ecosystem/go-grpc-middleware/recovery"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
 	"google.golang.org/grpc/reflection"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
 	"log/slog"
 	"net"
 	"os"
+	"path"
 	"runtime/debug"
+	"time"
 )
 
 func main() {
-	seed := *config.SEEDHEX
-	if len(seed) != 64 {
-		panic("seed must be 64 hex characters")
-	} else if seed == "0000000000000000000000000000000000000000000000000000000000000000" {
-		panic("a unique seed value is required")
+	if (*config.SeedCommitments || *config.SeedReveal) && !storage.Persistent(*config.Store) {
+		panic(errors.New("-seed-commitments and -seed-reveal require a persistent -store, a file path or a redis address"))
+	}
+	if len(*config.CrashChainFile) > 0 && !storage.Persistent(*config.Store) {
+		// A restart would serve the rounds again, their seeds and crash points are already public
+		panic(errors.New("-crash-chain-file requires a persistent -store, a file path or a redis address"))
+	}
+
+	passphrase, errPassphrase := keyring.ReadPassphrase(*config.KeystorePassphraseFile, keyring.PassphraseEnv)
+	if errPassphrase != nil {
+		panic(errPassphrase)
+	}
+
+	seedSource := keyring.Source{
+		SeedHex:    *config.SEEDHEX,
+		SeedFile:   *config.SeedFile,
+		File:       *config.KeyringFile,
+		Dir:        *config.KeyringDir,
+		Keystore:   *config.KeystoreFile,
+		Passphrase: passphrase,
+	}
+	var ring *keyring.Keyring
+	var errRing error
+	if len(*config.SeedSharesAddr) > 0 {
+		// Dual control: the seed only exists in memory, combined from the shares of several operators
+		if seedSource.Reloadable() {
+			panic(errors.New("-seed-shares-addr can not be combined with a seed, keyring or keystore file"))
+		}
+		seedHex, errCollect := unseal.Collect(context.Background(), *config.SeedSharesAddr, *config.SeedSharesFingerprint)
+		if errCollect != nil {
+			panic(errCollect)
+		}
+		ring, errRing = keyring.Single(seedHex)
+	} else {
+		ring, errRing = seedSource.Load()
+	}
+	if errRing != nil {
+		panic(errRing)
+	}
+
+	errMode := sequence.ValidateMode(*config.SequenceMode)
+	if errMode != nil {
+		panic(errMode)
+	}
+
+	drawer, errDrawer := deterministic.New(ring, *config.DeterministicAlgorithm, *config.SeedDerivation)
+	if errDrawer != nil {
+		panic(errDrawer)
+	}
+
+	store, errStore := storage.Open(*config.Store)
+	if errStore != nil {
+		slog.Error("failed to open store", "error", errStore.Error())
+		os.Exit(1)
+	}
+	defer store.Close()
+
+	errMigrate := storage.Migrate(context.Background(), store, storage.Migrations)
+	if errMigrate != nil {
+		slog.Error("failed to migrate store", "error", errMigrate.Error())
+		os.Exit(1)
+	}
+
+	// Commitments lost on a restart would be published again, later than the draws they should precede
+	var commitments *commitment.Registry
+	if *config.SeedCommitments || *config.SeedReveal {
+		commitments = commitment.New(store)
+		errPublish := commitments.Publish(context.Background(), ring)
+		if errPublish != nil {
+			slog.Error("failed to publish seed commitments", "error", errPublish.Error())
+			os.Exit(1)
+		}
+	}
+
+	// Rotate seeds without a restart when the key files change, a keyring replacing a committed seed is refused
+	if seedSource.Reloadable() {
+		go keyring.Watch(context.Background(), seedSource, *config.KeyringReloadInterval, func(r *keyring.Keyring) {
+			if commitments != nil {
+				errPublish := commitments.Publish(context.Background(), r)
+				if errPublish != nil {
+					slog.Error("failed to publish seed commitments, keeping the current keys", "error", errPublish.Error())
+					return
+				}
+			}
+			drawer.SetKeyring(r)
+			slog.Info("keyring reloaded", "keys", len(r.Keys()))
+		})
+	}
+
+	allocator := sequence.NewAllocator(store)
+
+	idempotencyCache, errIdempotency := idempotency.New(store, *config.IdempotencyTTL)
+	if errIdempotency != nil {
+		panic(errIdempotency)
+	}
+
+	drawRegistry, errRegistry := registry.New(*config.SingleUse, store)
+	if errRegistry != nil {
+		panic(errRegistry)
+	}
+
+	var crashGame *crash.Game
+	if len(*config.CrashChainFile) > 0 {
+		var errCrash error
+		crashGame, errCrash = crash.Open(*config.CrashChainFile, *config.CrashHouseEdge, store)
+		if errCrash != nil {
+			slog.Error("failed to open crash chain", "error", errCrash.Error())
+			os.Exit(1)
+		}
+		slog.Info("serving crash rounds", "terminalHash", hex.EncodeToString(crashGame.Terminal()), "length", crashGame.Length())
+	}
+
+	var signer *receipt.Signer
+	if len(*config.ReceiptKeyFile) > 0 {
+		var errSigner error
+		signer, errSigner = receipt.LoadSigner(*config.ReceiptKeyFile)
+		if errSigner != nil {
+			slog.Error("failed to load receipt key", "error", errSigner.Error())
+			os.Exit(1)
+		}
+		slog.Info("signing receipts", "keyId", signer.KeyID())
+	}
+
+	interceptors := []grpc.UnaryServerInterceptor{}
+	if len(*config.AuditDir) > 0 {
+		auditLog, errAudit := audit.Open(audit.Options{
+			Dir:            *config.AuditDir,
+			MaxSegmentSize: *config.AuditSegmentSize,
+			Fsync:          *config.AuditFsync,
+			FsyncInterval:  *config.AuditFsyncInterval,
+		})
+		if errAudit != nil {
+			slog.Error("failed to open audit log", "error", errAudit.Error())
+			os.Exit(1)
+		}
+		defer auditLog.Close()
+
+		interceptors = append(interceptors, auditInterceptor(auditLog, drawer))
+	}
+	// Receipts are attached inside the audit interceptor, so the audit log records them
+	if signer != nil {
+		interceptors = append(interceptors, receiptInterceptor(signer))
 	}
 
 	recoveryOpts := []grpc_recovery.Option{
@@ -38,13 +196,13 @@ func main() {
 			),
 		),
 		grpc.ChainUnaryInterceptor(
-			grpc_recovery.UnaryServerInterceptor(recoveryOpts...),
+			append([]grpc.UnaryServerInterceptor{grpc_recovery.UnaryServerInterceptor(recoveryOpts...)}, interceptors...)...,
 		),
 	)
 
 	reflection.Register(s)
 
-	randomServer := NewRandomGRPCServer(seed)
+	randomServer := NewRandomGRPCServer(drawer, *config.SequenceMode, allocator, sequence.NewReplayClients(*config.ReplayTokens), drawRegistry, idempotencyCache, signer, commitments, *config.SeedReveal, crashGame)
 	pb.RegisterRandomServer(s, randomServer)
 
 	lis, errListen := net.Listen("tcp", fmt.Sprintf(":%v", *config.GRPCPort))
@@ -62,15 +220,62 @@ func main() {
 	}
 }
 
+const (
+	// replayTokenKey is the metadata key replay clients use to present their token
+	replayTokenKey = "x-replay-token"
+	// idempotencyKeyKey is the metadata key of the idempotency key, used when the request field is empty
+	idempotencyKeyKey = "idempotency-key"
+	// clientIDKey is the metadata key clients identify themselves with in the audit log
+	clientIDKey = "x-client-id"
+)
+
+// drawMethods are the methods of the Random service that draw numbers
+var drawMethods = map[string]bool{
+	pb.Random_GetRandomInt64_FullMethodName:          true,
+	pb.Random_GetRandomFloat64_FullMethodName:        true,
+	pb.Random_GetDeterministicRandom_FullMethodName:  true,
+	pb.Random_DrawDeterministicRandom_FullMethodName: true,
+	pb.Random_NextCrashRound_FullMethodName:          true,
+}
+
 type RandomGRPCServer struct {
 	pb.UnimplementedRandomServer
-	seed string
+	drawer        *deterministic.Drawer
+	sequenceMode  string
+	allocator     *sequence.Allocator
+	replayClients *sequence.ReplayClients
+	registry      *registry.Registry
+	idempotency   *idempotency.Cache
+	signer        *receipt.Signer
+	commitments   *commitment.Registry
+	seedReveal    bool
+	crash         *crash.Game
 }
 
-func NewRandomGRPCServer(seed string) *RandomGRPCServer {
+func NewRandomGRPCServer(drawer *deterministic.Drawer, sequenceMode string, allocator *sequence.Allocator, replayClients *sequence.ReplayClients, drawRegistry *registry.Registry, idempotencyCache *idempotency.Cache, signer *receipt.Signer, commitments *commitment.Registry, seedReveal bool, crashGame *crash.Game) *RandomGRPCServer {
 	return &RandomGRPCServer{
-		seed: seed,
+		drawer:        drawer,
+		sequenceMode:  sequenceMode,
+		allocator:     allocator,
+		replayClients: replayClients,
+		registry:      drawRegistry,
+		idempotency:   idempotencyCache,
+		signer:        signer,
+		commitments:   commitments,
+		seedReveal:    seedReveal,
+		crash:         crashGame,
+	}
+}
+
+func (rs *RandomGRPCServer) GetReceiptPublicKey(ctx context.Context, req *pb.GetReceiptPublicKeyRequest) (*pb.GetReceiptPublicKeyResponse, error) {
+	if rs.signer == nil {
+		return nil, status.Error(codes.FailedPrecondition, "receipts are not signed, set -receipt-key-file")
 	}
+
+	return &pb.GetReceiptPublicKeyResponse{
+		KeyId:     rs.signer.KeyID(),
+		PublicKey: rs.signer.PublicKey(),
+	}, nil
 }
 
 func (rs *RandomGRPCServer) GetRandomInt64(ctx context.Context, req *pb.GetRandomInt64Request) (*pb.GetRandomInt64Response, error) {
@@ -78,34 +283,422 @@ func (rs *RandomGRPCServer) GetRandomInt64(ctx context.Context, req *pb.GetRando
 		return nil, fmt.Errorf("request is nil")
 	}
 
-	number, err := random.UniformInt64(req.Min, req.Max)
+	key, err := idempotencyKey(ctx, req.IdempotencyKey)
 	if err != nil {
 		return nil, err
 	}
 
+	number, replayed, err := idempotency.Do(ctx, rs.idempotency, "GetRandomInt64", key, []int32{req.Min, req.Max}, func() (int64, error) {
+		return random.UniformInt64(req.Min, req.Max)
+	})
+	if errors.Is(err, idempotency.ErrKeyReused) {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	} else if err != nil {
+		return nil, err
+	}
+
 	return &pb.GetRandomInt64Response{
-		Number: number,
+		Number:   number,
+		Replayed: replayed,
 	}, nil
 }
 
 func (rs *RandomGRPCServer) GetRandomFloat64(ctx context.Context, req *pb.GetRandomFloat64Request) (*pb.GetRandomFloat64Response, error) {
-	number, err := random.UniformFloat64()
+	if req == nil {
+		return nil, fmt.Errorf("request is nil")
+	}
+
+	key, err := idempotencyKey(ctx, req.IdempotencyKey)
 	if err != nil {
 		return nil, err
 	}
 
+	number, replayed, err := idempotency.Do(ctx, rs.idempotency, "GetRandomFloat64", key, nil, random.UniformFloat64)
+	if errors.Is(err, idempotency.ErrKeyReused) {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	} else if err != nil {
+		return nil, err
+	}
+
 	return &pb.GetRandomFloat64Response{
-		Number: number,
+		Number:   number,
+		Replayed: replayed,
 	}, nil
 }
 
 func (rs *RandomGRPCServer) GetDeterministicRandom(ctx context.Context, req *pb.GetDeterministicRandomRequest) (*pb.GetDeterministicRandomResponse, error) {
-	number, err := random.DeterministicRandom(rs.seed, req.Sequence, req.Probabilities)
+	if req == nil {
+		return nil, fmt.Errorf("request is nil")
+	}
+
+	var token string
+	md, _ := metadata.FromIncomingContext(ctx)
+	if values := md.Get(replayTokenKey); len(values) > 0 {
+		token = values[0]
+	}
+	replayClient := rs.replayClients.Allowed(token)
+
+	if rs.sequenceMode == sequence.ModeServer {
+		if !replayClient {
+			return nil, status.Error(codes.PermissionDenied, "sequences are allocated by the server, use DrawDeterministicRandom")
+		}
+		slog.Info("replaying deterministic random", "sequence", req.Sequence)
+	}
+
+	// Replay clients repeat draws on purpose, so single-use only applies to client chosen sequences
+	trackDraw := rs.registry.Enabled() && rs.sequenceMode == sequence.ModeClient
+	if trackDraw || len(req.Namespace) > 0 {
+		err := sequence.ValidateNamespace(req.Namespace)
+		if err != nil {
+			return nil, status.Error(codes.InvalidArgument, err.Error())
+		}
+	}
+
+	// An earlier key only replays, for a replay client or the draw recorded with it
+	key, err := rs.drawer.DrawKey(req.KeyId, req.Epoch, func(earlier keyring.Key) (bool, error) {
+		if replayClient || !trackDraw {
+			return replayClient, nil
+		}
+		return rs.registry.DrawnWith(ctx, req.Namespace, req.Sequence, earlier.ID)
+	})
+	if errors.Is(err, keyring.ErrKeyRetired) {
+		return nil, status.Error(codes.FailedPrecondition, err.Error())
+	} else if errors.Is(err, keyring.ErrUnknownKey) || errors.Is(err, keyring.ErrKeyNotActive) {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	} else if err != nil {
+		return nil, err
+	}
+
+	number, proof, err := rs.drawer.Draw(key, req.Namespace, req.Sequence, req.Probabilities)
 	if err != nil {
 		return nil, err
 	}
 
+	replayed := false
+	if trackDraw {
+		var draw registry.Draw
+		draw, replayed, err = rs.registry.Record(ctx, registry.Draw{
+			Namespace:     req.Namespace,
+			Sequence:      req.Sequence,
+			Probabilities: req.Probabilities,
+			Number:        number,
+			KeyID:         key.ID,
+			Epoch:         key.Epoch,
+			DrawnAt:       time.Now().UTC(),
+		})
+		if errors.Is(err, registry.ErrAlreadyDrawn) || errors.Is(err, registry.ErrProbabilitiesChanged) {
+			return nil, status.Error(codes.AlreadyExists, err.Error())
+		} else if err != nil {
+			return nil, err
+		}
+
+		// A replay returns the original draw, so also the key it was drawn with
+		if replayed && draw.KeyID != key.ID {
+			key, err = rs.drawer.Key(draw.KeyID, draw.Epoch)
+			if err != nil {
+				return nil, err
+			}
+			_, proof, err = rs.drawer.Draw(key, req.Namespace, req.Sequence, req.Probabilities)
+			if err != nil {
+				return nil, err
+			}
+		}
+		number = draw.Number
+	}
+
 	return &pb.GetDeterministicRandomResponse{
-		Number: number,
+		Number:   number,
+		Replayed: replayed,
+		VrfProof: proof,
+		KeyId:    key.ID,
+		Epoch:    key.Epoch,
+	}, nil
+}
+
+func (rs *RandomGRPCServer) DrawDeterministicRandom(ctx context.Context, req *pb.DrawDeterministicRandomRequest) (*pb.DrawDeterministicRandomResponse, error) {
+	if req == nil {
+		return nil, fmt.Errorf("request is nil")
+	}
+
+	key, err := rs.drawer.Key("", 0)
+	if err != nil {
+		return nil, err
+	}
+
+	// Validate before allocating so invalid requests do not burn sequence numbers
+	err = rs.drawer.Validate(key, 0, req.Probabilities)
+	if err != nil {
+		return nil, err
+	}
+
+	seq, err := rs.allocator.Next(ctx, req.Namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	number, proof, err := rs.drawer.Draw(key, req.Namespace, seq, req.Probabilities)
+	if err != nil {
+		return nil, err
+	}
+
+	return &pb.DrawDeterministicRandomResponse{
+		Sequence: seq,
+		Number:   number,
+		VrfProof: proof,
+		KeyId:    key.ID,
+		Epoch:    key.Epoch,
 	}, nil
 }
+
+func (rs *RandomGRPCServer) GetVRFPublicKey(ctx context.Context, req *pb.GetVRFPublicKeyRequest) (*pb.GetVRFPublicKeyResponse, error) {
+	if !rs.drawer.VRF() {
+		return nil, status.Error(codes.FailedPrecondition, "deterministic draws are not proven, set -deterministic-algorithm vrf")
+	}
+
+	key, err := rs.drawer.Key(req.KeyId, 0)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	publicKey, err := rs.drawer.PublicKey(key, req.Namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	return &pb.GetVRFPublicKeyResponse{
+		PublicKey: publicKey,
+		KeyId:     key.ID,
+		Epoch:     key.Epoch,
+	}, nil
+}
+
+func (rs *RandomGRPCServer) VerifyDeterministicRandom(ctx context.Context, req *pb.VerifyDeterministicRandomRequest) (*pb.VerifyDeterministicRandomResponse, error) {
+	if req == nil {
+		return nil, fmt.Errorf("request is nil")
+	}
+
+	err := rs.drawer.Verify(req.PublicKey, req.KeyId, req.Namespace, req.Sequence, req.Probabilities, req.Number, req.VrfProof)
+	if err != nil {
+		return &pb.VerifyDeterministicRandomResponse{
+			Valid: false,
+			Error: err.Error(),
+		}, nil
+	}
+
+	return &pb.VerifyDeterministicRandomResponse{
+		Valid: true,
+	}, nil
+}
+
+func (rs *RandomGRPCServer) ListSeedCommitments(ctx context.Context, req *pb.ListSeedCommitmentsRequest) (*pb.ListSeedCommitmentsResponse, error) {
+	if rs.commitments == nil {
+		return nil, status.Error(codes.FailedPrecondition, "seed commitments are not published, set -seed-commitments")
+	}
+
+	epochs, err := rs.commitments.Current(ctx, rs.drawer.Keyring())
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &pb.ListSeedCommitmentsResponse{}
+	for _, e := range epochs {
+		resp.Commitments = append(resp.Commitments, seedCommitment(e))
+	}
+	return resp, nil
+}
+
+func (rs *RandomGRPCServer) RevealSeed(ctx context.Context, req *pb.RevealSeedRequest) (*pb.RevealSeedResponse, error) {
+	if !rs.seedReveal {
+		return nil, status.Error(codes.FailedPrecondition, "seeds are not revealed, set -seed-reveal")
+	}
+
+	e, seedHex, err := rs.commitments.Reveal(ctx, rs.drawer.Keyring(), req.KeyId, req.Epoch)
+	if errors.Is(err, keyring.ErrUnknownKey) {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	} else if errors.Is(err, commitment.ErrNotExpired) {
+		return nil, status.Error(codes.FailedPrecondition, err.Error())
+	} else if err != nil {
+		return nil, err
+	}
+
+	return &pb.RevealSeedResponse{
+		Commitment: seedCommitment(e),
+		SeedHex:    seedHex,
+	}, nil
+}
+
+func (rs *RandomGRPCServer) GetCrashChain(ctx context.Context, req *pb.GetCrashChainRequest) (*pb.GetCrashChainResponse, error) {
+	if rs.crash == nil {
+		return nil, status.Error(codes.FailedPrecondition, "crash rounds are not served, set -crash-chain-file")
+	}
+
+	played, err := rs.crash.Played(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return &pb.GetCrashChainResponse{
+		TerminalHash: rs.crash.Terminal(),
+		Length:       rs.crash.Length(),
+		RoundsPlayed: played,
+		HouseEdge:    rs.crash.HouseEdge(),
+	}, nil
+}
+
+func (rs *RandomGRPCServer) NextCrashRound(ctx context.Context, req *pb.NextCrashRoundRequest) (*pb.NextCrashRoundResponse, error) {
+	if rs.crash == nil {
+		return nil, status.Error(codes.FailedPrecondition, "crash rounds are not served, set -crash-chain-file")
+	}
+
+	round, err := rs.crash.Next(ctx)
+	if errors.Is(err, hashchain.ErrExhausted) {
+		return nil, status.Error(codes.ResourceExhausted, err.Error())
+	} else if err != nil {
+		return nil, err
+	}
+
+	return &pb.NextCrashRoundResponse{
+		Round:        crashRound(round),
+		TerminalHash: rs.crash.Terminal(),
+	}, nil
+}
+
+func (rs *RandomGRPCServer) GetCrashRound(ctx context.Context, req *pb.GetCrashRoundRequest) (*pb.GetCrashRoundResponse, error) {
+	if rs.crash == nil {
+		return nil, status.Error(codes.FailedPrecondition, "crash rounds are not served, set -crash-chain-file")
+	}
+
+	round, err := rs.crash.Get(ctx, req.Round)
+	if errors.Is(err, crash.ErrNotPlayed) {
+		return nil, status.Error(codes.FailedPrecondition, err.Error())
+	} else if err != nil {
+		return nil, err
+	}
+
+	return &pb.GetCrashRoundResponse{
+		Round: crashRound(round),
+	}, nil
+}
+
+func crashRound(r crash.Round) *pb.CrashRound {
+	return &pb.CrashRound{
+		Round:      r.Number,
+		Seed:       r.Seed,
+		Multiplier: r.Multiplier,
+	}
+}
+
+func seedCommitment(e commitment.Epoch) *pb.SeedCommitment {
+	c := &pb.SeedCommitment{
+		KeyId:               e.KeyID,
+		Epoch:               e.Epoch,
+		Commitment:          e.Commitment.Commitment,
+		PublishedAtUnixNano: e.PublishedAt.UnixNano(),
+		Late:                e.Late,
+	}
+	// A key active since forever has no activation time
+	if !e.ActiveFrom.IsZero() {
+		c.ActiveFromUnixNano = e.ActiveFrom.UnixNano()
+	}
+	if !e.ActiveUntil.IsZero() {
+		c.ActiveUntilUnixNano = e.ActiveUntil.UnixNano()
+	}
+	return c
+}
+
+// idempotencyKey returns the key of the request field, or else of the metadata.
+// An invalid key is an InvalidArgument error.
+func idempotencyKey(ctx context.Context, fromRequest string) (string, error) {
+	key := fromRequest
+	if len(key) == 0 {
+		md, _ := metadata.FromIncomingContext(ctx)
+		if values := md.Get(idempotencyKeyKey); len(values) > 0 {
+			key = values[0]
+		}
+	}
+	if len(key) == 0 {
+		return "", nil
+	}
+
+	err := idempotency.ValidateKey(key)
+	if err != nil {
+		return "", status.Error(codes.InvalidArgument, err.Error())
+	}
+	return key, nil
+}
+
+// receiptInterceptor signs every successful draw and attaches the receipt to the response.
+// A draw that cannot be signed is not returned.
+func receiptInterceptor(signer *receipt.Signer) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		resp, err := handler(ctx, req)
+		if err != nil || !drawMethods[info.FullMethod] {
+			return resp, err
+		}
+
+		reqMessage, okReq := req.(proto.Message)
+		respMessage, okResp := resp.(proto.Message)
+		if !okReq || !okResp {
+			return nil, status.Error(codes.Internal, "failed to sign receipt")
+		}
+
+		_, err = signer.Attach(path.Base(info.FullMethod), reqMessage, respMessage)
+		if err != nil {
+			slog.Error("failed to sign receipt", "method", info.FullMethod, "error", err.Error())
+			return nil, status.Error(codes.Internal, "failed to sign receipt")
+		}
+
+		return resp, nil
+	}
+}
+
+// auditInterceptor appends every successful draw of the Random service to the audit log.
+// A draw that cannot be audited is not returned.
+func auditInterceptor(auditLog *audit.Log, drawer *deterministic.Drawer) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		resp, err := handler(ctx, req)
+		if err != nil || !drawMethods[info.FullMethod] {
+			return resp, err
+		}
+
+		reqMessage, okReq := req.(proto.Message)
+		respMessage, okResp := resp.(proto.Message)
+		if !okReq || !okResp {
+			return nil, status.Error(codes.Internal, "failed to audit draw")
+		}
+
+		entry, err := audit.NewEntry(clientID(ctx), path.Base(info.FullMethod), reqMessage, respMessage)
+		if err == nil {
+			entry.AlgorithmVersion = drawer.Version()
+			entry.SeedFingerprint = drawer.Fingerprint(keyID(respMessage))
+			_, err = auditLog.Append(entry)
+		}
+		if err != nil {
+			slog.Error("failed to audit draw", "method", info.FullMethod, "error", err.Error())
+			return nil, status.Error(codes.Internal, "failed to audit draw")
+		}
+
+		return resp, nil
+	}
+}
+
+// keyID returns the id of the seed a deterministic draw was made with, empty for other draws
+func keyID(resp proto.Message) string {
+	if r, ok := resp.(interface{ GetKeyId() string }); ok {
+		return r.GetKeyId()
+	}
+	return ""
+}
+
+// clientID returns the id the client sent in the metadata, or else its address
+func clientID(ctx context.Context) string {
+	md, _ := metadata.FromIncomingContext(ctx)
+	if values := md.Get(clientIDKey); len(values) > 0 {
+		return values[0]
+	}
+
+	if p, ok := peer.FromContext(ctx); ok && p.Addr != nil {
+		return p.Addr.String()
+	}
+	return ""
+}
@@ -1,10 +1,28 @@
 package main
 
 import (
+	"context"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
 	"fmt"
 	"github.com/fasttrack-solutions/random"
+	"github.com/fasttrack-solutions/random/internal/audit"
+	"github.com/fasttrack-solutions/random/internal/commitment"
 	"github.com/fasttrack-solutions/random/internal/config"
+	"github.com/fasttrack-solutions/random/internal/crash"
+	"github.com/fasttrack-solutions/random/internal/deterministic"
+	"github.com/fasttrack-solutions/random/internal/idempotency"
+	"github.com/fasttrack-solutions/random/internal/keyring"
+	"github.com/fasttrack-solutions/random/internal/registry"
+	"github.com/fasttrack-solutions/random/internal/sequence"
+	"github.com/fasttrack-solutions/random/internal/storage"
+	"github.com/fasttrack-solutions/random/internal/unseal"
+	"github.com/fasttrack-solutions/random/pkg/hashchain"
+	"github.com/fasttrack-solutions/random/pkg/pb"
+	"github.com/fasttrack-solutions/random/pkg/receipt"
 	"github.com/gin-gonic/gin"
+	"google.golang.org/protobuf/proto"
 	"log/slog"
 	"math"
 	"net/http"
@@ -14,11 +32,143 @@ import (
 )
 
 func main() {
-	seed := *config.SEEDHEX
-	if len(seed) != 64 {
-		panic("seed must be 64 hex characters")
-	} else if seed == "0000000000000000000000000000000000000000000000000000000000000000" {
-		panic("a unique seed value is required")
+	if (*config.SeedCommitments || *config.SeedReveal) && !storage.Persistent(*config.Store) {
+		panic(errors.New("-seed-commitments and -seed-reveal require a persistent -store, a file path or a redis address"))
+	}
+	if len(*config.CrashChainFile) > 0 && !storage.Persistent(*config.Store) {
+		// A restart would serve the rounds again, their seeds and crash points are already public
+		panic(errors.New("-crash-chain-file requires a persistent -store, a file path or a redis address"))
+	}
+
+	passphrase, errPassphrase := keyring.ReadPassphrase(*config.KeystorePassphraseFile, keyring.PassphraseEnv)
+	if errPassphrase != nil {
+		panic(errPassphrase)
+	}
+
+	seedSource := keyring.Source{
+		SeedHex:    *config.SEEDHEX,
+		SeedFile:   *config.SeedFile,
+		File:       *config.KeyringFile,
+		Dir:        *config.KeyringDir,
+		Keystore:   *config.KeystoreFile,
+		Passphrase: passphrase,
+	}
+	var ring *keyring.Keyring
+	var errRing error
+	if len(*config.SeedSharesAddr) > 0 {
+		// Dual control: the seed only exists in memory, combined from the shares of several operators
+		if seedSource.Reloadable() {
+			panic(errors.New("-seed-shares-addr can not be combined with a seed, keyring or keystore file"))
+		}
+		seedHex, errCollect := unseal.Collect(context.Background(), *config.SeedSharesAddr, *config.SeedSharesFingerprint)
+		if errCollect != nil {
+			panic(errCollect)
+		}
+		ring, errRing = keyring.Single(seedHex)
+	} else {
+		ring, errRing = seedSource.Load()
+	}
+	if errRing != nil {
+		panic(errRing)
+	}
+
+	sequenceMode := *config.SequenceMode
+	errMode := sequence.ValidateMode(sequenceMode)
+	if errMode != nil {
+		panic(errMode)
+	}
+
+	drawer, errDrawer := deterministic.New(ring, *config.DeterministicAlgorithm, *config.SeedDerivation)
+	if errDrawer != nil {
+		panic(errDrawer)
+	}
+
+	store, errStore := storage.Open(*config.Store)
+	if errStore != nil {
+		panic(errStore)
+	}
+	defer store.Close()
+
+	errMigrate := storage.Migrate(context.Background(), store, storage.Migrations)
+	if errMigrate != nil {
+		panic(errMigrate)
+	}
+
+	// Commitments lost on a restart would be published again, later than the draws they should precede
+	var commitments *commitment.Registry
+	if *config.SeedCommitments || *config.SeedReveal {
+		commitments = commitment.New(store)
+		errPublish := commitments.Publish(context.Background(), ring)
+		if errPublish != nil {
+			panic(errPublish)
+		}
+	}
+
+	// Rotate seeds without a restart when the key files change, a keyring replacing a committed seed is refused
+	if seedSource.Reloadable() {
+		go keyring.Watch(context.Background(), seedSource, *config.KeyringReloadInterval, func(r *keyring.Keyring) {
+			if commitments != nil {
+				errPublish := commitments.Publish(context.Background(), r)
+				if errPublish != nil {
+					slog.Error("failed to publish seed commitments, keeping the current keys", "error", errPublish.Error())
+					return
+				}
+			}
+			drawer.SetKeyring(r)
+			slog.Info("keyring reloaded", "keys", len(r.Keys()))
+		})
+	}
+
+	allocator := sequence.NewAllocator(store)
+
+	idempotencyCache, errIdempotency := idempotency.New(store, *config.IdempotencyTTL)
+	if errIdempotency != nil {
+		panic(errIdempotency)
+	}
+
+	replayClients := sequence.NewReplayClients(*config.ReplayTokens)
+
+	drawRegistry, errRegistry := registry.New(*config.SingleUse, store)
+	if errRegistry != nil {
+		panic(errRegistry)
+	}
+
+	// Replay clients repeat draws on purpose, so single-use only applies to client chosen sequences
+	trackDraws := drawRegistry.Enabled() && sequenceMode == sequence.ModeClient
+
+	var crashGame *crash.Game
+	if len(*config.CrashChainFile) > 0 {
+		var errCrash error
+		crashGame, errCrash = crash.Open(*config.CrashChainFile, *config.CrashHouseEdge, store)
+		if errCrash != nil {
+			panic(errCrash)
+		}
+		slog.Info("serving crash rounds", "terminalHash", hex.EncodeToString(crashGame.Terminal()), "length", crashGame.Length())
+	}
+
+	recorder := &drawRecorder{}
+	if len(*config.AuditDir) > 0 {
+		auditLog, errAudit := audit.Open(audit.Options{
+			Dir:            *config.AuditDir,
+			MaxSegmentSize: *config.AuditSegmentSize,
+			Fsync:          *config.AuditFsync,
+			FsyncInterval:  *config.AuditFsyncInterval,
+		})
+		if errAudit != nil {
+			panic(errAudit)
+		}
+		defer auditLog.Close()
+
+		recorder.log, recorder.drawer = auditLog, drawer
+	}
+
+	if len(*config.ReceiptKeyFile) > 0 {
+		signer, errSigner := receipt.LoadSigner(*config.ReceiptKeyFile)
+		if errSigner != nil {
+			panic(errSigner)
+		}
+		slog.Info("signing receipts", "keyId", signer.KeyID())
+		recorder.signer = signer
 	}
 
 	gin.SetMode(gin.ReleaseMode)
@@ -29,13 +179,43 @@ func main() {
 		c.String(http.StatusOK, "pong @ %s", time.Now().UTC().String())
 	})
 
+	ginEngine.GET("/receiptPublicKey", func(c *gin.Context) {
+		if recorder.signer == nil {
+			c.String(http.StatusNotFound, "receipts are not signed, set -receipt-key-file")
+			c.Abort()
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{
+			"keyId":     recorder.signer.KeyID(),
+			"publicKey": base64.StdEncoding.EncodeToString(recorder.signer.PublicKey()),
+		})
+	})
+
 	ginEngine.GET("/getRandomFloat64", func(c *gin.Context) {
-		number, errUniformFloat64 := random.UniformFloat64()
-		if errUniformFloat64 != nil {
+		key, ok := idempotencyKey(c)
+		if !ok {
+			return
+		}
+
+		number, replayed, errUniformFloat64 := idempotency.Do(c.Request.Context(), idempotencyCache, "GetRandomFloat64", key, nil, random.UniformFloat64)
+		if errors.Is(errUniformFloat64, idempotency.ErrKeyReused) {
+			c.String(http.StatusUnprocessableEntity, errUniformFloat64.Error())
+			c.Abort()
+			return
+		} else if errUniformFloat64 != nil {
 			c.String(http.StatusInternalServerError, fmt.Sprintf("error generating random float64: %s", errUniformFloat64))
 			c.Abort()
 			return
 		}
+		if !recorder.record(c, "GetRandomFloat64",
+			&pb.GetRandomFloat64Request{IdempotencyKey: key},
+			&pb.GetRandomFloat64Response{Number: number, Replayed: replayed},
+		) {
+			return
+		}
+		if len(key) > 0 {
+			c.Header("X-Replayed", strconv.FormatBool(replayed))
+		}
 		c.String(http.StatusOK, fmt.Sprintf("%v", number))
 	})
 
@@ -92,16 +272,56 @@ func main() {
 			}
 		}
 
-		number, errUniformInt64 := random.UniformInt64(minimum, maximum)
-		if errUniformInt64 != nil {
+		key, ok := idempotencyKey(c)
+		if !ok {
+			return
+		}
+
+		number, replayed, errUniformInt64 := idempotency.Do(c.Request.Context(), idempotencyCache, "GetRandomInt64", key, []int32{minimum, maximum}, func() (int64, error) {
+			return random.UniformInt64(minimum, maximum)
+		})
+		if errors.Is(errUniformInt64, idempotency.ErrKeyReused) {
+			c.String(http.StatusUnprocessableEntity, errUniformInt64.Error())
+			c.Abort()
+			return
+		} else if errUniformInt64 != nil {
 			c.String(http.StatusBadRequest, errUniformInt64.Error())
 			c.Abort()
 			return
 		}
+		if !recorder.record(c, "GetRandomInt64",
+			&pb.GetRandomInt64Request{Min: minimum, Max: maximum, IdempotencyKey: key},
+			&pb.GetRandomInt64Response{Number: number, Replayed: replayed},
+		) {
+			return
+		}
+		if len(key) > 0 {
+			c.Header("X-Replayed", strconv.FormatBool(replayed))
+		}
 		c.String(http.StatusOK, fmt.Sprintf("%v", number))
 	})
 
 	ginEngine.GET("/getDeterministicRandom", func(c *gin.Context) {
+		replayClient := replayClients.Allowed(c.GetHeader("X-Replay-Token"))
+		if sequenceMode == sequence.ModeServer {
+			if !replayClient {
+				c.String(http.StatusForbidden, "sequences are allocated by the server, use /drawDeterministicRandom")
+				c.Abort()
+				return
+			}
+			slog.Info("replaying deterministic random", "sequence", c.Query("s"))
+		}
+
+		namespace := c.Query("n")
+		if trackDraws || len(namespace) > 0 {
+			errNamespace := sequence.ValidateNamespace(namespace)
+			if errNamespace != nil {
+				c.String(http.StatusBadRequest, errNamespace.Error())
+				c.Abort()
+				return
+			}
+		}
+
 		sequence := int64(0)
 		sequenceAsStr := c.Query("s")
 		if len(sequenceAsStr) == 0 {
@@ -123,44 +343,353 @@ func main() {
 			}
 		}
 
-		var probabilities []float64
-		probabilitiesAsStr := c.Query("p")
-		if len(probabilitiesAsStr) == 0 {
-			c.String(http.StatusBadRequest, "probabilities are missing")
+		probabilities, ok := parseProbabilities(c)
+		if !ok {
+			return
+		}
+
+		// (k)ey id and/or (e)poch select the seed to replay a draw with
+		epoch := int64(0)
+		if epochAsStr := c.Query("e"); len(epochAsStr) > 0 {
+			epochAsNumber, errParseInt := strconv.ParseInt(epochAsStr, 10, 64)
+			if errParseInt != nil {
+				c.String(http.StatusBadRequest, "unable to parse epoch as number")
+				c.Abort()
+				return
+			}
+			epoch = epochAsNumber
+		}
+
+		// An earlier key only replays, for a replay client or the draw recorded with it
+		key, errKey := drawer.DrawKey(c.Query("k"), epoch, func(earlier keyring.Key) (bool, error) {
+			if replayClient || !trackDraws {
+				return replayClient, nil
+			}
+			return drawRegistry.DrawnWith(c.Request.Context(), namespace, sequence, earlier.ID)
+		})
+		if errors.Is(errKey, keyring.ErrKeyRetired) {
+			c.String(http.StatusForbidden, errKey.Error())
 			c.Abort()
 			return
-		} else if len(probabilitiesAsStr) > 300 {
-			c.String(http.StatusBadRequest, "string of probabilities must be less than 300 characters")
+		} else if errors.Is(errKey, keyring.ErrUnknownKey) || errors.Is(errKey, keyring.ErrKeyNotActive) {
+			c.String(http.StatusBadRequest, errKey.Error())
 			c.Abort()
 			return
-		} else {
-			probabilitiesAsStrList := strings.Split(probabilitiesAsStr, ",")
-			if len(probabilitiesAsStrList) == 0 {
-				c.String(http.StatusBadRequest, "invalid probabilities, use comma separated list (i.e. 0.01,0.09,0.9")
+		} else if errKey != nil {
+			c.String(http.StatusInternalServerError, errKey.Error())
+			c.Abort()
+			return
+		}
+
+		number, proof, errDraw := drawer.Draw(key, namespace, sequence, probabilities)
+		if errDraw != nil {
+			c.String(http.StatusBadRequest, errDraw.Error())
+			c.Abort()
+			return
+		}
+
+		replayed := false
+		if trackDraws {
+			var draw registry.Draw
+			var errRecord error
+			draw, replayed, errRecord = drawRegistry.Record(c.Request.Context(), registry.Draw{
+				Namespace:     namespace,
+				Sequence:      sequence,
+				Probabilities: probabilities,
+				Number:        number,
+				KeyID:         key.ID,
+				Epoch:         key.Epoch,
+				DrawnAt:       time.Now().UTC(),
+			})
+			if errors.Is(errRecord, registry.ErrAlreadyDrawn) || errors.Is(errRecord, registry.ErrProbabilitiesChanged) {
+				c.String(http.StatusConflict, errRecord.Error())
+				c.Abort()
+				return
+			} else if errRecord != nil {
+				c.String(http.StatusInternalServerError, fmt.Sprintf("error recording draw: %s", errRecord))
 				c.Abort()
 				return
 			}
 
-			for _, v := range probabilitiesAsStrList {
-				probability, errParse := strconv.ParseFloat(strings.TrimSpace(v), 64)
-				if errParse != nil {
-					c.String(http.StatusBadRequest, fmt.Sprintf("invalid probability: %s", strings.TrimSpace(v)))
+			// A replay returns the original draw, so also the key it was drawn with
+			if replayed && draw.KeyID != key.ID {
+				key, errKey = drawer.Key(draw.KeyID, draw.Epoch)
+				if errKey == nil {
+					_, proof, errDraw = drawer.Draw(key, namespace, sequence, probabilities)
+				}
+				if errKey != nil || errDraw != nil {
+					c.String(http.StatusInternalServerError, fmt.Sprintf("error replaying draw: %s", errors.Join(errKey, errDraw)))
 					c.Abort()
 					return
 				}
-				probabilities = append(probabilities, probability)
 			}
+			number = draw.Number
 		}
 
-		number, errDeterministicRandom := random.DeterministicRandom(seed, sequence, probabilities)
-		if errDeterministicRandom != nil {
-			c.String(http.StatusBadRequest, errDeterministicRandom.Error())
-			c.Abort()
+		if !recorder.record(c, "GetDeterministicRandom",
+			&pb.GetDeterministicRandomRequest{Sequence: sequence, Probabilities: probabilities, Namespace: namespace, KeyId: c.Query("k"), Epoch: epoch},
+			&pb.GetDeterministicRandomResponse{Number: number, Replayed: replayed, VrfProof: proof, KeyId: key.ID, Epoch: key.Epoch},
+		) {
 			return
 		}
+		if trackDraws {
+			c.Header("X-Replayed", strconv.FormatBool(replayed))
+		}
+		c.Header("X-Key-Id", key.ID)
+		c.Header("X-Key-Epoch", strconv.FormatInt(key.Epoch, 10))
+		if len(proof) > 0 {
+			c.Header("X-VRF-Proof", hex.EncodeToString(proof))
+		}
 		c.String(http.StatusOK, fmt.Sprintf("%v", number))
 	})
 
+	ginEngine.POST("/drawDeterministicRandom", func(c *gin.Context) {
+		namespace := c.Query("n")
+		errNamespace := sequence.ValidateNamespace(namespace)
+		if errNamespace != nil {
+			c.String(http.StatusBadRequest, errNamespace.Error())
+			c.Abort()
+			return
+		}
+
+		probabilities, ok := parseProbabilities(c)
+		if !ok {
+			return
+		}
+
+		key, errKey := drawer.Key("", 0)
+		if errKey != nil {
+			c.String(http.StatusInternalServerError, errKey.Error())
+			c.Abort()
+			return
+		}
+
+		// Validate before allocating so invalid requests do not burn sequence numbers
+		errValidate := drawer.Validate(key, 0, probabilities)
+		if errValidate != nil {
+			c.String(http.StatusBadRequest, errValidate.Error())
+			c.Abort()
+			return
+		}
+
+		seq, errNext := allocator.Next(c.Request.Context(), namespace)
+		if errNext != nil {
+			c.String(http.StatusInternalServerError, fmt.Sprintf("error allocating sequence: %s", errNext))
+			c.Abort()
+			return
+		}
+
+		number, proof, errDraw := drawer.Draw(key, namespace, seq, probabilities)
+		if errDraw != nil {
+			c.String(http.StatusBadRequest, errDraw.Error())
+			c.Abort()
+			return
+		}
+
+		if !recorder.record(c, "DrawDeterministicRandom",
+			&pb.DrawDeterministicRandomRequest{Namespace: namespace, Probabilities: probabilities},
+			&pb.DrawDeterministicRandomResponse{Sequence: seq, Number: number, VrfProof: proof, KeyId: key.ID, Epoch: key.Epoch},
+		) {
+			return
+		}
+		response := gin.H{"sequence": seq, "number": number, "keyId": key.ID, "epoch": key.Epoch}
+		if len(proof) > 0 {
+			response["proof"] = hex.EncodeToString(proof)
+		}
+		c.JSON(http.StatusOK, response)
+	})
+
+	ginEngine.GET("/vrfPublicKey", func(c *gin.Context) {
+		if !drawer.VRF() {
+			c.String(http.StatusNotFound, "deterministic draws are not proven, set -deterministic-algorithm vrf")
+			c.Abort()
+			return
+		}
+		key, errKey := drawer.Key(c.Query("k"), 0)
+		if errKey != nil {
+			c.String(http.StatusBadRequest, errKey.Error())
+			c.Abort()
+			return
+		}
+		publicKey, errPublicKey := drawer.PublicKey(key, c.Query("n"))
+		if errPublicKey != nil {
+			c.String(http.StatusInternalServerError, errPublicKey.Error())
+			c.Abort()
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"publicKey": hex.EncodeToString(publicKey), "keyId": key.ID, "epoch": key.Epoch})
+	})
+
+	ginEngine.GET("/seedCommitments", func(c *gin.Context) {
+		if commitments == nil {
+			c.String(http.StatusNotFound, "seed commitments are not published, set -seed-commitments")
+			c.Abort()
+			return
+		}
+
+		epochs, errCurrent := commitments.Current(c.Request.Context(), drawer.Keyring())
+		if errCurrent != nil {
+			c.String(http.StatusInternalServerError, errCurrent.Error())
+			c.Abort()
+			return
+		}
+		list := make([]gin.H, len(epochs))
+		for i, e := range epochs {
+			list[i] = seedCommitment(e)
+		}
+		c.JSON(http.StatusOK, gin.H{"commitments": list})
+	})
+
+	ginEngine.GET("/revealSeed", func(c *gin.Context) {
+		if !*config.SeedReveal {
+			c.String(http.StatusNotFound, "seeds are not revealed, set -seed-reveal")
+			c.Abort()
+			return
+		}
+
+		epoch := int64(0)
+		if epochAsStr := c.Query("e"); len(epochAsStr) > 0 {
+			epochAsNumber, errParseInt := strconv.ParseInt(epochAsStr, 10, 64)
+			if errParseInt != nil {
+				c.String(http.StatusBadRequest, "unable to parse epoch as number")
+				c.Abort()
+				return
+			}
+			epoch = epochAsNumber
+		}
+
+		e, seedHex, errReveal := commitments.Reveal(c.Request.Context(), drawer.Keyring(), c.Query("k"), epoch)
+		if errors.Is(errReveal, keyring.ErrUnknownKey) {
+			c.String(http.StatusBadRequest, errReveal.Error())
+			c.Abort()
+			return
+		} else if errors.Is(errReveal, commitment.ErrNotExpired) {
+			c.String(http.StatusForbidden, errReveal.Error())
+			c.Abort()
+			return
+		} else if errReveal != nil {
+			c.String(http.StatusInternalServerError, errReveal.Error())
+			c.Abort()
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"commitment": seedCommitment(e), "seedHex": seedHex})
+	})
+
+	ginEngine.GET("/crashChain", func(c *gin.Context) {
+		if crashGame == nil {
+			c.String(http.StatusNotFound, "crash rounds are not served, set -crash-chain-file")
+			c.Abort()
+			return
+		}
+		played, errPlayed := crashGame.Played(c.Request.Context())
+		if errPlayed != nil {
+			c.String(http.StatusInternalServerError, errPlayed.Error())
+			c.Abort()
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{
+			"terminalHash": hex.EncodeToString(crashGame.Terminal()),
+			"length":       crashGame.Length(),
+			"roundsPlayed": played,
+			"houseEdge":    crashGame.HouseEdge(),
+		})
+	})
+
+	ginEngine.POST("/nextCrashRound", func(c *gin.Context) {
+		if crashGame == nil {
+			c.String(http.StatusNotFound, "crash rounds are not served, set -crash-chain-file")
+			c.Abort()
+			return
+		}
+		round, errNext := crashGame.Next(c.Request.Context())
+		if errors.Is(errNext, hashchain.ErrExhausted) {
+			c.String(http.StatusConflict, errNext.Error())
+			c.Abort()
+			return
+		} else if errNext != nil {
+			c.String(http.StatusInternalServerError, errNext.Error())
+			c.Abort()
+			return
+		}
+		drawn := &pb.NextCrashRoundResponse{
+			Round:        &pb.CrashRound{Round: round.Number, Seed: round.Seed, Multiplier: round.Multiplier},
+			TerminalHash: crashGame.Terminal(),
+		}
+		if !recorder.record(c, "NextCrashRound", &pb.NextCrashRoundRequest{}, drawn) {
+			return
+		}
+		response := crashRound(round)
+		response["terminalHash"] = hex.EncodeToString(drawn.TerminalHash)
+		c.JSON(http.StatusOK, response)
+	})
+
+	ginEngine.GET("/crashRound", func(c *gin.Context) {
+		if crashGame == nil {
+			c.String(http.StatusNotFound, "crash rounds are not served, set -crash-chain-file")
+			c.Abort()
+			return
+		}
+		number, errRound := strconv.ParseInt(c.Query("r"), 10, 64)
+		if errRound != nil {
+			c.String(http.StatusBadRequest, "unable to parse round as number")
+			c.Abort()
+			return
+		}
+		round, errGet := crashGame.Get(c.Request.Context(), number)
+		if errors.Is(errGet, crash.ErrNotPlayed) {
+			c.String(http.StatusForbidden, errGet.Error())
+			c.Abort()
+			return
+		} else if errGet != nil {
+			c.String(http.StatusInternalServerError, errGet.Error())
+			c.Abort()
+			return
+		}
+		c.JSON(http.StatusOK, crashRound(round))
+	})
+
+	ginEngine.GET("/verifyDeterministicRandom", func(c *gin.Context) {
+		sequence, errSequence := strconv.ParseInt(c.Query("s"), 10, 64)
+		if errSequence != nil {
+			c.String(http.StatusBadRequest, "unable to parse sequence as number")
+			c.Abort()
+			return
+		}
+
+		number, errNumber := strconv.ParseInt(c.Query("number"), 10, 64)
+		if errNumber != nil {
+			c.String(http.StatusBadRequest, "unable to parse number as number")
+			c.Abort()
+			return
+		}
+
+		proof, errProof := hex.DecodeString(c.Query("proof"))
+		if errProof != nil {
+			c.String(http.StatusBadRequest, "proof must be hex")
+			c.Abort()
+			return
+		}
+
+		publicKey, errPublicKey := hex.DecodeString(c.Query("publicKey"))
+		if errPublicKey != nil {
+			c.String(http.StatusBadRequest, "publicKey must be hex")
+			c.Abort()
+			return
+		}
+
+		probabilities, ok := parseProbabilities(c)
+		if !ok {
+			return
+		}
+
+		errVerify := drawer.Verify(publicKey, c.Query("k"), c.Query("n"), sequence, probabilities, number, proof)
+		if errVerify != nil {
+			c.JSON(http.StatusOK, gin.H{"valid": false, "error": errVerify.Error()})
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"valid": true})
+	})
+
 	// start server
 	slog.Info(fmt.Sprintf("http server listening on %v", *config.HTTPPort))
 	errRun := ginEngine.Run(fmt.Sprintf(":%v", *config.HTTPPort))
@@ -168,3 +697,142 @@ func main() {
 		panic(errRun)
 	}
 }
+
+// parseProbabilities reads the comma separated (p)robabilities from the querystring.
+// On failure the response is written and false is returned.
+func parseProbabilities(c *gin.Context) ([]float64, bool) {
+	var probabilities []float64
+	probabilitiesAsStr := c.Query("p")
+	if len(probabilitiesAsStr) == 0 {
+		c.String(http.StatusBadRequest, "probabilities are missing")
+		c.Abort()
+		return nil, false
+	} else if len(probabilitiesAsStr) > 300 {
+		c.String(http.StatusBadRequest, "string of probabilities must be less than 300 characters")
+		c.Abort()
+		return nil, false
+	} else {
+		probabilitiesAsStrList := strings.Split(probabilitiesAsStr, ",")
+		if len(probabilitiesAsStrList) == 0 {
+			c.String(http.StatusBadRequest, "invalid probabilities, use comma separated list (i.e. 0.01,0.09,0.9")
+			c.Abort()
+			return nil, false
+		}
+
+		for _, v := range probabilitiesAsStrList {
+			probability, errParse := strconv.ParseFloat(strings.TrimSpace(v), 64)
+			if errParse != nil {
+				c.String(http.StatusBadRequest, fmt.Sprintf("invalid probability: %s", strings.TrimSpace(v)))
+				c.Abort()
+				return nil, false
+			}
+			probabilities = append(probabilities, probability)
+		}
+	}
+
+	return probabilities, true
+}
+
+// crashRound is the JSON of a crash round, the multiplier is in hundredths
+func crashRound(r crash.Round) gin.H {
+	return gin.H{
+		"round":      r.Number,
+		"seed":       hex.EncodeToString(r.Seed),
+		"multiplier": r.Multiplier,
+	}
+}
+
+// seedCommitment is the JSON of a commitment, the times are left out when they are not set
+func seedCommitment(e commitment.Epoch) gin.H {
+	h := gin.H{
+		"keyId":       e.KeyID,
+		"epoch":       e.Epoch,
+		"commitment":  e.Commitment.Commitment,
+		"publishedAt": e.PublishedAt,
+		"late":        e.Late,
+	}
+	if !e.ActiveFrom.IsZero() {
+		h["activeFrom"] = e.ActiveFrom
+	}
+	if !e.ActiveUntil.IsZero() {
+		h["activeUntil"] = e.ActiveUntil
+	}
+	return h
+}
+
+// idempotencyKey reads the optional Idempotency-Key header.
+// On failure the response is written and false is returned.
+func idempotencyKey(c *gin.Context) (string, bool) {
+	key := c.GetHeader("Idempotency-Key")
+	if len(key) == 0 {
+		return "", true
+	}
+
+	errKey := idempotency.ValidateKey(key)
+	if errKey != nil {
+		c.String(http.StatusBadRequest, errKey.Error())
+		c.Abort()
+		return "", false
+	}
+	return key, true
+}
+
+// keyID returns the id of the seed a deterministic draw was made with, empty for other draws
+func keyID(resp proto.Message) string {
+	if r, ok := resp.(interface{ GetKeyId() string }); ok {
+		return r.GetKeyId()
+	}
+	return ""
+}
+
+// drawRecorder signs the draws served over HTTP and appends them to the audit log,
+// using the gRPC messages so receipts and entries look the same for both servers.
+// Either step is skipped when it is disabled.
+type drawRecorder struct {
+	log    *audit.Log
+	drawer *deterministic.Drawer
+	signer *receipt.Signer
+}
+
+// record signs and audits the draw, the receipt is sent in the X-Receipt header.
+// On failure the response is written and false is returned, a draw that cannot be
+// signed or audited is not returned.
+func (r *drawRecorder) record(c *gin.Context, rpc string, req proto.Message, resp proto.Message) bool {
+	if r.signer != nil {
+		signed, err := r.signer.Attach(rpc, req, resp)
+		var encoded string
+		if err == nil {
+			encoded, err = receipt.Encode(signed)
+		}
+		if err != nil {
+			slog.Error("failed to sign receipt", "rpc", rpc, "error", err.Error())
+			c.String(http.StatusInternalServerError, "failed to sign receipt")
+			c.Abort()
+			return false
+		}
+		c.Header("X-Receipt", encoded)
+	}
+
+	if r.log == nil {
+		return true
+	}
+
+	client := c.GetHeader("X-Client-Id")
+	if len(client) == 0 {
+		client = c.ClientIP()
+	}
+
+	entry, err := audit.NewEntry(client, rpc, req, resp)
+	if err == nil {
+		entry.AlgorithmVersion = r.drawer.Version()
+		entry.SeedFingerprint = r.drawer.Fingerprint(keyID(resp))
+		_, err = r.log.Append(entry)
+	}
+	if err != nil {
+		slog.Error("failed to audit draw", "rpc", rpc, "error", err.Error())
+		c.String(http.StatusInternalServerError, "failed to audit draw")
+		c.Abort()
+		return false
+	}
+	return true
+}
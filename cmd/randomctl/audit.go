@@ -0,0 +1,142 @@
+package main
+
+import (
+	"cmp"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/fasttrack-solutions/random/internal/audit"
+	"github.com/fasttrack-solutions/random/internal/keyring"
+)
+
+func auditVerify(args []string) error {
+	fs := flag.NewFlagSet("audit verify", flag.ContinueOnError)
+	dir := fs.String("dir", "", "Directory of the audit log")
+	seedHex := fs.String("seed-hex", os.Getenv("SEED_HEX"), "Seed to recompute deterministic outcomes with, defaults to $SEED_HEX; only the chain is verified if empty")
+	keyringFile := fs.String("keyring-file", "", "Keyring file whose seeds recompute deterministic outcomes, in addition to -seed-hex")
+	keyringDir := fs.String("keyring-dir", "", "Directory of key files whose seeds recompute deterministic outcomes, in addition to -seed-hex")
+	keystoreFile := fs.String("keystore-file", "", "Keystore whose seeds recompute deterministic outcomes, in addition to -seed-hex")
+	passphraseFile := fs.String("keystore-passphrase-file", "", "File holding the keystore passphrase, read from $"+keyring.PassphraseEnv+" if empty")
+	crashHouseEdge := fs.Float64("crash-house-edge", 0.01, "House edge of the crash game, to recompute the crash points of crash rounds")
+	expectEntries := fs.Uint64("expect-entries", 0, "Number of entries the log held when it was last verified, the log must still hold them")
+	expectLastHash := fs.String("expect-last-hash", "", "Last hash of the log when it was last verified, the log must still hold it")
+	maxProblems := fs.Int("max-problems", 100, "Number of problems to list, 0 lists all")
+	err := fs.Parse(args)
+	if err != nil {
+		return err
+	} else if len(*dir) == 0 {
+		return errors.New("-dir is required")
+	}
+
+	var seeds []string
+	if len(*seedHex) > 0 {
+		seeds = append(seeds, *seedHex)
+	}
+	source := keyring.Source{File: *keyringFile, Dir: *keyringDir, Keystore: *keystoreFile}
+	if source.Reloadable() {
+		source.Passphrase, err = keyring.ReadPassphrase(*passphraseFile, keyring.PassphraseEnv)
+		if err != nil {
+			return err
+		}
+		ring, errRing := source.Load()
+		if errRing != nil {
+			return errRing
+		}
+		for _, k := range ring.Keys() {
+			seeds = append(seeds, k.SeedHex)
+		}
+	}
+
+	report, err := audit.Verify(*dir, audit.VerifyOptions{
+		Seeds:          seeds,
+		CrashHouseEdge: *crashHouseEdge,
+		MaxProblems:    *maxProblems,
+		ExpectEntries:  *expectEntries,
+		ExpectLastHash: *expectLastHash,
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, problem := range report.Problems {
+		fmt.Println(problem)
+	}
+	if report.MoreProblems > 0 {
+		fmt.Printf("... and %v more problems\n", report.MoreProblems)
+	}
+
+	fmt.Printf("segments: %v, entries: %v, recomputed: %v, crash rounds: %v, last hash: %s\n", report.Segments, report.Entries, report.Recomputed, report.CrashRounds, report.LastHash)
+	if len(seeds) == 0 {
+		fmt.Println("no seed set, deterministic outcomes were not recomputed")
+	}
+
+	if !report.OK() {
+		return fmt.Errorf("audit log verification failed with %v problems", uint64(len(report.Problems))+report.MoreProblems)
+	}
+	fmt.Println("audit log verified")
+	return nil
+}
+
+func auditExport(args []string) error {
+	fs := flag.NewFlagSet("audit export", flag.ContinueOnError)
+	dir := fs.String("dir", "", "Directory of the audit log")
+	format := fs.String("format", audit.FormatCSV, "Export format: csv or json")
+	out := fs.String("out", "", "File to write to, stdout if empty")
+	namespace := fs.String("namespace", "", "Only export draws of this namespace")
+	from := fs.String("from", "", "Only export draws at or after this RFC 3339 time")
+	to := fs.String("to", "", "Only export draws before this RFC 3339 time")
+	fromSequence := fs.Int64("from-sequence", -1, "Only export deterministic draws with a sequence of at least this")
+	toSequence := fs.Int64("to-sequence", -1, "Only export deterministic draws with a sequence of at most this")
+	err := fs.Parse(args)
+	if err != nil {
+		return err
+	} else if len(*dir) == 0 {
+		return errors.New("-dir is required")
+	}
+
+	filter := audit.Filter{Namespace: *namespace}
+	if len(*from) > 0 {
+		filter.From, err = time.Parse(time.RFC3339, *from)
+		if err != nil {
+			return fmt.Errorf("invalid -from: %w", err)
+		}
+	}
+	if len(*to) > 0 {
+		filter.To, err = time.Parse(time.RFC3339, *to)
+		if err != nil {
+			return fmt.Errorf("invalid -to: %w", err)
+		}
+	}
+	if *fromSequence >= 0 {
+		filter.FromSequence = fromSequence
+	}
+	if *toSequence >= 0 {
+		filter.ToSequence = toSequence
+	}
+
+	var f *os.File
+	var w io.Writer = os.Stdout
+	if len(*out) > 0 {
+		f, err = os.Create(filepath.Clean(*out))
+		if err != nil {
+			return err
+		}
+		w = f
+	}
+
+	count, err := audit.Export(*dir, filter, *format, w)
+	if f != nil {
+		err = cmp.Or(err, f.Close())
+	}
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(os.Stderr, "exported %v draws\n", count)
+	return nil
+}
@@ -0,0 +1,112 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/fasttrack-solutions/random"
+	"github.com/fasttrack-solutions/random/internal/bitstream"
+)
+
+func bitstreamExport(args []string) error {
+	fs := flag.NewFlagSet("bitstream export", flag.ContinueOnError)
+	source := fs.String("source", bitstream.SourceCrypto, "Bits to export: crypto (crypto/rand) or deterministic (the hash stream of a seed)")
+	seedFile := fs.String("seed-file", "", "File holding the hex seed of -source deterministic, $SEED_HEX is used if empty")
+	start := fs.Int64("start", 0, "First sequence number of -source deterministic")
+	format := fs.String("format", bitstream.FormatRaw, "Output format: raw (bytes), u32 (little-endian 32-bit words) or dieharder (ASCII input of dieharder -g 202)")
+	size := fs.String("size", "", "Bytes to export with an optional K, M or G suffix (powers of 1024), endless if empty except for -format dieharder")
+	out := fs.String("out", "-", "File to write to, - for stdout")
+	workers := fs.Int("workers", runtime.GOMAXPROCS(0), "Number of workers hashing -source deterministic")
+	err := fs.Parse(args)
+	if err != nil {
+		return err
+	} else if *workers < 1 {
+		return errors.New("-workers must be at least 1")
+	}
+
+	bytesToExport, err := parseSize(*size)
+	if err != nil {
+		return err
+	}
+
+	var r io.Reader
+	var generator string
+	switch *source {
+	case bitstream.SourceDeterministic:
+		seedHex, errSeed := readSeed(*seedFile)
+		if errSeed != nil {
+			return errSeed
+		}
+		fingerprint, errSeed := random.SeedFingerprint(seedHex)
+		if errSeed != nil {
+			return errSeed
+		}
+		p, errStream := bitstream.NewParallel(seedHex, *start, *workers)
+		if errStream != nil {
+			return errStream
+		}
+		defer p.Close()
+		r = p
+		generator = fmt.Sprintf("deterministic seed %s from sequence %v", fingerprint, *start)
+	default:
+		r, err = bitstream.Open(*source, "", 0)
+		if err != nil {
+			return err
+		}
+		generator = "crypto/rand"
+	}
+
+	w := io.Writer(os.Stdout)
+	if *out != "-" {
+		f, errCreate := os.Create(filepath.Clean(*out))
+		if errCreate != nil {
+			return errCreate
+		}
+		defer f.Close()
+		w = f
+	}
+
+	began := time.Now()
+	n, err := bitstream.Export(w, r, *format, bytesToExport, generator)
+	if err != nil {
+		return err
+	}
+	elapsed := time.Since(began).Seconds()
+	fmt.Fprintf(os.Stderr, "exported %v bytes in %.1fs (%.0f MiB/s)\n", n, elapsed, float64(n)/(1<<20)/max(elapsed, 1e-9))
+	return nil
+}
+
+// parseSize parses a number of bytes with an optional K, M or G suffix, an empty size is 0
+func parseSize(size string) (int64, error) {
+	if len(size) == 0 {
+		return 0, nil
+	}
+
+	unit := int64(1)
+	switch strings.ToUpper(size[len(size)-1:]) {
+	case "K":
+		unit = 1 << 10
+	case "M":
+		unit = 1 << 20
+	case "G":
+		unit = 1 << 30
+	}
+	if unit > 1 {
+		size = size[:len(size)-1]
+	}
+
+	n, err := strconv.ParseInt(size, 10, 64)
+	if err != nil || n < 1 || n > math.MaxInt64/unit {
+		return 0, fmt.Errorf("invalid size %q", size)
+	}
+	return n * unit, nil
+}
@@ -0,0 +1,81 @@
+package main
+
+import (
+	"bytes"
+	"encoding/hex"
+	"errors"
+	"flag"
+	"fmt"
+	"time"
+
+	"github.com/fasttrack-solutions/random/pkg/hashchain"
+)
+
+func chainGenerate(args []string) error {
+	fs := flag.NewFlagSet("chain generate", flag.ContinueOnError)
+	out := fs.String("out", "", "File to write the chain to, it is as secret as a seed")
+	length := fs.Int64("length", 1_000_000, "Number of rounds")
+	interval := fs.Int64("checkpoint-interval", 1000, "Every how many links a checkpoint is kept, more take more space and fewer more hashing per round")
+	err := fs.Parse(args)
+	if err != nil {
+		return err
+	} else if len(*out) == 0 {
+		return errors.New("-out is required")
+	}
+
+	start := time.Now()
+	chain, err := hashchain.Generate(*length, *interval)
+	if err != nil {
+		return err
+	}
+
+	var buf bytes.Buffer
+	_, err = chain.WriteTo(&buf)
+	if err != nil {
+		return err
+	}
+	err = writeSecret(*out, buf.Bytes(), false)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("generated %v links in %s, %v bytes\n", chain.Length(), time.Since(start).Round(time.Millisecond), buf.Len())
+	fmt.Printf("terminal hash: %s\n", hex.EncodeToString(chain.Terminal()))
+	return nil
+}
+
+func chainVerify(args []string) error {
+	fs := flag.NewFlagSet("chain verify", flag.ContinueOnError)
+	terminal := fs.String("terminal", "", "Published hex terminating hash of the chain")
+	round := fs.Int64("round", 0, "Number of the round")
+	seedHex := fs.String("seed", "", "Hex seed of the round")
+	houseEdge := fs.Float64("house-edge", 0.01, "House edge of the game, to recompute the crash point")
+	err := fs.Parse(args)
+	if err != nil {
+		return err
+	} else if len(*terminal) == 0 || len(*seedHex) == 0 || *round == 0 {
+		return errors.New("-terminal, -round and -seed are required")
+	}
+
+	terminalHash, err := hex.DecodeString(*terminal)
+	if err != nil {
+		return errors.New("invalid terminating hash")
+	}
+	seed, err := hex.DecodeString(*seedHex)
+	if err != nil {
+		return errors.New("invalid seed")
+	}
+
+	err = hashchain.Verify(terminalHash, *round, seed)
+	if err != nil {
+		return err
+	}
+	multiplier, err := hashchain.CrashMultiplier(seed, *houseEdge)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("crash point: %v.%02dx\n", multiplier/100, multiplier%100)
+	fmt.Printf("round %v verified against the terminating hash\n", *round)
+	return nil
+}
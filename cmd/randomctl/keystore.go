@@ -0,0 +1,180 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/fasttrack-solutions/random/internal/keyring"
+)
+
+// newPassphraseEnv is the environment variable the new passphrase of keystore reencrypt is read from
+const newPassphraseEnv = "KEYSTORE_NEW_PASSPHRASE"
+
+func keystoreCreate(args []string) error {
+	fs := flag.NewFlagSet("keystore create", flag.ContinueOnError)
+	out := fs.String("out", "", "File to write the keystore to")
+	seedFile := fs.String("seed-file", "", "File holding the hex seed to encrypt, $SEED_HEX is used if no file is set")
+	keyringFile := fs.String("keyring-file", "", "Keyring file to encrypt")
+	keyringDir := fs.String("keyring-dir", "", "Directory of key files to encrypt")
+	passphraseFile := fs.String("passphrase-file", "", "File holding the passphrase, read from $"+keyring.PassphraseEnv+" if empty")
+	params := scryptFlags(fs)
+	err := fs.Parse(args)
+	if err != nil {
+		return err
+	} else if len(*out) == 0 {
+		return errors.New("-out is required")
+	}
+
+	ring, err := keyring.Source{
+		SeedHex:  os.Getenv("SEED_HEX"),
+		SeedFile: *seedFile,
+		File:     *keyringFile,
+		Dir:      *keyringDir,
+	}.Load()
+	if err != nil {
+		return err
+	}
+
+	passphrase, err := keyring.ReadPassphrase(*passphraseFile, keyring.PassphraseEnv)
+	if err != nil {
+		return err
+	}
+
+	data, err := keyring.EncryptKeystore(ring.Keys(), passphrase, *params)
+	if err != nil {
+		return err
+	}
+
+	err = writeSecret(*out, data, false)
+	if err != nil {
+		return err
+	}
+
+	printKeys(ring)
+	return nil
+}
+
+func keystoreInspect(args []string) error {
+	fs := flag.NewFlagSet("keystore inspect", flag.ContinueOnError)
+	in := fs.String("in", "", "Keystore file")
+	passphraseFile := fs.String("passphrase-file", "", "File holding the passphrase, read from $"+keyring.PassphraseEnv+" if empty")
+	err := fs.Parse(args)
+	if err != nil {
+		return err
+	} else if len(*in) == 0 {
+		return errors.New("-in is required")
+	}
+
+	passphrase, err := keyring.ReadPassphrase(*passphraseFile, keyring.PassphraseEnv)
+	if err != nil {
+		return err
+	}
+
+	ring, err := keyring.LoadKeystore(*in, passphrase)
+	if err != nil {
+		return err
+	}
+
+	printKeys(ring)
+	return nil
+}
+
+func keystoreReencrypt(args []string) error {
+	fs := flag.NewFlagSet("keystore reencrypt", flag.ContinueOnError)
+	in := fs.String("in", "", "Keystore file")
+	out := fs.String("out", "", "File to write the keystore to, replaces -in if empty")
+	passphraseFile := fs.String("passphrase-file", "", "File holding the current passphrase, read from $"+keyring.PassphraseEnv+" if empty")
+	newPassphraseFile := fs.String("new-passphrase-file", "", "File holding the new passphrase, read from $"+newPassphraseEnv+" if empty")
+	params := scryptFlags(fs)
+	err := fs.Parse(args)
+	if err != nil {
+		return err
+	} else if len(*in) == 0 {
+		return errors.New("-in is required")
+	}
+
+	passphrase, err := keyring.ReadPassphrase(*passphraseFile, keyring.PassphraseEnv)
+	if err != nil {
+		return err
+	}
+	newPassphrase, err := keyring.ReadPassphrase(*newPassphraseFile, newPassphraseEnv)
+	if err != nil {
+		return err
+	}
+
+	ring, err := keyring.LoadKeystore(*in, passphrase)
+	if err != nil {
+		return err
+	}
+
+	data, err := keyring.EncryptKeystore(ring.Keys(), newPassphrase, *params)
+	if err != nil {
+		return err
+	}
+
+	if len(*out) == 0 {
+		err = writeSecret(*in, data, true)
+	} else {
+		err = writeSecret(*out, data, false)
+	}
+	if err != nil {
+		return err
+	}
+
+	printKeys(ring)
+	return nil
+}
+
+func scryptFlags(fs *flag.FlagSet) *keyring.KeystoreParams {
+	params := keyring.DefaultKeystoreParams
+	fs.IntVar(&params.N, "scrypt-n", params.N, "scrypt cost parameter, a power of two")
+	fs.IntVar(&params.R, "scrypt-r", params.R, "scrypt block size")
+	fs.IntVar(&params.P, "scrypt-p", params.P, "scrypt parallelization")
+	return &params
+}
+
+// printKeys lists the keys by fingerprint, seeds are never printed
+func printKeys(ring *keyring.Keyring) {
+	for _, k := range ring.Keys() {
+		activeFrom := "always"
+		if !k.ActiveFrom.IsZero() {
+			activeFrom = k.ActiveFrom.Format(time.RFC3339)
+		}
+		fmt.Printf("id: %s, epoch: %v, active from: %s, fingerprint: %s\n", k.ID, k.Epoch, activeFrom, k.Fingerprint())
+	}
+}
+
+// writeSecret writes a file only the owner can read. An existing file is only
+// replaced when overwrite is set, through a rename so it is never left half written.
+func writeSecret(path string, data []byte, overwrite bool) error {
+	path = filepath.Clean(path)
+	target := path
+	if overwrite {
+		path += ".tmp"
+	}
+
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0600)
+	if err != nil {
+		return err
+	}
+	_, err = f.Write(data)
+	if err == nil {
+		err = f.Sync()
+	}
+	if errClose := f.Close(); err == nil {
+		err = errClose
+	}
+	if err != nil {
+		_ = os.Remove(path)
+		return err
+	}
+
+	if overwrite {
+		return os.Rename(path, target)
+	}
+	return nil
+}
@@ -0,0 +1,123 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"slices"
+)
+
+// command is a group of subcommands, i.e. "audit verify"
+type command struct {
+	usage       string
+	help        string
+	subcommands map[string]func(args []string) error
+}
+
+var commands = map[string]command{
+	"audit": {
+		usage: "audit verify|export [flags]",
+		help:  "verify or export the audit log",
+		subcommands: map[string]func(args []string) error{
+			"verify": auditVerify,
+			"export": auditExport,
+		},
+	},
+	"bitstream": {
+		usage: "bitstream export [flags]",
+		help:  "write the raw output of a generator for external test suites",
+		subcommands: map[string]func(args []string) error{
+			"export": bitstreamExport,
+		},
+	},
+	"chain": {
+		usage: "chain generate|verify [flags]",
+		help:  "create a hash chain of crash rounds or verify a round",
+		subcommands: map[string]func(args []string) error{
+			"generate": chainGenerate,
+			"verify":   chainVerify,
+		},
+	},
+	"keystore": {
+		usage: "keystore create|inspect|reencrypt [flags]",
+		help:  "encrypt seeds with a passphrase",
+		subcommands: map[string]func(args []string) error{
+			"create":    keystoreCreate,
+			"inspect":   keystoreInspect,
+			"reencrypt": keystoreReencrypt,
+		},
+	},
+	"nist": {
+		usage: "nist run [flags]",
+		help:  "run the NIST SP 800-22 statistical test suite",
+		subcommands: map[string]func(args []string) error{
+			"run": nistRun,
+		},
+	},
+	"receipt": {
+		usage: "receipt keygen|verify [flags]",
+		help:  "create a signing key or verify a receipt",
+		subcommands: map[string]func(args []string) error{
+			"keygen": receiptKeygen,
+			"verify": receiptVerify,
+		},
+	},
+	"seed": {
+		usage: "seed generate|validate [flags]",
+		help:  "generate a seed or check its strength",
+		subcommands: map[string]func(args []string) error{
+			"generate": seedGenerate,
+			"validate": seedValidate,
+		},
+	},
+	"shares": {
+		usage: "shares split|combine [flags]",
+		help:  "split a seed for dual control or combine shares into a keystore",
+		subcommands: map[string]func(args []string) error{
+			"split":   sharesSplit,
+			"combine": sharesCombine,
+		},
+	},
+}
+
+func main() {
+	if len(os.Args) < 3 {
+		usage()
+		os.Exit(2)
+	}
+
+	cmd, ok := commands[os.Args[1]]
+	if !ok {
+		usage()
+		os.Exit(2)
+	}
+
+	run, ok := cmd.subcommands[os.Args[2]]
+	if !ok {
+		usage()
+		os.Exit(2)
+	}
+
+	err := run(os.Args[3:])
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "error:", err)
+		os.Exit(1)
+	}
+}
+
+func usage() {
+	fmt.Fprintln(os.Stderr, "usage: randomctl <command> <subcommand> [flags]")
+	fmt.Fprintln(os.Stderr)
+	fmt.Fprintln(os.Stderr, "commands:")
+	names := make([]string, 0, len(commands))
+	width := 0
+	for name, cmd := range commands {
+		names = append(names, name)
+		width = max(width, len(cmd.usage))
+	}
+	slices.Sort(names)
+	for _, name := range names {
+		fmt.Fprintf(os.Stderr, "  %-*s  %s\n", width, commands[name].usage, commands[name].help)
+	}
+	fmt.Fprintln(os.Stderr)
+	fmt.Fprintln(os.Stderr, "run 'randomctl <command> <subcommand> -h' for the flags of a subcommand")
+}
@@ -0,0 +1,147 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sync"
+
+	"github.com/fasttrack-solutions/random"
+	"github.com/fasttrack-solutions/random/internal/bitstream"
+	"github.com/fasttrack-solutions/random/internal/nist"
+)
+
+// sourceFile reads the bits to test from a file
+const sourceFile = "file"
+
+func nistRun(args []string) error {
+	fs := flag.NewFlagSet("nist run", flag.ContinueOnError)
+	source := fs.String("source", bitstream.SourceCrypto, "Bits to test: crypto (crypto/rand), deterministic (the hash stream of a seed) or file")
+	seedFile := fs.String("seed-file", "", "File holding the hex seed of -source deterministic, $SEED_HEX is used if empty")
+	start := fs.Int64("start", 0, "First sequence number of -source deterministic")
+	in := fs.String("in", "", "File of raw bits of -source file, the most significant bit of each byte first")
+	n := fs.Int("n", 1_000_000, "Bits per sequence")
+	sequences := fs.Int("sequences", 100, "Number of sequences")
+	alpha := fs.Float64("alpha", nist.DefaultAlpha, "Significance level of the tests")
+	out := fs.String("out", "", "File to write the report to, it is printed if empty")
+	workers := fs.Int("workers", runtime.GOMAXPROCS(0), "Number of sequences tested in parallel")
+	params := nist.DefaultParams()
+	fs.IntVar(&params.BlockFrequencyBlockLength, "block-frequency-m", params.BlockFrequencyBlockLength, "Block length of the block frequency test")
+	fs.IntVar(&params.NonOverlappingTemplateLength, "template-m", params.NonOverlappingTemplateLength, "Template length of the non-overlapping template matching test")
+	fs.IntVar(&params.ApproximateEntropyBlockLength, "entropy-m", params.ApproximateEntropyBlockLength, "Block length of the approximate entropy test")
+	fs.IntVar(&params.SerialBlockLength, "serial-m", params.SerialBlockLength, "Block length of the serial test")
+	fs.IntVar(&params.LinearComplexityBlockLength, "linear-complexity-m", params.LinearComplexityBlockLength, "Block length of the linear complexity test")
+	err := fs.Parse(args)
+	if err != nil {
+		return err
+	} else if *n < 100 || *sequences < 1 || *workers < 1 {
+		return errors.New("-n must be at least 100, -sequences and -workers at least 1")
+	} else if *alpha <= 0 || *alpha >= 1 {
+		return errors.New("-alpha must be between 0 and 1")
+	} else if params.ApproximateEntropyBlockLength < 1 || params.ApproximateEntropyBlockLength > 24 || params.SerialBlockLength < 2 || params.SerialBlockLength > 24 {
+		return errors.New("-entropy-m and -serial-m must be at most 24")
+	}
+
+	var r io.Reader
+	var generator string
+	switch *source {
+	case sourceFile:
+		f, errOpen := os.Open(filepath.Clean(*in))
+		if errOpen != nil {
+			return errOpen
+		}
+		defer f.Close()
+		r, generator = f, *in
+	case bitstream.SourceDeterministic:
+		seedHex, errSeed := readSeed(*seedFile)
+		if errSeed != nil {
+			return errSeed
+		}
+		fingerprint, errSeed := random.SeedFingerprint(seedHex)
+		if errSeed != nil {
+			return errSeed
+		}
+		r, err = bitstream.Open(*source, seedHex, *start)
+		generator = fmt.Sprintf("deterministic seed %s from sequence %v", fingerprint, *start)
+	default:
+		r, err = bitstream.Open(*source, "", 0)
+		generator = "crypto/rand"
+	}
+	if err != nil {
+		return err
+	}
+
+	results, err := runSequences(r, *n, *sequences, *workers, params)
+	if err != nil {
+		return err
+	}
+	summary := nist.Assess(results, *alpha)
+
+	w := io.Writer(os.Stdout)
+	if len(*out) > 0 {
+		f, errCreate := os.Create(filepath.Clean(*out))
+		if errCreate != nil {
+			return errCreate
+		}
+		defer f.Close()
+		w = f
+	}
+	err = summary.WriteReport(w, generator)
+	if err != nil {
+		return err
+	}
+
+	if failed := summary.Failed(); failed > 0 {
+		return fmt.Errorf("%v of %v tests failed", failed, len(summary.Rows))
+	}
+	return nil
+}
+
+// runSequences reads the sequences in order and tests them in parallel
+func runSequences(r io.Reader, n int, sequences int, workers int, params nist.Params) ([][]nist.Result, error) {
+	type sequence struct {
+		index int
+		bits  []byte
+	}
+	queue := make(chan sequence)
+	results := make([][]nist.Result, sequences)
+
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	done := 0
+	for range workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for s := range queue {
+				results[s.index] = nist.Run(s.bits, params)
+
+				mu.Lock()
+				done++
+				fmt.Fprintf(os.Stderr, "\rtested %v of %v sequences", done, sequences)
+				mu.Unlock()
+			}
+		}()
+	}
+
+	var err error
+	for i := range sequences {
+		var bits []byte
+		bits, err = nist.ReadSequence(r, n)
+		if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+			err = fmt.Errorf("the input holds %v sequences of %v bits, %v are required", i, n, sequences)
+			break
+		} else if err != nil {
+			break
+		}
+		queue <- sequence{index: i, bits: bits}
+	}
+	close(queue)
+	wg.Wait()
+	fmt.Fprintln(os.Stderr)
+	return results, err
+}
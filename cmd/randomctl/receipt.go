@@ -0,0 +1,104 @@
+package main
+
+import (
+	"crypto/ed25519"
+	"encoding/base64"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/fasttrack-solutions/random/pkg/pb"
+	"github.com/fasttrack-solutions/random/pkg/receipt"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+)
+
+func receiptKeygen(args []string) error {
+	fs := flag.NewFlagSet("receipt keygen", flag.ContinueOnError)
+	out := fs.String("out", "", "File to write the PEM encoded private key to")
+	err := fs.Parse(args)
+	if err != nil {
+		return err
+	} else if len(*out) == 0 {
+		return errors.New("-out is required")
+	}
+
+	publicKey, keyPEM, err := receipt.GenerateKey()
+	if err != nil {
+		return err
+	}
+
+	f, err := os.OpenFile(filepath.Clean(*out), os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0600)
+	if err != nil {
+		return err
+	}
+	_, err = f.Write(keyPEM)
+	if err != nil {
+		_ = f.Close()
+		return err
+	}
+	err = f.Close()
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("key id: %s\npublic key: %s\n", receipt.KeyID(publicKey), base64.StdEncoding.EncodeToString(publicKey))
+	return nil
+}
+
+func receiptVerify(args []string) error {
+	fs := flag.NewFlagSet("receipt verify", flag.ContinueOnError)
+	publicKey := fs.String("public-key", "", "Base64 public key, as returned by /receiptPublicKey or GetReceiptPublicKey")
+	encoded := fs.String("receipt", "", "Base64 receipt, as sent in the X-Receipt header")
+	err := fs.Parse(args)
+	if err != nil {
+		return err
+	} else if len(*publicKey) == 0 || len(*encoded) == 0 {
+		return errors.New("-public-key and -receipt are required")
+	}
+
+	key, err := base64.StdEncoding.DecodeString(*publicKey)
+	if err != nil || len(key) != ed25519.PublicKeySize {
+		return errors.New("invalid public key")
+	}
+
+	r, err := receipt.Decode(strings.TrimSpace(*encoded))
+	if err != nil {
+		return err
+	}
+
+	method := pb.File_pkg_pb_service_proto.Services().ByName("Random").Methods().ByName(protoreflect.Name(r.Rpc))
+	if method == nil {
+		return fmt.Errorf("unknown rpc %q", r.Rpc)
+	}
+	req, err := newMessage(method.Input())
+	if err != nil {
+		return err
+	}
+	resp, err := newMessage(method.Output())
+	if err != nil {
+		return err
+	}
+
+	err = receipt.Open(ed25519.PublicKey(key), r, req, resp)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("rpc: %s\nrequest: %s\nresponse: %s\n", r.Rpc, protojson.MarshalOptions{EmitUnpopulated: true}.Format(req), protojson.MarshalOptions{EmitUnpopulated: true}.Format(resp))
+	fmt.Println("receipt verified")
+	return nil
+}
+
+func newMessage(desc protoreflect.MessageDescriptor) (proto.Message, error) {
+	t, err := protoregistry.GlobalTypes.FindMessageByName(desc.FullName())
+	if err != nil {
+		return nil, err
+	}
+	return t.New().Interface(), nil
+}
@@ -0,0 +1,85 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/fasttrack-solutions/random"
+)
+
+func seedGenerate(args []string) error {
+	fs := flag.NewFlagSet("seed generate", flag.ContinueOnError)
+	out := fs.String("out", "", "File to write the seed to, for -seed-file; the seed is printed if empty")
+	err := fs.Parse(args)
+	if err != nil {
+		return err
+	}
+
+	seedHex, err := random.GenerateSeed()
+	if err != nil {
+		return err
+	}
+	fingerprint, err := random.SeedFingerprint(seedHex)
+	if err != nil {
+		return err
+	}
+
+	if len(*out) > 0 {
+		err = writeSecret(*out, []byte(seedHex+"\n"), false)
+		if err != nil {
+			return err
+		}
+	} else {
+		fmt.Printf("seed: %s\n", seedHex)
+	}
+	fmt.Printf("fingerprint: %s\n", fingerprint)
+	return nil
+}
+
+func seedValidate(args []string) error {
+	fs := flag.NewFlagSet("seed validate", flag.ContinueOnError)
+	seedFile := fs.String("seed-file", "", "File holding the hex seed, $SEED_HEX is validated if empty")
+	err := fs.Parse(args)
+	if err != nil {
+		return err
+	}
+
+	seedHex, err := readSeed(*seedFile)
+	if err != nil {
+		return err
+	}
+
+	err = random.ValidateSeed(seedHex)
+	if err != nil {
+		return err
+	}
+	fingerprint, err := random.SeedFingerprint(seedHex)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("fingerprint: %s\n", fingerprint)
+	fmt.Println("seed is valid")
+	return nil
+}
+
+// readSeed reads the hex seed from seedFile, or $SEED_HEX when it is empty
+func readSeed(seedFile string) (string, error) {
+	if len(seedFile) == 0 {
+		seedHex := os.Getenv("SEED_HEX")
+		if len(seedHex) == 0 {
+			return "", errors.New("-seed-file or $SEED_HEX is required")
+		}
+		return seedHex, nil
+	}
+
+	b, err := os.ReadFile(filepath.Clean(seedFile))
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(b)), nil
+}
@@ -0,0 +1,142 @@
+package main
+
+import (
+	"bufio"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/fasttrack-solutions/random"
+	"github.com/fasttrack-solutions/random/internal/keyring"
+	"github.com/fasttrack-solutions/random/internal/unseal"
+)
+
+func sharesSplit(args []string) error {
+	fs := flag.NewFlagSet("shares split", flag.ContinueOnError)
+	n := fs.Int("n", 5, "Number of shares")
+	k := fs.Int("k", 3, "Number of shares required to recover the seed")
+	seedFile := fs.String("seed-file", "", "File holding the hex seed to split, $SEED_HEX is used if empty")
+	generate := fs.Bool("generate", false, "Split a newly generated seed, so it is never seen by anyone")
+	outDir := fs.String("out-dir", "", "Directory to write share-<i>.txt files to, the shares are printed if empty")
+	err := fs.Parse(args)
+	if err != nil {
+		return err
+	}
+
+	var seedHex string
+	switch {
+	case *generate:
+		seedHex, err = random.GenerateSeed()
+		if err != nil {
+			return err
+		}
+	case len(*seedFile) > 0:
+		b, errRead := os.ReadFile(filepath.Clean(*seedFile))
+		if errRead != nil {
+			return errRead
+		}
+		seedHex = strings.TrimSpace(string(b))
+	default:
+		seedHex = os.Getenv("SEED_HEX")
+		if len(seedHex) == 0 {
+			return errors.New("-generate, -seed-file or $SEED_HEX is required")
+		}
+	}
+
+	shares, err := unseal.Split(seedHex, *n, *k)
+	if err != nil {
+		return err
+	}
+
+	for _, s := range shares {
+		if len(*outDir) == 0 {
+			fmt.Println(s.String())
+			continue
+		}
+		path := filepath.Join(*outDir, fmt.Sprintf("share-%v.txt", s.Index()))
+		err = writeSecret(path, []byte(s.String()+"\n"), false)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("wrote share %v to %s\n", s.Index(), path)
+	}
+
+	fmt.Printf("fingerprint: %s, threshold: %v of %v\n", shares[0].Fingerprint, *k, *n)
+	return nil
+}
+
+func sharesCombine(args []string) error {
+	fs := flag.NewFlagSet("shares combine", flag.ContinueOnError)
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "usage: randomctl shares combine -out <keystore> [flags] [share files]")
+		fmt.Fprintln(fs.Output(), "shares are read from stdin, one per line, when no files are given")
+		fs.PrintDefaults()
+	}
+	out := fs.String("out", "", "File to write the keystore to")
+	passphraseFile := fs.String("passphrase-file", "", "File holding the keystore passphrase, read from $"+keyring.PassphraseEnv+" if empty")
+	params := scryptFlags(fs)
+	err := fs.Parse(args)
+	if err != nil {
+		return err
+	} else if len(*out) == 0 {
+		return errors.New("-out is required")
+	}
+
+	var texts []string
+	if fs.NArg() > 0 {
+		for _, path := range fs.Args() {
+			b, errRead := os.ReadFile(filepath.Clean(path))
+			if errRead != nil {
+				return errRead
+			}
+			texts = append(texts, string(b))
+		}
+	} else {
+		scanner := bufio.NewScanner(os.Stdin)
+		for scanner.Scan() {
+			if len(strings.TrimSpace(scanner.Text())) > 0 {
+				texts = append(texts, scanner.Text())
+			}
+		}
+		if scanner.Err() != nil {
+			return scanner.Err()
+		}
+	}
+
+	shares := make([]unseal.Share, len(texts))
+	for i, text := range texts {
+		shares[i], err = unseal.ParseShare(text)
+		if err != nil {
+			return fmt.Errorf("share %v: %w", i+1, err)
+		}
+	}
+
+	seedHex, err := unseal.Combine(shares)
+	if err != nil {
+		return err
+	}
+	ring, err := keyring.Single(seedHex)
+	if err != nil {
+		return err
+	}
+
+	passphrase, err := keyring.ReadPassphrase(*passphraseFile, keyring.PassphraseEnv)
+	if err != nil {
+		return err
+	}
+	data, err := keyring.EncryptKeystore(ring.Keys(), passphrase, *params)
+	if err != nil {
+		return err
+	}
+
+	err = writeSecret(*out, data, false)
+	if err != nil {
+		return err
+	}
+
+	printKeys(ring)
+	return nil
+}
@@ -0,0 +1,156 @@
+package main
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// checkpointInterval is how often the checkpoint of a run is saved
+const checkpointInterval = 10 * time.Second
+
+// checkpoint is the progress of a run saved while it writes its results, to
+// resume the run after an interruption. The outcomes before it are read back
+// from the results to restore the statistical tests and the prize table.
+type checkpoint struct {
+	// Run identifies the parameters of the run that determine its results, a checkpoint only resumes the same run
+	Run string `json:"run"`
+	// Draws is the number of draws in the results and Offset their size in bytes, anything after it is
+	// discarded. Size is their size before compression.
+	Draws  int64 `json:"draws"`
+	Offset int64 `json:"offset"`
+	Size   int64 `json:"size"`
+}
+
+// identity is a hash of the parameters that determine the results of the run,
+// the seed is in it but cannot be recovered from it
+func (r Run) identity() string {
+	data, _ := json.Marshal(struct {
+		Function      string
+		Count         int64
+		Min, Max      int32
+		Seed          string
+		Start         int64
+		Probabilities []float64
+		Format        string
+		Compression   string
+	}{r.Function, r.Count, r.Min, r.Max, r.Seed, r.Start, r.Probabilities, r.Format, r.Compression})
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// loadCheckpoint reads the checkpoint of a run, it returns nil when there is none
+func loadCheckpoint(path string, r Run) (*checkpoint, error) {
+	data, err := os.ReadFile(filepath.Clean(path))
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	var c checkpoint
+	err = json.Unmarshal(data, &c)
+	if err != nil {
+		return nil, fmt.Errorf("invalid checkpoint %s: %w", path, err)
+	} else if c.Run != r.identity() {
+		return nil, fmt.Errorf("checkpoint %s is of a run with other parameters, remove it to start over", path)
+	} else if c.Draws < 0 || c.Draws > r.Count || c.Offset < 1 || c.Size < 0 {
+		return nil, fmt.Errorf("invalid checkpoint %s: draws or offset out of range", path)
+	}
+	return &c, nil
+}
+
+// save writes the checkpoint to path, replacing the previous one in one step
+func (c checkpoint) save(path string) error {
+	data, err := json.Marshal(c)
+	if err != nil {
+		return err
+	}
+
+	tmp := path + ".tmp"
+	err = os.WriteFile(filepath.Clean(tmp), append(data, '\n'), 0600)
+	if err != nil {
+		return err
+	}
+	return os.Rename(filepath.Clean(tmp), filepath.Clean(path))
+}
+
+// replay reads the results of the run up to the checkpoint, writes them
+// decompressed to content and feeds their outcomes in order. The results must
+// start with the header of the run.
+func replay(f io.Reader, r Run, c *checkpoint, content io.Writer, feed func(v float64) error) error {
+	dec, err := newDecompressor(io.LimitReader(f, c.Offset), r.Compression)
+	if err != nil {
+		return err
+	}
+	defer dec.Close()
+	counted := &countingWriter{w: content}
+	br := bufio.NewReaderSize(io.TeeReader(dec, counted), 1<<20)
+
+	header := r.header()
+	got := make([]byte, len(header))
+	_, err = io.ReadFull(br, got)
+	if err != nil || string(got) != header {
+		return errors.New("the results do not start with the header of the run")
+	}
+
+	draws := int64(0)
+	for {
+		line, errRead := br.ReadString('\n')
+		if errors.Is(errRead, io.EOF) && len(line) == 0 {
+			break
+		} else if errRead != nil {
+			return fmt.Errorf("the results end within a line before the checkpoint: %w", errRead)
+		}
+
+		v, errParse := parseOutcome(line)
+		if errParse != nil {
+			return errParse
+		}
+		err = feed(v)
+		if err != nil {
+			return err
+		}
+		draws++
+	}
+
+	if draws != c.Draws || counted.n != c.Size {
+		return fmt.Errorf("the results hold %v draws in %v bytes before the checkpoint, the checkpoint %v in %v", draws, counted.n, c.Draws, c.Size)
+	}
+	return nil
+}
+
+// parseOutcome parses the outcome of a line of results in any format, it is the last column or field
+func parseOutcome(line string) (float64, error) {
+	line = strings.TrimSuffix(strings.TrimSuffix(line, "\n"), "}")
+	if i := strings.LastIndexAny(line, ",:"); i >= 0 {
+		line = line[i+1:]
+	}
+	v, err := strconv.ParseFloat(line, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid outcome in the results: %w", err)
+	}
+	return v, nil
+}
+
+// countingWriter counts the bytes written through it
+type countingWriter struct {
+	w io.Writer
+	n int64
+}
+
+func (c *countingWriter) Write(p []byte) (int, error) {
+	n, err := c.w.Write(p)
+	c.n += int64(n)
+	return n, err
+}
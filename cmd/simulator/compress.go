@@ -0,0 +1,84 @@
+package main
+
+import (
+	"compress/gzip"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+
+	"github.com/klauspost/compress/zstd"
+)
+
+const (
+	compressionNone = "none"
+	compressionGzip = "gzip"
+	compressionZstd = "zstd"
+)
+
+// compressionOf is the compression of a results file by the extension of its path
+func compressionOf(path string) string {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".gz":
+		return compressionGzip
+	case ".zst":
+		return compressionZstd
+	default:
+		return compressionNone
+	}
+}
+
+// compressionExtension is the extension of results compressed with compression
+func compressionExtension(compression string) string {
+	switch compression {
+	case compressionGzip:
+		return ".gz"
+	case compressionZstd:
+		return ".zst"
+	default:
+		return ""
+	}
+}
+
+// newCompressor compresses what is written to it into w, closing it ends a
+// gzip member or zstd frame without closing w. Readers of both formats read
+// files of several members or frames as one stream, so a resumed run can start
+// a new one where the checkpoint ended the last.
+func newCompressor(w io.Writer, compression string) (io.WriteCloser, error) {
+	switch compression {
+	case compressionGzip:
+		return gzip.NewWriter(w), nil
+	case compressionZstd:
+		return zstd.NewWriter(w)
+	case compressionNone:
+		return nopWriteCloser{w}, nil
+	default:
+		return nil, fmt.Errorf("unknown compression %q", compression)
+	}
+}
+
+// newDecompressor reads the content of r, compressed with compression
+func newDecompressor(r io.Reader, compression string) (io.ReadCloser, error) {
+	switch compression {
+	case compressionGzip:
+		return gzip.NewReader(r)
+	case compressionZstd:
+		d, err := zstd.NewReader(r)
+		if err != nil {
+			return nil, err
+		}
+		return d.IOReadCloser(), nil
+	case compressionNone:
+		return io.NopCloser(r), nil
+	default:
+		return nil, fmt.Errorf("unknown compression %q", compression)
+	}
+}
+
+type nopWriteCloser struct {
+	io.Writer
+}
+
+func (nopWriteCloser) Close() error {
+	return nil
+}
@@ -0,0 +1,223 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"strconv"
+	"text/tabwriter"
+
+	"github.com/fasttrack-solutions/random/internal/stats"
+)
+
+const (
+	// diffFloatBins is the number of equal bins the outcomes of UniformFloat64 are compared in
+	diffFloatBins = 100
+	// diffMaxBuckets is the most buckets the outcomes of UniformInt64 are compared in
+	diffMaxBuckets = 1000
+)
+
+// categories maps the outcomes of a run onto the rows of a diff
+type categories struct {
+	labels   []string
+	category func(v float64) (int, bool)
+}
+
+// newCategories are the indexes of DeterministicRandom, the values or buckets of
+// values of UniformInt64 or the bins of UniformFloat64
+func newCategories(r Run) categories {
+	switch r.Function {
+	case functionDeterministicRandom:
+		labels := make([]string, len(r.Probabilities))
+		for i := range labels {
+			labels[i] = strconv.Itoa(i)
+		}
+		return categories{labels: labels, category: func(v float64) (int, bool) {
+			return int(v), v >= 0 && v < float64(len(labels)) && v == math.Trunc(v)
+		}}
+	case functionUniformInt64:
+		size := int64(r.Max) - int64(r.Min) + 1
+		buckets := min(size, diffMaxBuckets)
+		labels := make([]string, buckets)
+		for i := range labels {
+			// Bucket i holds the values of which (v-min)*buckets/size is i
+			lo := (int64(i)*size + buckets - 1) / buckets
+			hi := (int64(i+1)*size+buckets-1)/buckets - 1
+			labels[i] = strconv.FormatInt(int64(r.Min)+lo, 10)
+			if hi > lo {
+				labels[i] += "-" + strconv.FormatInt(int64(r.Min)+hi, 10)
+			}
+		}
+		return categories{labels: labels, category: func(v float64) (int, bool) {
+			if v < float64(r.Min) || v > float64(r.Max) || v != math.Trunc(v) {
+				return 0, false
+			}
+			return int((int64(v) - int64(r.Min)) * buckets / size), true
+		}}
+	default:
+		labels := make([]string, diffFloatBins)
+		for i := range labels {
+			labels[i] = fmt.Sprintf("%.2f-%.2f", float64(i)/diffFloatBins, float64(i+1)/diffFloatBins)
+		}
+		return categories{labels: labels, category: func(v float64) (int, bool) {
+			return min(int(v*diffFloatBins), diffFloatBins-1), v >= 0 && v <= 1
+		}}
+	}
+}
+
+// runDiff compares the distributions of the outcomes of two results files of
+// the same function, with a chi-square test of homogeneity
+func runDiff(args []string) error {
+	fs := flag.NewFlagSet("simulator diff", flag.ContinueOnError)
+	alpha := fs.Float64("alpha", stats.DefaultAlpha, "Significance level below which the distributions differ")
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "usage: simulator diff [flags] <results a> <results b>")
+		fs.PrintDefaults()
+	}
+	err := fs.Parse(args)
+	if err != nil {
+		return err
+	} else if fs.NArg() != 2 {
+		fs.Usage()
+		return errors.New("diff takes two results files")
+	} else if *alpha <= 0 || *alpha >= 1 {
+		return errors.New("-alpha must be between 0 and 1")
+	}
+
+	paths := [2]string{fs.Arg(0), fs.Arg(1)}
+	var manifests [2]Manifest
+	for i, path := range paths {
+		manifests[i], err = readManifest(path + manifestSuffix)
+		if err != nil {
+			return err
+		}
+	}
+	a, b := manifests[0].run(), manifests[1].run()
+	if a.Function != b.Function {
+		return fmt.Errorf("cannot compare %s with %s", a.Function, b.Function)
+	} else if a.Function == functionUniformInt64 && (a.Min != b.Min || a.Max != b.Max) {
+		return errors.New("cannot compare UniformInt64 of different ranges")
+	} else if a.Function == functionDeterministicRandom && len(a.Probabilities) != len(b.Probabilities) {
+		return errors.New("cannot compare DeterministicRandom of a different number of indexes")
+	}
+
+	cats := newCategories(a)
+	var counts [2][]int64
+	var outside, totals [2]int64
+	for i, path := range paths {
+		counts[i], outside[i], totals[i], err = countOutcomes(path, manifests[i], cats)
+		if err != nil {
+			return err
+		}
+	}
+
+	stat, df := homogeneity(counts[0], counts[1])
+	p := 1.0
+	if df > 0 {
+		p = stats.ChiSquarePValue(stat, df)
+	}
+
+	tw := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', tabwriter.AlignRight)
+	fmt.Fprintln(tw, "outcome\ta\ta share\tb\tb share\tdifference\t")
+	for c, label := range cats.labels {
+		shareA, shareB := share(counts[0][c], totals[0]), share(counts[1][c], totals[1])
+		fmt.Fprintf(tw, "%s\t%v\t%.4f%%\t%v\t%.4f%%\t%+.4f%%\t\n", label, counts[0][c], 100*shareA, counts[1][c], 100*shareB, 100*(shareB-shareA))
+	}
+	if outside[0] > 0 || outside[1] > 0 {
+		fmt.Fprintf(tw, "outside\t%v\t\t%v\t\t\t\n", outside[0], outside[1])
+	}
+	err = tw.Flush()
+	if err != nil {
+		return err
+	}
+
+	if manifests[0].ContentSHA256 == manifests[1].ContentSHA256 {
+		fmt.Println("the results are identical")
+	}
+	fmt.Printf("chi-square %.6g with %v degrees of freedom, p-value %.6f over %v and %v outcomes\n", stat, df, p, totals[0], totals[1])
+	if p < *alpha || outside[0] > 0 || outside[1] > 0 {
+		return fmt.Errorf("the distributions differ at alpha %v", *alpha)
+	}
+	return nil
+}
+
+// countOutcomes counts the outcomes of the results at path per category, the ones
+// outside of all categories and all of them
+func countOutcomes(path string, m Manifest, cats categories) ([]int64, int64, int64, error) {
+	results, err := openManifestResults(path, m, io.Discard)
+	if err != nil {
+		return nil, 0, 0, err
+	}
+	defer results.Close()
+
+	header := m.run().header()
+	got := make([]byte, len(header))
+	_, err = io.ReadFull(results, got)
+	if err != nil || string(got) != header {
+		return nil, 0, 0, fmt.Errorf("%s does not start with the header of its manifest", path)
+	}
+
+	counts := make([]int64, len(cats.labels))
+	outside, total := int64(0), int64(0)
+	for {
+		line, errRead := results.ReadSlice('\n')
+		if len(line) == 0 && errors.Is(errRead, io.EOF) {
+			break
+		} else if errRead != nil && !errors.Is(errRead, io.EOF) {
+			return nil, 0, 0, errRead
+		}
+
+		v, errParse := parseOutcome(string(line))
+		if errParse != nil {
+			return nil, 0, 0, fmt.Errorf("%s: %w", path, errParse)
+		}
+		total++
+		if c, ok := cats.category(v); ok {
+			counts[c]++
+		} else {
+			outside++
+		}
+	}
+	return counts, outside, total, nil
+}
+
+// homogeneity is the chi-square statistic of the hypothesis that both counts are
+// drawn from the same distribution, categories empty in both are left out
+func homogeneity(a []int64, b []int64) (float64, int) {
+	totalA, totalB := float64(sum(a)), float64(sum(b))
+	if totalA == 0 || totalB == 0 {
+		return 0, 0
+	}
+
+	stat := 0.0
+	df := -1
+	for c := range a {
+		row := float64(a[c] + b[c])
+		if row == 0 {
+			continue
+		}
+		expectedA := row * totalA / (totalA + totalB)
+		expectedB := row * totalB / (totalA + totalB)
+		stat += (float64(a[c])-expectedA)*(float64(a[c])-expectedA)/expectedA + (float64(b[c])-expectedB)*(float64(b[c])-expectedB)/expectedB
+		df++
+	}
+	return stat, max(df, 0)
+}
+
+func sum(counts []int64) int64 {
+	total := int64(0)
+	for _, c := range counts {
+		total += c
+	}
+	return total
+}
+
+func share(count int64, total int64) float64 {
+	if total == 0 {
+		return 0
+	}
+	return float64(count) / float64(total)
+}
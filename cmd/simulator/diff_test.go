@@ -0,0 +1,53 @@
+package main
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Diff(t *testing.T) {
+	dir := t.TempDir()
+	a := testRun(filepath.Join(dir, "a.csv"))
+	same := testRun(filepath.Join(dir, "same.csv.gz"))
+	other := testRun(filepath.Join(dir, "other.jsonl"))
+	other.Probabilities = []float64{0.05, 0.15, 0.3, 0.5}
+	for _, r := range []Run{a, same, other} {
+		_, _, err := execute(r)
+		assert.Nil(t, err)
+	}
+
+	// The same draws, compressed or not, have the same distribution
+	out, err := captureStdout(t, func() error { return runDiff([]string{a.Output, same.Output}) })
+	assert.Nil(t, err)
+	assert.Contains(t, out, "the results are identical\n")
+	assert.Contains(t, out, "chi-square 0 with 3 degrees of freedom, p-value 1.000000 over 49275 and 49275 outcomes\n")
+
+	out, err = captureStdout(t, func() error { return runDiff([]string{a.Output, other.Output}) })
+	assert.ErrorContains(t, err, "the distributions differ")
+	assert.NotContains(t, out, "identical")
+	assert.Contains(t, out, "p-value 0.000000")
+
+	uniform := Run{Function: functionUniformFloat64, Count: 10, Output: filepath.Join(dir, "uniform.csv")}
+	_, _, err = execute(uniform)
+	assert.Nil(t, err)
+	_, err = captureStdout(t, func() error { return runDiff([]string{a.Output, uniform.Output}) })
+	assert.ErrorContains(t, err, "cannot compare")
+}
+
+func Test_Homogeneity(t *testing.T) {
+	// Both 60 outcomes: expected 15, 20 and 25 per category, (25+25)/15 + 0 + (25+25)/25 = 16/3
+	stat, df := homogeneity([]int64{10, 20, 0, 30}, []int64{20, 20, 0, 20})
+	assert.InDelta(t, 16.0/3, stat, 1e-12)
+	assert.Equal(t, 2, df)
+
+	// 40 and 20 outcomes: expected 80/3 and 40/3, then 40/3 and 20/3, 1.25 + 2.5
+	stat, df = homogeneity([]int64{30, 10}, []int64{10, 10})
+	assert.InDelta(t, 3.75, stat, 1e-12)
+	assert.Equal(t, 1, df)
+
+	stat, df = homogeneity([]int64{5, 5}, []int64{0, 0})
+	assert.Equal(t, 0.0, stat)
+	assert.Equal(t, 0, df)
+}
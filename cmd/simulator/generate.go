@@ -0,0 +1,246 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/fasttrack-solutions/random"
+)
+
+const (
+	// shardSize is the number of draws a worker draws and formats at a time
+	shardSize = 1 << 14
+	// progressInterval is how often the progress of a run is printed
+	progressInterval = time.Second
+	// progressBarWidth is the number of characters of the progress bar
+	progressBarWidth = 30
+)
+
+// shard is a range of draws of a run, drawn and formatted by a worker
+type shard struct {
+	// start is the first draw of the shard, from 0 to Count-1
+	start    int64
+	end      int64
+	outcomes []float64
+	lines    []byte
+	err      error
+	// done receives the shard once it is filled
+	done chan *shard
+}
+
+// header is the row of column names of formatCSV, the other formats have none
+func (r Run) header() string {
+	if r.Format != formatCSV {
+		return ""
+	} else if r.Function == functionDeterministicRandom {
+		return "sequence,index\n"
+	}
+	return "value\n"
+}
+
+// draw draws the outcome of draw i, from 0 to Count-1
+func (r Run) draw(i int64) (float64, error) {
+	if r.remote != nil {
+		return r.remote.draw(r, i)
+	}
+
+	switch r.Function {
+	case functionUniformInt64:
+		rnd, err := random.UniformInt64(r.Min, r.Max)
+		return float64(rnd), err
+	case functionDeterministicRandom:
+		rnd, err := random.DeterministicRandom(r.Seed, r.Start+i, r.Probabilities)
+		return float64(rnd), err
+	default:
+		return random.UniformFloat64()
+	}
+}
+
+// appendLine appends the line of draw i with outcome v, the outcomes of the
+// functions are integers except for UniformFloat64
+func (r Run) appendLine(b []byte, i int64, v float64) []byte {
+	if r.Format == formatJSONL {
+		if r.Function == functionDeterministicRandom {
+			b = append(b, `{"sequence":`...)
+			b = strconv.AppendInt(b, r.Start+i, 10)
+			b = append(b, `,"index":`...)
+		} else {
+			b = append(b, `{"value":`...)
+		}
+	} else if r.Format == formatCSV && r.Function == functionDeterministicRandom {
+		b = strconv.AppendInt(b, r.Start+i, 10)
+		b = append(b, ',')
+	}
+
+	if r.Function == functionUniformFloat64 {
+		// The shortest representation, as fmt prints it
+		b = strconv.AppendFloat(b, v, 'g', -1, 64)
+	} else {
+		b = strconv.AppendInt(b, int64(v), 10)
+	}
+	if r.Format == formatJSONL {
+		b = append(b, '}')
+	}
+	return append(b, '\n')
+}
+
+// fill draws and formats the draws of s
+func (r Run) fill(s *shard) {
+	s.outcomes = make([]float64, 0, s.end-s.start)
+	s.lines = make([]byte, 0, 24*(s.end-s.start))
+	for i := s.start; i < s.end; i++ {
+		v, err := r.draw(i)
+		if errors.Is(err, errRemoteFailed) {
+			// Counted in the report of the target
+			continue
+		} else if err != nil {
+			s.err = err
+			return
+		}
+		s.outcomes = append(s.outcomes, v)
+		s.lines = r.appendLine(s.lines, i, v)
+	}
+}
+
+// generate draws the outcomes from draw from to the end of the run on r.Workers
+// workers. The shards are written to w and fed to feed in the order of their draws,
+// the output and the reports do not depend on the number of workers. written is
+// called after every shard with the number of draws written.
+func generate(w io.Writer, r Run, from int64, feed func(v float64) error, written func(draws int64) error) error {
+	queue := make(chan chan *shard, 2*r.Workers)
+	jobs := make(chan *shard, 2*r.Workers)
+	stop := make(chan struct{})
+	var wg sync.WaitGroup
+	defer wg.Wait()
+	defer close(stop)
+
+	for range r.Workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for s := range jobs {
+				r.fill(s)
+				s.done <- s
+			}
+		}()
+	}
+
+	// Queue the shards in order, the workers fill them in any order
+	go func() {
+		defer close(jobs)
+		defer close(queue)
+		size := int64(shardSize)
+		if r.remote != nil {
+			size = remoteShardSize
+		}
+		for start := from; start < r.Count; start += size {
+			s := &shard{start: start, end: min(start+size, r.Count), done: make(chan *shard, 1)}
+			select {
+			case queue <- s.done:
+			case <-stop:
+				return
+			}
+			select {
+			case jobs <- s:
+			case <-stop:
+				return
+			}
+		}
+	}()
+
+	for done := range queue {
+		s := <-done
+		if s.err != nil {
+			return s.err
+		}
+
+		_, err := w.Write(s.lines)
+		if err != nil {
+			return err
+		}
+		for _, v := range s.outcomes {
+			err = feed(v)
+			if err != nil {
+				return err
+			}
+		}
+		err = written(s.end)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// progress prints a bar of the progress of a run and its throughput on stderr,
+// at most every progressInterval
+type progress struct {
+	count   int64
+	from    int64
+	draws   int64
+	began   time.Time
+	last    time.Time
+	printed bool
+}
+
+// newProgress starts the progress of a run of count draws, resumed at draw from
+func newProgress(count int64, from int64) *progress {
+	now := time.Now()
+	return &progress{count: count, from: from, draws: from, began: now, last: now}
+}
+
+// update prints the progress when progressInterval passed since it was printed last
+func (p *progress) update(draws int64) {
+	p.draws = draws
+	now := time.Now()
+	if now.Sub(p.last) < progressInterval {
+		return
+	}
+	p.last = now
+	p.print(now)
+}
+
+// print prints the progress line, with the time left until the run completes
+func (p *progress) print(now time.Time) {
+	rate := float64(p.draws-p.from) / now.Sub(p.began).Seconds()
+	if rate <= 0 {
+		return
+	}
+
+	filled := int(progressBarWidth * float64(p.draws) / float64(p.count))
+	left := "done"
+	if p.draws < p.count {
+		left = (time.Duration(float64(p.count-p.draws)/rate) * time.Second).Round(time.Second).String() + " left"
+	}
+	fmt.Fprintf(os.Stderr, "\r[%s%s] %5.1f%% %v of %v at %s draws/s, %s   ", strings.Repeat("#", filled),
+		strings.Repeat(".", progressBarWidth-filled), 100*float64(p.draws)/float64(p.count), p.draws, p.count, humanize(rate), left)
+	p.printed = true
+}
+
+// finish prints the progress of the last update and ends the progress line,
+// unless the run was too short to print its progress
+func (p *progress) finish() {
+	if p.printed {
+		p.print(time.Now())
+		fmt.Fprintln(os.Stderr)
+	}
+}
+
+// humanize abbreviates a number with the k, M or G suffix
+func humanize(v float64) string {
+	switch {
+	case v >= 1e9:
+		return fmt.Sprintf("%.2fG", v/1e9)
+	case v >= 1e6:
+		return fmt.Sprintf("%.2fM", v/1e6)
+	case v >= 1e3:
+		return fmt.Sprintf("%.1fk", v/1e3)
+	}
+	return fmt.Sprintf("%.0f", v)
+}
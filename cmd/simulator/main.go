@@ -1,292 +1,179 @@
 package main
 
 import (
-	"bufio"
+	"cmp"
+	"errors"
+	"flag"
 	"fmt"
-	"github.com/fasttrack-solutions/random"
-	"github.com/nexidian/gocliselect"
+	"math"
 	"os"
-	"path/filepath"
-	"strconv"
 	"strings"
-	"time"
+
+	"github.com/fasttrack-solutions/random/internal/stats"
 )
 
 func main() {
-	menu := gocliselect.NewMenu("Select test to run")
-
-	menu.AddItem("Random Uniform Float64 with range (0,1]", "UniformFloat64")
-	menu.AddItem("Random Uniform Int with range (min, max)", "UniformInt64")
-	menu.AddItem("Deterministic Random with seed and probabilities", "DeterministicRandom")
-	menu.AddItem("Exit", "Exit")
-
-	choice := menu.Display()
-
-	err := os.MkdirAll("cmd/simulator/results", 0750)
-	if err != nil {
-		fmt.Println("error creating results directory")
+	// Without arguments the simulator asks for a run in a menu
+	if len(os.Args) < 2 {
+		runMenu()
 		return
 	}
 
-	switch choice {
-	case "UniformFloat64":
-		for {
-			res, errPrompt := stringPrompt("how many results do you want to generate?")
-			if errPrompt != nil {
-				fmt.Println("error getting results from prompt:", errPrompt)
-				continue
-			}
-
-			numbersToGenerate, errParseInt := strconv.Atoi(res)
-			if errParseInt != nil {
-				fmt.Println(res, "is an invalid number, try again")
-				continue
-			}
-
-			fmt.Print("generating ", res, " random numbers... ")
-
-			fileName, errGenerate := generateUniformFloat64(numbersToGenerate)
-			if errGenerate != nil {
-				fmt.Print("error: ", errGenerate)
-				return
-			}
-
-			fmt.Println("file with results was generated:", fileName)
-
-			break
-		}
-
-	case "UniformInt64":
-		for {
-			res, errPrompt := stringPrompt("how many results do you want to generate?")
-			if errPrompt != nil {
-				fmt.Println("error getting results from prompt:", errPrompt)
-				continue
-			}
-
-			numbersToGenerate, errParseIntCount := strconv.Atoi(res)
-			if errParseIntCount != nil {
-				fmt.Println(res, "is an invalid number, try again")
-				continue
-			}
-
-			res, errPrompt = stringPrompt("whats the minimum number?")
-			if errPrompt != nil {
-				fmt.Println("error getting results from prompt:", errPrompt)
-				continue
-			}
-
-			minimumNumber, errParseIntMin := strconv.ParseInt(res, 10, 32)
-			if errParseIntMin != nil {
-				fmt.Println(res, "is an invalid number, try again")
-				continue
-			}
-
-			res, errPrompt = stringPrompt("whats the maximum number?")
-			if errPrompt != nil {
-				fmt.Println("error getting results from prompt:", errPrompt)
-				continue
-			}
-
-			maximumNumber, errParseIntMax := strconv.ParseInt(res, 10, 32)
-			if errParseIntMax != nil {
-				fmt.Println(res, "is an invalid number, try again")
-				continue
-			} else if maximumNumber <= minimumNumber {
-				fmt.Println("maximum cannot be less than or equal to minimum number, try again")
-				continue
-			}
-
-			fmt.Print("generating ", numbersToGenerate, " random numbers between ", minimumNumber, " and ", maximumNumber, "... ")
-
-			fileName, errGenerate := generateUniformInt64(numbersToGenerate, int32(minimumNumber), int32(maximumNumber))
-			if errGenerate != nil {
-				fmt.Print("error: ", errGenerate)
-				return
-			}
-
-			fmt.Println("file with results was generated:", fileName)
-
-			break
-		}
-
-	case "DeterministicRandom":
-		for {
-			res, errPrompt := stringPrompt("how many results do you want to generate?")
-			if errPrompt != nil {
-				fmt.Println("error getting results from prompt:", errPrompt)
-				continue
-			}
-
-			numbersToGenerate, errParseIntCount := strconv.ParseInt(res, 10, 64)
-			if errParseIntCount != nil {
-				fmt.Println(res, "is an invalid number, try again")
-				continue
-			}
-
-			res, errPrompt = stringPrompt("what seed should be used (i.e. 9912f3bcf715a55ae5c9d47f9f6562599912f3bcf715a55ae5c9d47f9f656259)?")
-			if errPrompt != nil {
-				fmt.Println("error getting results from prompt:", errPrompt)
-				continue
-			}
-
-			seedHex := res
-			if len(seedHex) != 64 {
-				fmt.Println("the seed needs to be 64 characters [a-f0-9], try again")
-				continue
-			}
-
-			res, errPrompt = stringPrompt("what probabilities should be used (i.e. 0.3, 0.5, 0.2)?")
-			if errPrompt != nil {
-				fmt.Println("error getting results from prompt:", errPrompt)
-				continue
-			}
-
-			probabilitiesAsStrings := strings.Split(res, ",")
-			if len(probabilitiesAsStrings) == 0 {
-				fmt.Println("no probabilities set, try again")
-				continue
-			}
-
-			probabilities := make([]float64, len(probabilitiesAsStrings))
-			for i, v := range probabilitiesAsStrings {
-				probabilities[i], err = strconv.ParseFloat(strings.TrimSpace(v), 64)
-				if err != nil {
-					fmt.Println("invalid probability, try again:", err)
-					continue
-				}
-			}
-
-			fmt.Print("generating ", numbersToGenerate, " random numbers... ")
-
-			fileName, errGenerate := generateDeterministicRandom(numbersToGenerate, seedHex, probabilities)
-			if errGenerate != nil {
-				fmt.Print("error: ", errGenerate)
-				return
-			}
-
-			fmt.Println("file with results was generated:", fileName)
-
-			break
-		}
-
-	case "Exit":
-		return
-
+	var err error
+	switch os.Args[1] {
+	case "verify":
+		err = runVerify(os.Args[2:])
+	case "diff":
+		err = runDiff(os.Args[2:])
 	default:
-		fmt.Println("oops... selected test is not yet implemented")
+		err = runFlags(os.Args[1:])
 	}
-
-	// generateRandom(100)
-}
-
-func stringPrompt(label string) (string, error) {
-	var s string
-	r := bufio.NewReader(os.Stdin)
-	for {
-		_, err := fmt.Fprint(os.Stderr, label+" ")
-		if err != nil {
-			return "", err
-		}
-		s, _ = r.ReadString('\n')
-		if s != "" {
-			break
-		}
+	if errors.Is(err, flag.ErrHelp) {
+		os.Exit(2)
+	} else if err != nil {
+		fmt.Fprintln(os.Stderr, "error:", err)
+		os.Exit(1)
 	}
-	return strings.TrimSpace(s), nil
 }
 
-func generateUniformFloat64(numbersToGenerate int) (string, error) {
-	fileName := fmt.Sprintf("cmd/simulator/results/UniformFloat64-%v.csv", time.Now().UnixMilli())
-
-	f, err := os.Create(filepath.Clean(fileName))
+// runFlags runs the scenario file or the single run described by the flags
+func runFlags(args []string) error {
+	fs := flag.NewFlagSet("simulator", flag.ContinueOnError)
+	scenario := fs.String("scenario", "", "YAML or JSON file describing the runs, instead of the flags below")
+	function := fs.String("function", "", "Function to draw: "+strings.Join(functions, ", "))
+	count := fs.Int64("count", 0, "Number of outcomes to draw")
+	minimum := fs.Int("min", 0, "Minimum of UniformInt64")
+	maximum := fs.Int("max", 0, "Maximum of UniformInt64")
+	seed := fs.String("seed", "", "Hex seed of DeterministicRandom, defaults to $SEED_HEX")
+	seedFile := fs.String("seed-file", "", "File holding the hex seed of DeterministicRandom")
+	start := fs.Int64("start", 0, "First sequence number of DeterministicRandom")
+	probabilities := fs.String("p", "", "Comma separated probabilities of DeterministicRandom, i.e. 0.3,0.5,0.2")
+	values := fs.String("values", "", "Comma separated payouts of the indexes of DeterministicRandom, i.e. 0,5,20, simulates the prize table")
+	cost := fs.Float64("cost", 0, "Cost of a play of the prize table, the RTP and volatility index are relative to it")
+	campaignSize := fs.Int64("campaign-size", stats.DefaultCampaignSize, "Plays of a campaign, the maximum exposure is measured per campaign")
+	confidence := fs.Float64("confidence", stats.DefaultConfidence, "Confidence level of the intervals of the prize table")
+	out := fs.String("out", "", "Results file, - for stdout, defaults to a new file in "+resultsDir)
+	format := fs.String("format", "", "Format of the results: "+strings.Join(formats, ", ")+"; by the extension of -out (.csv, .jsonl, .txt) when empty, else csv")
+	compression := fs.String("compress", "", "Compression of the results: none, gzip or zstd, by the extension of -out (.gz, .zst) when empty")
+	workers := fs.Int("workers", 0, fmt.Sprintf("Number of goroutines drawing the outcomes, the results are the same for any number; defaults to GOMAXPROCS, or %v concurrent requests with -target", defaultRemoteWorkers))
+	checkpointPath := fs.String("checkpoint", "", "File to save the progress to, rerunning the command resumes from it; requires -out")
+	target := fs.String("target", "", "Draw from a deployed service instead of the library: grpc://host:port, grpcs://host:port or an http(s):// URL")
+	namespace := fs.String("namespace", "", "Namespace the target draws DeterministicRandom in")
+	keyID := fs.String("key-id", "", "Key the target draws DeterministicRandom with, the active key when empty")
+	algorithm := fs.String("algorithm", "", "Deterministic algorithm of the target: sha256, the default, or vrf")
+	derivation := fs.String("derivation", "", "Seed derivation of the target: none, the default, or hkdf")
+	replayToken := fs.String("replay-token", "", "Replay token for a target that allocates the sequences, defaults to $REPLAY_TOKEN")
+	timeout := fs.Duration("timeout", defaultTimeout, "Timeout of a request to the target")
+	analyze := fs.Bool("analyze", false, "Run the statistical tests on the outcomes and print their report")
+	alpha := fs.Float64("alpha", stats.DefaultAlpha, "Significance level below which a statistical test fails")
+	reportPath := fs.String("report", "", "File to write the report of the statistical tests to in JSON, enables -analyze")
+	histogram := fs.Bool("histogram", false, "Print the histogram of the outcomes against the expected counts with the report, enables -analyze")
+	err := fs.Parse(args)
 	if err != nil {
-		return "", err
-	}
-
-	defer f.Close()
-
-	_, errWriteString := f.WriteString("UniformFloat64 (0-1]\n")
-	if errWriteString != nil {
-		return "", errWriteString
+		return err
+	} else if fs.NArg() > 0 {
+		return fmt.Errorf("unexpected argument %q", fs.Arg(0))
 	}
 
-	for i := 0; i < numbersToGenerate; i++ {
-		rnd, errRnr := random.UniformFloat64()
-		if errRnr != nil {
-			return "", errRnr
+	if len(*scenario) > 0 {
+		others := 0
+		fs.Visit(func(f *flag.Flag) {
+			if f.Name != "scenario" {
+				others++
+			}
+		})
+		if others > 0 {
+			return errors.New("-scenario cannot be combined with the flags of a single run")
 		}
 
-		_, errWriteString = f.WriteString(fmt.Sprintf("%v\n", rnd))
-		if errWriteString != nil {
-			return "", errWriteString
+		s, errLoad := loadScenario(*scenario)
+		if errLoad != nil {
+			return errLoad
+		}
+		failed := 0
+		for i, r := range s.Runs {
+			fmt.Fprintf(os.Stderr, "run %v of %v: generating %s\n", i+1, len(s.Runs), r)
+			runFailed, errRun := report(r)
+			if errRun != nil {
+				return fmt.Errorf("%s: %w", runLabel(i, r), errRun)
+			}
+			failed += runFailed
 		}
+		return testsFailed(failed)
 	}
 
-	return fileName, nil
-}
-
-func generateUniformInt64(numbersToGenerate int, min int32, max int32) (string, error) {
-	fileName := fmt.Sprintf("cmd/simulator/results/UniformInt64-%v.csv", time.Now().UnixMilli())
-
-	f, err := os.Create(filepath.Clean(fileName))
-	if err != nil {
-		return "", err
+	if *minimum < math.MinInt32 || *minimum > math.MaxInt32 || *maximum < math.MinInt32 || *maximum > math.MaxInt32 {
+		return errors.New("min and max must fit in 32 bits")
 	}
 
-	defer f.Close()
-
-	_, errWriteString := f.WriteString(fmt.Sprintf("UniformInt64 (%v-%v)\n", min, max))
-	if errWriteString != nil {
-		return "", errWriteString
+	r := Run{
+		Function:    *function,
+		Count:       *count,
+		Min:         int32(*minimum), // #nosec G115 -- checked above
+		Max:         int32(*maximum), // #nosec G115 -- checked above
+		Seed:        *seed,
+		SeedFile:    *seedFile,
+		Start:       *start,
+		Output:      *out,
+		Format:      *format,
+		Compression: *compression,
+		Workers:     *workers,
+		Checkpoint:  *checkpointPath,
+		Analyze:     *analyze,
+		Alpha:       *alpha,
+		Report:      *reportPath,
+		Histogram:   *histogram,
 	}
-
-	for i := 0; i < numbersToGenerate; i++ {
-		rnd, errRnr := random.UniformInt64(min, max)
-		if errRnr != nil {
-			return "", errRnr
+	if strings.EqualFold(r.Function, functionDeterministicRandom) && len(r.Seed) == 0 && len(r.SeedFile) == 0 {
+		r.Seed = os.Getenv("SEED_HEX")
+	}
+	r.Target, r.Namespace, r.KeyID, r.Algorithm, r.Derivation, r.ReplayToken = *target, *namespace, *keyID, *algorithm, *derivation, *replayToken
+	if len(r.Target) > 0 {
+		r.ReplayToken = cmp.Or(r.ReplayToken, os.Getenv("REPLAY_TOKEN"))
+	}
+	if len(r.Target) > 0 || *timeout != defaultTimeout {
+		r.Timeout = timeout.String()
+	}
+	if len(*probabilities) > 0 {
+		r.Probabilities, err = parseProbabilities(*probabilities)
+		if err != nil {
+			return err
 		}
-
-		_, errWriteString = f.WriteString(fmt.Sprintf("%v\n", rnd))
-		if errWriteString != nil {
-			return "", errWriteString
+	}
+	if len(*values) > 0 {
+		r.Values, err = parseValues(*values)
+		if err != nil {
+			return err
 		}
+		r.Cost, r.CampaignSize, r.Confidence = *cost, *campaignSize, *confidence
+	} else if *cost != 0 {
+		return errors.New("-cost requires -values")
 	}
-
-	return fileName, nil
-}
-
-func generateDeterministicRandom(numbersToGenerate int64, seed string, probabilities []float64) (string, error) {
-	fileName := fmt.Sprintf("cmd/simulator/results/DeterministicRandom-%v.csv", time.Now().UnixMilli())
-
-	f, err := os.Create(filepath.Clean(fileName))
+	failed, err := report(r)
 	if err != nil {
-		return "", err
+		return err
 	}
+	return testsFailed(failed)
+}
 
-	defer f.Close()
-
-	_, errWriteString := f.WriteString(fmt.Sprintf("DeterministicRandom (%v %v)\n", seed, probabilities))
-	if errWriteString != nil {
-		return "", errWriteString
+// report executes a run and tells where its results went, how its statistical
+// tests did, what its prize table paid and how its target did, on stderr so
+// stdout can carry the results. It returns the number of checks that failed.
+func report(r Run) (int, error) {
+	path, reports, err := execute(r)
+	if err != nil {
+		return 0, err
 	}
-
-	_, errWriteString = f.WriteString(fmt.Sprintf("SequenceNr, SelectedIndex\n"))
-	if errWriteString != nil {
-		return "", errWriteString
+	if path != "-" {
+		fmt.Fprintln(os.Stderr, "file with results was generated:", path)
 	}
+	return reports.write(os.Stderr, r.Histogram)
+}
 
-	for i := int64(0); i < numbersToGenerate; i++ {
-		rnd, errRnr := random.DeterministicRandom(seed, i, probabilities)
-		if errRnr != nil {
-			return "", errRnr
-		}
-
-		_, errWriteString = f.WriteString(fmt.Sprintf("%v, %v\n", i, rnd))
-		if errWriteString != nil {
-			return "", errWriteString
-		}
+// testsFailed fails the simulator when statistical tests or the checks of a target failed, so CI catches it
+func testsFailed(failed int) error {
+	if failed > 0 {
+		return fmt.Errorf("%v statistical tests or target checks failed", failed)
 	}
-
-	return fileName, nil
+	return nil
 }
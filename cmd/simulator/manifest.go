@@ -0,0 +1,194 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"runtime/debug"
+	"slices"
+	"time"
+
+	"github.com/fasttrack-solutions/random"
+)
+
+// manifestSuffix is appended to the path of a results file for the path of its manifest
+const manifestSuffix = ".manifest.json"
+
+// Manifest describes a results file, it is written next to it. It holds the
+// fingerprint of the seed, never the seed itself.
+type Manifest struct {
+	Tool             string    `json:"tool"`
+	ToolVersion      string    `json:"toolVersion"`
+	AlgorithmVersion string    `json:"algorithmVersion"`
+	Created          time.Time `json:"created"`
+
+	Name            string    `json:"name,omitempty"`
+	Function        string    `json:"function"`
+	Count           int64     `json:"count"`
+	Min             *int32    `json:"min,omitempty"`
+	Max             *int32    `json:"max,omitempty"`
+	SeedFingerprint string    `json:"seedFingerprint,omitempty"`
+	Start           int64     `json:"start"`
+	Probabilities   []float64 `json:"probabilities,omitempty"`
+	Values          []float64 `json:"values,omitempty"`
+	Cost            float64   `json:"cost,omitempty"`
+	// Target and Namespace are set when the outcomes were drawn by a deployed service
+	Target    string `json:"target,omitempty"`
+	Namespace string `json:"namespace,omitempty"`
+	// Draws is the number of outcomes in the results, Count less the Failed
+	// draws the target did not return
+	Draws  int64 `json:"draws"`
+	Failed int64 `json:"failed,omitempty"`
+
+	Format      string `json:"format"`
+	Compression string `json:"compression"`
+	// ContentSHA256 and ContentSize are of the results before compression, they
+	// are the same for any compression, number of workers or resumption
+	ContentSHA256 string `json:"contentSha256"`
+	ContentSize   int64  `json:"contentSize"`
+}
+
+// newManifest describes the results of the run, of which failed draws failed at the target
+func newManifest(r Run, contentSHA256 string, contentSize int64, failed int64) (Manifest, error) {
+	m := Manifest{
+		Tool:             "simulator",
+		ToolVersion:      toolVersion(),
+		AlgorithmVersion: random.AlgorithmVersion,
+		Created:          time.Now().UTC(),
+		Name:             r.Name,
+		Function:         r.Function,
+		Count:            r.Count,
+		Draws:            r.Count - failed,
+		Failed:           failed,
+		Start:            r.Start,
+		Probabilities:    r.Probabilities,
+		Values:           r.Values,
+		Cost:             r.Cost,
+		Target:           r.Target,
+		Namespace:        r.Namespace,
+		Format:           r.Format,
+		Compression:      r.Compression,
+		ContentSHA256:    contentSHA256,
+		ContentSize:      contentSize,
+	}
+
+	switch r.Function {
+	case functionUniformInt64:
+		m.Min, m.Max = &r.Min, &r.Max
+	case functionDeterministicRandom:
+		fingerprint, err := random.SeedFingerprint(r.Seed)
+		if err != nil {
+			return Manifest{}, err
+		}
+		m.SeedFingerprint = fingerprint
+		if r.remote != nil {
+			m.AlgorithmVersion = r.remote.drawer.Version()
+		}
+	}
+	return m, nil
+}
+
+// write writes the manifest of the results at path
+func (m Manifest) write(path string) error {
+	data, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Clean(path+manifestSuffix), append(data, '\n'), 0600)
+}
+
+// readManifest reads the manifest at path
+func readManifest(path string) (Manifest, error) {
+	data, err := os.ReadFile(filepath.Clean(path))
+	if err != nil {
+		return Manifest{}, err
+	}
+
+	var m Manifest
+	err = json.Unmarshal(data, &m)
+	if err != nil {
+		return Manifest{}, fmt.Errorf("invalid manifest %s: %w", path, err)
+	} else if !slices.Contains(functions, m.Function) || !slices.Contains(formats, m.Format) {
+		return Manifest{}, fmt.Errorf("invalid manifest %s: unknown function %q or format %q", path, m.Function, m.Format)
+	} else if m.Failed < 0 || m.Failed > m.Count || (m.Draws != 0 && m.Draws != m.Count-m.Failed) {
+		return Manifest{}, fmt.Errorf("invalid manifest %s: %v draws and %v failed of %v", path, m.Draws, m.Failed, m.Count)
+	}
+	// Manifests written before draws were recorded hold every draw
+	m.Draws = m.Count - m.Failed
+	return m, nil
+}
+
+// run is the run that wrote the results of the manifest, without its seed
+func (m Manifest) run() Run {
+	r := Run{
+		Name:          m.Name,
+		Function:      m.Function,
+		Count:         m.Count,
+		Start:         m.Start,
+		Probabilities: m.Probabilities,
+		Format:        m.Format,
+		Compression:   m.Compression,
+	}
+	if m.Min != nil && m.Max != nil {
+		r.Min, r.Max = *m.Min, *m.Max
+	}
+	return r
+}
+
+// resultsReader reads results decompressed
+type resultsReader struct {
+	*bufio.Reader
+	f   *os.File
+	dec io.ReadCloser
+}
+
+// openManifestResults opens the results at path described by their manifest
+// m, it reads them decompressed and hashes them to content
+func openManifestResults(path string, m Manifest, content io.Writer) (*resultsReader, error) {
+	f, err := os.Open(filepath.Clean(path))
+	if err != nil {
+		return nil, err
+	}
+	dec, err := newDecompressor(f, m.Compression)
+	if err != nil {
+		_ = f.Close()
+		return nil, fmt.Errorf("cannot read %s: %w", path, err)
+	}
+	return &resultsReader{Reader: bufio.NewReaderSize(io.TeeReader(dec, content), 1<<20), f: f, dec: dec}, nil
+}
+
+// Close closes the results file
+func (r *resultsReader) Close() error {
+	_ = r.dec.Close()
+	return r.f.Close()
+}
+
+// toolVersion is the version of the module the simulator was built from, or its commit
+func toolVersion() string {
+	info, ok := debug.ReadBuildInfo()
+	if !ok {
+		return "unknown"
+	}
+	if info.Main.Version != "" && info.Main.Version != "(devel)" {
+		return info.Main.Version
+	}
+
+	revision, modified := "", false
+	for _, s := range info.Settings {
+		switch s.Key {
+		case "vcs.revision":
+			revision = s.Value
+		case "vcs.modified":
+			modified = s.Value == "true"
+		}
+	}
+	if revision == "" {
+		return "devel"
+	} else if modified {
+		return revision + "-dirty"
+	}
+	return revision
+}
@@ -0,0 +1,160 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/nexidian/gocliselect"
+)
+
+// runMenu asks for a run interactively and executes it like the flags do
+func runMenu() {
+	menu := gocliselect.NewMenu("Select test to run")
+
+	menu.AddItem("Random Uniform Float64 with range (0,1]", functionUniformFloat64)
+	menu.AddItem("Random Uniform Int with range (min, max)", functionUniformInt64)
+	menu.AddItem("Deterministic Random with seed and probabilities", functionDeterministicRandom)
+	menu.AddItem("Exit", "Exit")
+
+	choice := menu.Display()
+	if choice == "Exit" {
+		return
+	}
+
+	for {
+		r := Run{Function: choice}
+
+		res, errPrompt := stringPrompt("how many results do you want to generate?")
+		if errPrompt != nil {
+			fmt.Println("error getting results from prompt:", errPrompt)
+			continue
+		}
+
+		r.Count, errPrompt = strconv.ParseInt(res, 10, 64)
+		if errPrompt != nil {
+			fmt.Println(res, "is an invalid number, try again")
+			continue
+		}
+
+		switch choice {
+		case functionUniformInt64:
+			res, errPrompt = stringPrompt("whats the minimum number?")
+			if errPrompt != nil {
+				fmt.Println("error getting results from prompt:", errPrompt)
+				continue
+			}
+
+			minimumNumber, errParseIntMin := strconv.ParseInt(res, 10, 32)
+			if errParseIntMin != nil {
+				fmt.Println(res, "is an invalid number, try again")
+				continue
+			}
+
+			res, errPrompt = stringPrompt("whats the maximum number?")
+			if errPrompt != nil {
+				fmt.Println("error getting results from prompt:", errPrompt)
+				continue
+			}
+
+			maximumNumber, errParseIntMax := strconv.ParseInt(res, 10, 32)
+			if errParseIntMax != nil {
+				fmt.Println(res, "is an invalid number, try again")
+				continue
+			}
+
+			r.Min, r.Max = int32(minimumNumber), int32(maximumNumber) // #nosec G115 -- parsed as 32 bits
+
+		case functionDeterministicRandom:
+			r.Seed, errPrompt = stringPrompt("what seed should be used (i.e. 9912f3bcf715a55ae5c9d47f9f6562599912f3bcf715a55ae5c9d47f9f656259)?")
+			if errPrompt != nil {
+				fmt.Println("error getting results from prompt:", errPrompt)
+				continue
+			}
+
+			res, errPrompt = stringPrompt("what probabilities should be used (i.e. 0.3, 0.5, 0.2)?")
+			if errPrompt != nil {
+				fmt.Println("error getting results from prompt:", errPrompt)
+				continue
+			}
+
+			r.Probabilities, errPrompt = parseProbabilities(res)
+			if errPrompt != nil {
+				fmt.Println(errPrompt, "try again")
+				continue
+			}
+
+			res, errPrompt = stringPrompt("what does each index pay (i.e. 0, 5, 20), empty to skip the prize table?")
+			if errPrompt != nil {
+				fmt.Println("error getting results from prompt:", errPrompt)
+				continue
+			}
+
+			if len(res) > 0 {
+				r.Values, errPrompt = parseValues(res)
+				if errPrompt != nil {
+					fmt.Println(errPrompt, "try again")
+					continue
+				}
+
+				res, errPrompt = stringPrompt("what does a play cost (i.e. 1)?")
+				if errPrompt != nil {
+					fmt.Println("error getting results from prompt:", errPrompt)
+					continue
+				}
+
+				r.Cost, errPrompt = strconv.ParseFloat(res, 64)
+				if errPrompt != nil {
+					fmt.Println(res, "is an invalid number, try again")
+					continue
+				}
+			}
+		}
+
+		res, errPrompt = stringPrompt("run the statistical tests on the results (y/N)?")
+		if errPrompt != nil {
+			fmt.Println("error getting results from prompt:", errPrompt)
+			continue
+		}
+		// Exploring a run, the histogram shows its outcomes right away
+		r.Analyze = strings.EqualFold(res, "y") || strings.EqualFold(res, "yes")
+		r.Histogram = r.Analyze
+
+		// Check the answers before generating, a mistake asks again
+		errNormalize := r.normalize()
+		if errNormalize != nil {
+			fmt.Println(errNormalize, "try again")
+			continue
+		}
+
+		fmt.Print("generating ", r, "... ")
+
+		fileName, reports, errGenerate := execute(r)
+		if errGenerate != nil {
+			fmt.Print("error: ", errGenerate)
+			return
+		}
+
+		fmt.Println("file with results was generated:", fileName)
+		_, _ = reports.write(os.Stdout, r.Histogram)
+		return
+	}
+}
+
+func stringPrompt(label string) (string, error) {
+	var s string
+	r := bufio.NewReader(os.Stdin)
+	for {
+		_, err := fmt.Fprint(os.Stderr, label+" ")
+		if err != nil {
+			return "", err
+		}
+		s, _ = r.ReadString('\n')
+		if s != "" {
+			break
+		}
+	}
+	return strings.TrimSpace(s), nil
+}
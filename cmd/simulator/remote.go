@@ -0,0 +1,466 @@
+package main
+
+import (
+	"cmp"
+	"context"
+	"crypto/tls"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"text/tabwriter"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"github.com/fasttrack-solutions/random/internal/deterministic"
+	"github.com/fasttrack-solutions/random/internal/keyring"
+	"github.com/fasttrack-solutions/random/internal/stats"
+	"github.com/fasttrack-solutions/random/pkg/pb"
+)
+
+const (
+	// defaultTimeout is how long a draw from a target may take
+	defaultTimeout = 10 * time.Second
+	// defaultRemoteWorkers is the number of concurrent requests to a target
+	defaultRemoteWorkers = 16
+	// remoteShardSize is the number of draws a worker requests at a time, smaller
+	// than shardSize so the requests spread over the workers of short runs
+	remoteShardSize = 256
+)
+
+// errRemoteFailed is returned for a draw the target failed, it is counted and left out of the results
+var errRemoteFailed = errors.New("remote draw failed")
+
+// target draws outcomes from a deployed service
+type target interface {
+	uniformFloat64(ctx context.Context) (float64, error)
+	uniformInt64(ctx context.Context, minimum int32, maximum int32) (int64, error)
+	// deterministicRandom returns the outcome of the sequence and its proof with the vrf algorithm
+	deterministicRandom(ctx context.Context, sequence int64, probabilities []float64, namespace string, keyID string) (int64, []byte, error)
+	Close() error
+}
+
+// dialTarget connects to grpc://host:port, grpcs://host:port (TLS) or an http(s):// URL
+func dialTarget(address string, replayToken string, workers int) (target, error) {
+	u, err := url.Parse(address)
+	if err != nil {
+		return nil, fmt.Errorf("invalid target %q: %w", address, err)
+	}
+
+	switch u.Scheme {
+	case "grpc", "grpcs":
+		creds := insecure.NewCredentials()
+		if u.Scheme == "grpcs" {
+			creds = credentials.NewTLS(&tls.Config{MinVersion: tls.VersionTLS12})
+		}
+		conn, errDial := grpc.NewClient(u.Host, grpc.WithTransportCredentials(creds))
+		if errDial != nil {
+			return nil, errDial
+		}
+		return &grpcTarget{conn: conn, client: pb.NewRandomClient(conn), replayToken: replayToken}, nil
+	case "http", "https":
+		transport := http.DefaultTransport.(*http.Transport).Clone()
+		transport.MaxIdleConnsPerHost = workers
+		return &httpTarget{
+			base:        strings.TrimSuffix(u.String(), "/"),
+			client:      &http.Client{Transport: transport},
+			replayToken: replayToken,
+		}, nil
+	}
+	return nil, fmt.Errorf("invalid target %q, expected grpc://, grpcs://, http:// or https://", address)
+}
+
+// grpcTarget draws from the gRPC service
+type grpcTarget struct {
+	conn        *grpc.ClientConn
+	client      pb.RandomClient
+	replayToken string
+}
+
+func (t *grpcTarget) uniformFloat64(ctx context.Context) (float64, error) {
+	res, err := t.client.GetRandomFloat64(ctx, &pb.GetRandomFloat64Request{})
+	if err != nil {
+		return 0, err
+	}
+	return res.GetNumber(), nil
+}
+
+func (t *grpcTarget) uniformInt64(ctx context.Context, minimum int32, maximum int32) (int64, error) {
+	res, err := t.client.GetRandomInt64(ctx, &pb.GetRandomInt64Request{Min: minimum, Max: maximum})
+	if err != nil {
+		return 0, err
+	}
+	return res.GetNumber(), nil
+}
+
+func (t *grpcTarget) deterministicRandom(ctx context.Context, sequence int64, probabilities []float64, namespace string, keyID string) (int64, []byte, error) {
+	if len(t.replayToken) > 0 {
+		// A server that allocates the sequences only draws a given one for replay clients
+		ctx = metadata.AppendToOutgoingContext(ctx, "x-replay-token", t.replayToken)
+	}
+	res, err := t.client.GetDeterministicRandom(ctx, &pb.GetDeterministicRandomRequest{
+		Sequence:      sequence,
+		Probabilities: probabilities,
+		Namespace:     namespace,
+		KeyId:         keyID,
+	})
+	if err != nil {
+		return 0, nil, err
+	}
+	return res.GetNumber(), res.GetVrfProof(), nil
+}
+
+func (t *grpcTarget) Close() error {
+	return t.conn.Close()
+}
+
+// httpTarget draws from the HTTP service
+type httpTarget struct {
+	base        string
+	client      *http.Client
+	replayToken string
+}
+
+// httpStatusError is a response of the HTTP service other than 200 OK
+type httpStatusError struct {
+	code int
+	body string
+}
+
+func (e *httpStatusError) Error() string {
+	return fmt.Sprintf("HTTP %v: %s", e.code, e.body)
+}
+
+// get requests path with the query and returns the body and headers of the response
+func (t *httpTarget) get(ctx context.Context, path string, query url.Values) (string, http.Header, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, t.base+path+"?"+query.Encode(), nil)
+	if err != nil {
+		return "", nil, err
+	}
+	if len(t.replayToken) > 0 {
+		req.Header.Set("X-Replay-Token", t.replayToken)
+	}
+
+	res, err := t.client.Do(req)
+	if err != nil {
+		return "", nil, err
+	}
+	defer res.Body.Close()
+	body, err := io.ReadAll(io.LimitReader(res.Body, 4096))
+	if err != nil {
+		return "", nil, err
+	} else if res.StatusCode != http.StatusOK {
+		return "", nil, &httpStatusError{code: res.StatusCode, body: strings.TrimSpace(string(body))}
+	}
+	return strings.TrimSpace(string(body)), res.Header, nil
+}
+
+func (t *httpTarget) uniformFloat64(ctx context.Context) (float64, error) {
+	body, _, err := t.get(ctx, "/getRandomFloat64", nil)
+	if err != nil {
+		return 0, err
+	}
+	return strconv.ParseFloat(body, 64)
+}
+
+func (t *httpTarget) uniformInt64(ctx context.Context, minimum int32, maximum int32) (int64, error) {
+	body, _, err := t.get(ctx, "/getRandomInt64", url.Values{
+		"min": {strconv.FormatInt(int64(minimum), 10)},
+		"max": {strconv.FormatInt(int64(maximum), 10)},
+	})
+	if err != nil {
+		return 0, err
+	}
+	return strconv.ParseInt(body, 10, 64)
+}
+
+func (t *httpTarget) deterministicRandom(ctx context.Context, sequence int64, probabilities []float64, namespace string, keyID string) (int64, []byte, error) {
+	p := make([]string, len(probabilities))
+	for i, v := range probabilities {
+		p[i] = strconv.FormatFloat(v, 'g', -1, 64)
+	}
+	query := url.Values{"s": {strconv.FormatInt(sequence, 10)}, "p": {strings.Join(p, ",")}}
+	if len(namespace) > 0 {
+		query.Set("n", namespace)
+	}
+	if len(keyID) > 0 {
+		query.Set("k", keyID)
+	}
+
+	body, header, err := t.get(ctx, "/getDeterministicRandom", query)
+	if err != nil {
+		return 0, nil, err
+	}
+	number, err := strconv.ParseInt(body, 10, 64)
+	if err != nil {
+		return 0, nil, err
+	}
+	proof, err := hex.DecodeString(header.Get("X-VRF-Proof"))
+	return number, proof, err
+}
+
+func (t *httpTarget) Close() error {
+	t.client.CloseIdleConnections()
+	return nil
+}
+
+// remote draws the outcomes of a run from its target. It checks the outcomes of
+// DeterministicRandom against the library and records the latency and errors.
+type remote struct {
+	target  target
+	timeout time.Duration
+	latency *stats.Latency
+
+	// drawer and key compute the outcomes of DeterministicRandom locally with the seed of the run
+	drawer *deterministic.Drawer
+	key    keyring.Key
+
+	mu            sync.Mutex
+	errors        map[string]int64
+	firstError    string
+	checked       int64
+	mismatches    int64
+	firstMismatch *Mismatch
+}
+
+// newRemote connects to the target of the run
+func newRemote(r Run) (*remote, error) {
+	t, err := dialTarget(r.Target, r.ReplayToken, r.Workers)
+	if err != nil {
+		return nil, err
+	}
+	rm := &remote{target: t, timeout: r.timeout, latency: stats.NewLatency(), errors: make(map[string]int64)}
+
+	if r.Function == functionDeterministicRandom {
+		ring, errRing := keyring.Single(r.Seed)
+		if errRing == nil {
+			rm.drawer, errRing = deterministic.New(ring, r.Algorithm, r.Derivation)
+		}
+		if errRing == nil {
+			rm.key, errRing = rm.drawer.Key("", 0)
+		}
+		if errRing != nil {
+			_ = t.Close()
+			return nil, errRing
+		}
+	}
+	return rm, nil
+}
+
+// draw requests the outcome of draw i of the run from the target, it returns
+// errRemoteFailed when the request failed
+func (rm *remote) draw(r Run, i int64) (float64, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), rm.timeout)
+	defer cancel()
+
+	var v float64
+	var number int64
+	var proof []byte
+	var err error
+	began := time.Now()
+	switch r.Function {
+	case functionUniformInt64:
+		number, err = rm.target.uniformInt64(ctx, r.Min, r.Max)
+		v = float64(number)
+	case functionDeterministicRandom:
+		number, proof, err = rm.target.deterministicRandom(ctx, r.Start+i, r.Probabilities, r.Namespace, r.KeyID)
+		v = float64(number)
+	default:
+		v, err = rm.target.uniformFloat64(ctx)
+	}
+	if err != nil {
+		rm.fail(err)
+		return 0, errRemoteFailed
+	}
+	rm.latency.Add(time.Since(began))
+
+	if rm.drawer != nil {
+		local, localProof, errLocal := rm.drawer.Draw(rm.key, r.Namespace, r.Start+i, r.Probabilities)
+		if errLocal != nil {
+			return 0, errLocal
+		}
+		// The ECVRF proofs are deterministic too, so a proof from another key does not match
+		rm.check(Mismatch{Sequence: r.Start + i, Local: local, Remote: number, ProofDiffers: !slices.Equal(localProof, proof)})
+	}
+	return v, nil
+}
+
+// fail counts a failed request by the kind of its error
+func (rm *remote) fail(err error) {
+	kind := "error"
+	var statusErr *httpStatusError
+	if errors.Is(err, context.DeadlineExceeded) {
+		kind = "timeout"
+	} else if errors.As(err, &statusErr) {
+		kind = fmt.Sprintf("HTTP %v", statusErr.code)
+	} else if s, ok := status.FromError(err); ok {
+		kind = s.Code().String()
+	}
+
+	rm.mu.Lock()
+	defer rm.mu.Unlock()
+	rm.errors[kind]++
+	if len(rm.firstError) == 0 {
+		rm.firstError = err.Error()
+	}
+}
+
+// check counts an outcome compared with the library, the first mismatch is kept.
+// The draws complete out of order, so it is the one with the lowest sequence.
+func (rm *remote) check(m Mismatch) {
+	rm.mu.Lock()
+	defer rm.mu.Unlock()
+	rm.checked++
+	if m.Local == m.Remote && !m.ProofDiffers {
+		return
+	}
+	rm.mismatches++
+	if rm.firstMismatch == nil || m.Sequence < rm.firstMismatch.Sequence {
+		rm.firstMismatch = &m
+	}
+}
+
+// report summarizes the requests to the target
+func (rm *remote) report(r Run) RemoteReport {
+	rm.mu.Lock()
+	defer rm.mu.Unlock()
+
+	report := RemoteReport{
+		Target:        r.Target,
+		ErrorsByKind:  rm.errors,
+		FirstError:    rm.firstError,
+		Checked:       rm.checked,
+		Mismatches:    rm.mismatches,
+		FirstMismatch: rm.firstMismatch,
+		Latency:       rm.latency.Report(),
+	}
+	for _, n := range rm.errors {
+		report.Errors += n
+	}
+	report.Requests = report.Latency.Count + report.Errors
+	return report
+}
+
+// Close disconnects from the target
+func (rm *remote) Close() error {
+	return rm.target.Close()
+}
+
+// Mismatch is an outcome of the target that differs from the one of the library
+type Mismatch struct {
+	Sequence int64 `json:"sequence"`
+	Local    int64 `json:"local"`
+	Remote   int64 `json:"remote"`
+	// ProofDiffers is set when the vrf proofs differ
+	ProofDiffers bool `json:"proofDiffers,omitempty"`
+}
+
+// RemoteReport is the report of the requests to a target
+type RemoteReport struct {
+	Target   string `json:"target"`
+	Requests int64  `json:"requests"`
+	// Errors counts the failed requests, they are left out of the results
+	Errors       int64            `json:"errors"`
+	ErrorsByKind map[string]int64 `json:"errorsByKind,omitempty"`
+	FirstError   string           `json:"firstError,omitempty"`
+	// Checked counts the outcomes of DeterministicRandom compared with the library, Mismatches those that differ
+	Checked       int64               `json:"checked"`
+	Mismatches    int64               `json:"mismatches"`
+	FirstMismatch *Mismatch           `json:"firstMismatch,omitempty"`
+	Latency       stats.LatencyReport `json:"latency"`
+}
+
+// Failed reports whether a request failed or an outcome differs from the library
+func (r RemoteReport) Failed() bool {
+	return r.Errors > 0 || r.Mismatches > 0
+}
+
+// WriteText writes the report as lines of text
+func (r RemoteReport) WriteText(w io.Writer) error {
+	_, err := fmt.Fprintf(w, "%v requests to %s, %v failed\n", r.Requests, r.Target, r.Errors)
+	if err != nil {
+		return err
+	}
+	if len(r.ErrorsByKind) > 0 {
+		kinds := make([]string, 0, len(r.ErrorsByKind))
+		for kind := range r.ErrorsByKind {
+			kinds = append(kinds, kind)
+		}
+		slices.Sort(kinds)
+		tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+		for _, kind := range kinds {
+			fmt.Fprintf(tw, "  %s\t%v\n", kind, r.ErrorsByKind[kind])
+		}
+		err = tw.Flush()
+		if err != nil {
+			return err
+		}
+		_, err = fmt.Fprintf(w, "first error: %s\n", r.FirstError)
+		if err != nil {
+			return err
+		}
+	}
+	if r.Latency.Count > 0 {
+		err = r.Latency.WriteText(w)
+		if err != nil {
+			return err
+		}
+	}
+	if r.Checked == 0 {
+		return nil
+	}
+
+	if r.Mismatches == 0 {
+		_, err = fmt.Fprintf(w, "all %v outcomes match the library\n", r.Checked)
+		return err
+	}
+	first := r.FirstMismatch
+	proof := ""
+	if first.ProofDiffers {
+		proof = ", the proofs differ"
+	}
+	_, err = fmt.Fprintf(w, "%v of %v outcomes differ from the library, first at sequence %v: %v remote, %v local%s\n",
+		r.Mismatches, r.Checked, first.Sequence, first.Remote, first.Local, proof)
+	return err
+}
+
+// normalizeTarget checks the options of the target of the run and fills in their defaults
+func (r *Run) normalizeTarget() error {
+	if len(r.Target) == 0 {
+		if len(r.Namespace) > 0 || len(r.KeyID) > 0 || len(r.Algorithm) > 0 || len(r.Derivation) > 0 || len(r.ReplayToken) > 0 || len(r.Timeout) > 0 {
+			return errors.New("namespace, key id, algorithm, derivation, replay token and timeout require a target")
+		}
+		return nil
+	} else if len(r.Checkpoint) > 0 {
+		return errors.New("a run against a target cannot be resumed from a checkpoint")
+	}
+
+	r.Timeout = cmp.Or(r.Timeout, defaultTimeout.String())
+	timeout, err := time.ParseDuration(r.Timeout)
+	if err != nil || timeout <= 0 {
+		return fmt.Errorf("invalid timeout %q", r.Timeout)
+	}
+	r.timeout = timeout
+
+	if r.Function != functionDeterministicRandom {
+		if len(r.Namespace) > 0 || len(r.KeyID) > 0 || len(r.Algorithm) > 0 || len(r.Derivation) > 0 {
+			return errors.New("namespace, key id, algorithm and derivation only apply to DeterministicRandom")
+		}
+		return nil
+	}
+	r.Algorithm = cmp.Or(strings.ToLower(r.Algorithm), deterministic.AlgorithmSHA256)
+	r.Derivation = cmp.Or(strings.ToLower(r.Derivation), deterministic.DerivationNone)
+	_, err = deterministic.New(nil, r.Algorithm, r.Derivation)
+	return err
+}
@@ -0,0 +1,583 @@
+package main
+
+import (
+	"bufio"
+	"cmp"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/fasttrack-solutions/random/internal/stats"
+)
+
+const (
+	functionUniformFloat64      = "UniformFloat64"
+	functionUniformInt64        = "UniformInt64"
+	functionDeterministicRandom = "DeterministicRandom"
+)
+
+var functions = []string{functionUniformFloat64, functionUniformInt64, functionDeterministicRandom}
+
+const (
+	// formatCSV writes a row of column names before the outcomes: sequence,index for DeterministicRandom and value for the others
+	formatCSV = "csv"
+	// formatJSONL writes a JSON object per outcome with the same fields as the columns of formatCSV
+	formatJSONL = "jsonl"
+	// formatValues writes only the outcomes, one per line, to pipe them into other tools
+	formatValues = "values"
+)
+
+var formats = []string{formatCSV, formatJSONL, formatValues}
+
+// resultsDir is where results are written when a run has no output path
+const resultsDir = "cmd/simulator/results"
+
+const (
+	// histogramRows is the most rows of the histogram of a run, neighbouring buckets are merged into a row
+	histogramRows = 50
+	// histogramWidth is the number of characters of the longest bar of the histogram of a run
+	histogramWidth = 50
+)
+
+// Run is one simulation: the function to draw, its parameters and where to write the outcomes
+type Run struct {
+	Name     string `json:"name" yaml:"name"`
+	Function string `json:"function" yaml:"function"`
+	Count    int64  `json:"count" yaml:"count"`
+
+	// Min and Max are the range of UniformInt64
+	Min int32 `json:"min" yaml:"min"`
+	Max int32 `json:"max" yaml:"max"`
+
+	// Seed or SeedFile, Start and Probabilities are the parameters of DeterministicRandom,
+	// the sequence numbers Start to Start+Count-1 are drawn
+	Seed          string    `json:"seed" yaml:"seed"`
+	SeedFile      string    `json:"seedFile" yaml:"seedFile"`
+	Start         int64     `json:"start" yaml:"start"`
+	Probabilities []float64 `json:"probabilities" yaml:"probabilities"`
+
+	// Values are the payouts of the indexes of DeterministicRandom and Cost the cost of a play, setting
+	// them simulates the prize table. The exposure is measured over campaigns of CampaignSize plays and
+	// the intervals are at Confidence.
+	Values       []float64 `json:"values" yaml:"values"`
+	Cost         float64   `json:"cost" yaml:"cost"`
+	CampaignSize int64     `json:"campaignSize" yaml:"campaignSize"`
+	Confidence   float64   `json:"confidence" yaml:"confidence"`
+
+	// Output is the path of the results file, "-" for stdout, a file in resultsDir when empty
+	Output string `json:"output" yaml:"output"`
+	Format string `json:"format" yaml:"format"`
+	// Compression is none, gzip or zstd, by the extension of Output when empty
+	Compression string `json:"compression" yaml:"compression"`
+
+	// Workers is the number of goroutines drawing the outcomes, GOMAXPROCS when 0. The results are the same for any number.
+	Workers int `json:"workers" yaml:"workers"`
+	// Checkpoint is the path where the progress of the run is saved, the run resumes from it when it exists
+	Checkpoint string `json:"checkpoint" yaml:"checkpoint"`
+
+	// Target draws the outcomes from a deployed service instead of the library: grpc://host:port,
+	// grpcs://host:port or an http(s):// URL. The outcomes of DeterministicRandom are checked against
+	// the library with the seed, the service draws Namespace with the key KeyID, the active key when
+	// empty, by Algorithm and Derivation. ReplayToken is sent to a service that allocates the
+	// sequences and Timeout, i.e. 5s, limits every request.
+	Target      string `json:"target" yaml:"target"`
+	Namespace   string `json:"namespace" yaml:"namespace"`
+	KeyID       string `json:"keyId" yaml:"keyId"`
+	Algorithm   string `json:"algorithm" yaml:"algorithm"`
+	Derivation  string `json:"derivation" yaml:"derivation"`
+	ReplayToken string `json:"replayToken" yaml:"replayToken"`
+	Timeout     string `json:"timeout" yaml:"timeout"`
+
+	// Analyze runs the statistical tests on the outcomes, a test fails below Alpha.
+	// Report is the path of the report in JSON and Histogram prints the histogram
+	// of the outcomes with the report, setting either enables Analyze.
+	Analyze   bool    `json:"analyze" yaml:"analyze"`
+	Alpha     float64 `json:"alpha" yaml:"alpha"`
+	Report    string  `json:"report" yaml:"report"`
+	Histogram bool    `json:"histogram" yaml:"histogram"`
+
+	// timeout is Timeout parsed and remote the connection to Target while the run executes
+	timeout time.Duration
+	remote  *remote
+}
+
+// String describes the run in messages
+func (r Run) String() string {
+	switch r.Function {
+	case functionUniformInt64:
+		return fmt.Sprintf("%v %s between %v and %v", r.Count, r.Function, r.Min, r.Max)
+	case functionDeterministicRandom:
+		return fmt.Sprintf("%v %s from sequence %v with probabilities %v", r.Count, r.Function, r.Start, r.Probabilities)
+	default:
+		return fmt.Sprintf("%v %s", r.Count, r.Function)
+	}
+}
+
+// normalize checks the run, resolves its seed and fills in the defaults
+func (r *Run) normalize() error {
+	for _, f := range functions {
+		if strings.EqualFold(r.Function, f) {
+			r.Function = f
+		}
+	}
+	if !slices.Contains(functions, r.Function) {
+		return fmt.Errorf("unknown function %q, expected one of %s", r.Function, strings.Join(functions, ", "))
+	} else if r.Count < 1 {
+		return errors.New("count must be at least 1")
+	}
+
+	inferred := formatOf(r.Output)
+	r.Format = cmp.Or(strings.ToLower(r.Format), inferred, formatCSV)
+	if !slices.Contains(formats, r.Format) {
+		return fmt.Errorf("unknown format %q, expected one of %s", r.Format, strings.Join(formats, ", "))
+	} else if len(inferred) > 0 && r.Format != inferred {
+		return fmt.Errorf("format %s contradicts the extension of %s, a %s file", r.Format, r.Output, inferred)
+	}
+	r.Compression = cmp.Or(strings.ToLower(r.Compression), compressionOf(r.Output))
+	if r.Compression != compressionNone && r.Compression != compressionGzip && r.Compression != compressionZstd {
+		return fmt.Errorf("unknown compression %q, expected %s, %s or %s", r.Compression, compressionNone, compressionGzip, compressionZstd)
+	}
+
+	if len(r.Target) > 0 {
+		r.Workers = cmp.Or(r.Workers, defaultRemoteWorkers)
+	}
+	r.Workers = cmp.Or(r.Workers, runtime.GOMAXPROCS(0))
+	if r.Workers < 1 {
+		return errors.New("workers must be at least 1")
+	} else if len(r.Checkpoint) > 0 && (len(r.Output) == 0 || r.Output == "-") {
+		return errors.New("a checkpoint requires an output file")
+	}
+
+	err := r.normalizeTarget()
+	if err != nil {
+		return err
+	}
+
+	r.Analyze = r.Analyze || len(r.Report) > 0 || r.Histogram
+	r.Alpha = cmp.Or(r.Alpha, stats.DefaultAlpha)
+	if r.Alpha <= 0 || r.Alpha >= 1 {
+		return errors.New("alpha must be between 0 and 1")
+	}
+
+	if r.Function != functionDeterministicRandom && (len(r.Values) > 0 || r.Cost != 0 || r.CampaignSize != 0 || r.Confidence != 0) {
+		return errors.New("values, cost, campaign size and confidence only apply to DeterministicRandom")
+	} else if len(r.Values) == 0 && (r.Cost != 0 || r.CampaignSize != 0 || r.Confidence != 0) {
+		return errors.New("cost, campaign size and confidence require values")
+	}
+
+	switch r.Function {
+	case functionUniformInt64:
+		if r.Max <= r.Min {
+			return errors.New("maximum cannot be less than or equal to minimum number")
+		}
+	case functionDeterministicRandom:
+		if len(r.SeedFile) > 0 {
+			if len(r.Seed) > 0 {
+				return errors.New("seed and seed file are mutually exclusive")
+			}
+			b, err := os.ReadFile(filepath.Clean(r.SeedFile))
+			if err != nil {
+				return err
+			}
+			r.Seed = strings.TrimSpace(string(b))
+			r.SeedFile = ""
+		}
+		if len(r.Seed) != 64 {
+			return errors.New("the seed needs to be 64 characters [a-f0-9]")
+		} else if r.Start < 0 {
+			return errors.New("start must be at least 0")
+		} else if len(r.Probabilities) == 0 {
+			return errors.New("no probabilities set")
+		}
+
+		if len(r.Values) > 0 {
+			r.CampaignSize = cmp.Or(r.CampaignSize, stats.DefaultCampaignSize)
+			r.Confidence = cmp.Or(r.Confidence, stats.DefaultConfidence)
+			if r.Confidence <= 0 || r.Confidence >= 1 {
+				return errors.New("confidence must be between 0 and 1")
+			}
+			_, err := stats.NewPayout(r.Probabilities, r.Values, r.Cost, r.CampaignSize)
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// Reports are the reports of a run, in the JSON of the report file the tests are at the top level
+type Reports struct {
+	// Report is the report of the statistical tests, nil when the run does not analyze its outcomes
+	*stats.Report
+	// Payout is the report of the prize table, nil when the run has no values
+	Payout *stats.PayoutReport `json:"payout,omitempty"`
+	// Remote is the report of the requests to the target, nil when the run draws with the library
+	Remote *RemoteReport `json:"remote,omitempty"`
+}
+
+// write writes the reports the run produced as text, with the histogram of the
+// outcomes first when histogram is set. It returns the number of statistical
+// tests that failed, plus one when the target failed its checks.
+func (reports Reports) write(w io.Writer, histogram bool) (int, error) {
+	failed := 0
+	if reports.Report != nil {
+		if histogram {
+			err := reports.Report.Histogram.Merge(histogramRows).WriteText(w, histogramWidth)
+			if err != nil {
+				return 0, err
+			}
+		}
+		err := reports.Report.WriteText(w)
+		if err != nil {
+			return 0, err
+		}
+		failed = reports.Report.Failed()
+	}
+	if reports.Payout != nil {
+		err := reports.Payout.WriteText(w)
+		if err != nil {
+			return failed, err
+		}
+	}
+	if reports.Remote != nil {
+		err := reports.Remote.WriteText(w)
+		if err != nil {
+			return failed, err
+		}
+		if reports.Remote.Failed() {
+			failed++
+		}
+	}
+	return failed, nil
+}
+
+// execute draws the outcomes of the run and writes them, it returns the path of
+// the results and the reports of the statistical tests and of the prize table
+func execute(r Run) (string, Reports, error) {
+	err := r.normalize()
+	if err != nil {
+		return "", Reports{}, err
+	}
+
+	if len(r.Target) > 0 {
+		r.remote, err = newRemote(r)
+		if err != nil {
+			return "", Reports{}, err
+		}
+		defer r.remote.Close()
+	}
+
+	var b *stats.Battery
+	if r.Analyze {
+		b, err = newBattery(r)
+		if err != nil {
+			return "", Reports{}, err
+		}
+	}
+	var p *stats.Payout
+	if len(r.Values) > 0 {
+		p, err = stats.NewPayout(r.Probabilities, r.Values, r.Cost, r.CampaignSize)
+		if err != nil {
+			return "", Reports{}, err
+		}
+	}
+
+	feed := func(v float64) error {
+		if b != nil {
+			b.Add(v)
+		}
+		if p != nil {
+			return p.Add(int64(v))
+		}
+		return nil
+	}
+
+	// content hashes the results before compression for the manifest
+	content := sha256.New()
+	f, path, cp, err := openResults(r, content, feed)
+	if err != nil {
+		return path, Reports{}, err
+	}
+	w := io.Writer(os.Stdout)
+	if f != nil {
+		w = f
+	}
+	size, err := writeResults(w, f, r, cp, content, feed)
+	if f != nil {
+		err = cmp.Or(err, f.Close())
+	}
+	if err != nil {
+		return path, Reports{}, err
+	}
+	var remoteReport *RemoteReport
+	if r.remote != nil {
+		report := r.remote.report(r)
+		remoteReport = &report
+	}
+	if f != nil {
+		failed := int64(0)
+		if remoteReport != nil {
+			failed = remoteReport.Errors
+		}
+		m, errManifest := newManifest(r, hex.EncodeToString(content.Sum(nil)), size, failed)
+		if errManifest != nil {
+			return path, Reports{}, errManifest
+		}
+		err = m.write(path)
+		if err != nil {
+			return path, Reports{}, err
+		}
+	}
+	if len(r.Checkpoint) > 0 {
+		// The run is complete, a next run starts over
+		err = os.Remove(filepath.Clean(r.Checkpoint))
+		if err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return path, Reports{}, err
+		}
+	}
+
+	var reports Reports
+	if b != nil {
+		report := b.Report(r.Alpha)
+		reports.Report = &report
+	}
+	if p != nil {
+		report := p.Report(r.Confidence)
+		reports.Payout = &report
+	}
+	reports.Remote = remoteReport
+	if len(r.Report) > 0 {
+		data, errMarshal := json.MarshalIndent(reports, "", "  ")
+		if errMarshal != nil {
+			return path, reports, errMarshal
+		}
+		err = os.WriteFile(filepath.Clean(r.Report), append(data, '\n'), 0600)
+	}
+	return path, reports, err
+}
+
+// newBattery creates the statistical tests of the distribution of the function of the run
+func newBattery(r Run) (*stats.Battery, error) {
+	switch r.Function {
+	case functionUniformInt64:
+		return stats.NewUniformInt64(int64(r.Min), int64(r.Max))
+	case functionDeterministicRandom:
+		return stats.NewDiscrete(r.Probabilities)
+	default:
+		return stats.NewUniformFloat64(), nil
+	}
+}
+
+// createResultsFile creates a new file in resultsDir named after the run and the time, with extension ext
+func createResultsFile(name string, ext string) (*os.File, string, error) {
+	err := os.MkdirAll(resultsDir, 0750)
+	if err != nil {
+		return nil, "", fmt.Errorf("error creating results directory: %w", err)
+	}
+
+	base := fmt.Sprintf("%s-%v", name, time.Now().UnixMilli())
+	for i := 0; ; i++ {
+		path := filepath.Join(resultsDir, base+ext)
+		if i > 0 {
+			path = filepath.Join(resultsDir, fmt.Sprintf("%s-%v%s", base, i, ext))
+		}
+
+		f, err := os.OpenFile(filepath.Clean(path), os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0600)
+		if errors.Is(err, fs.ErrExist) {
+			continue
+		} else if err != nil {
+			return nil, "", err
+		}
+		return f, path, nil
+	}
+}
+
+// openResults opens the results file of the run, nil when writing to stdout. With
+// the checkpoint of an interrupted run it replays the results up to the checkpoint
+// to content and feed and returns the checkpoint to resume from.
+func openResults(r Run, content io.Writer, feed func(v float64) error) (*os.File, string, *checkpoint, error) {
+	switch r.Output {
+	case "-":
+		return nil, r.Output, nil, nil
+	case "":
+		f, path, err := createResultsFile(cmp.Or(r.Name, r.Function), r.extension())
+		return f, path, nil, err
+	}
+
+	if len(r.Checkpoint) > 0 {
+		cp, err := loadCheckpoint(r.Checkpoint, r)
+		if err != nil {
+			return nil, r.Output, nil, err
+		}
+		if cp != nil {
+			f, errResume := resumeResults(r, cp, content, feed)
+			return f, r.Output, cp, errResume
+		}
+	}
+
+	err := os.MkdirAll(filepath.Dir(r.Output), 0750)
+	if err != nil {
+		return nil, r.Output, nil, err
+	}
+	f, err := os.Create(filepath.Clean(r.Output))
+	return f, r.Output, nil, err
+}
+
+// resumeResults replays the results of an interrupted run up to its checkpoint and drops what follows it
+func resumeResults(r Run, cp *checkpoint, content io.Writer, feed func(v float64) error) (*os.File, error) {
+	f, err := os.OpenFile(filepath.Clean(r.Output), os.O_RDWR, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	err = replay(f, r, cp, content, feed)
+	if err != nil {
+		_ = f.Close()
+		return nil, fmt.Errorf("cannot resume %s from checkpoint %s: %w", r.Output, r.Checkpoint, err)
+	}
+	err = f.Truncate(cp.Offset)
+	if err == nil {
+		_, err = f.Seek(cp.Offset, io.SeekStart)
+	}
+	if err != nil {
+		_ = f.Close()
+		return nil, err
+	}
+
+	fmt.Fprintf(os.Stderr, "resuming %s at draw %v of %v\n", r.Output, cp.Draws, r.Count)
+	return f, nil
+}
+
+// writeResults draws the outcomes of the run, from the checkpoint when resuming,
+// and writes them to w compressed. content receives them before compression, the
+// size of which is returned. With a checkpoint path the compressed stream is
+// ended, the results file f synced and the checkpoint saved every checkpointInterval.
+func writeResults(w io.Writer, f *os.File, r Run, cp *checkpoint, content io.Writer, feed func(v float64) error) (int64, error) {
+	from, offset, size := int64(0), int64(0), int64(0)
+	if cp != nil {
+		from, offset, size = cp.Draws, cp.Offset, cp.Size
+	}
+	cw := &countingWriter{w: w, n: offset}
+	comp, err := newCompressor(cw, r.Compression)
+	if err != nil {
+		return 0, err
+	}
+	bw := bufio.NewWriterSize(comp, 1<<20)
+	out := &countingWriter{w: io.MultiWriter(bw, content), n: size}
+	if cp == nil {
+		_, err = io.WriteString(out, r.header())
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	prog := newProgress(r.Count, from)
+	defer prog.finish()
+	saved := time.Now()
+	err = generate(out, r, from, feed, func(draws int64) error {
+		prog.update(draws)
+		if len(r.Checkpoint) == 0 || time.Since(saved) < checkpointInterval {
+			return nil
+		}
+		saved = time.Now()
+
+		// The results up to the checkpoint are on disk before the checkpoint is
+		errFlush := bw.Flush()
+		if errFlush != nil {
+			return errFlush
+		}
+		errFlush = comp.Close()
+		if errFlush != nil {
+			return errFlush
+		}
+		errFlush = f.Sync()
+		if errFlush != nil {
+			return errFlush
+		}
+		errFlush = checkpoint{Run: r.identity(), Draws: draws, Offset: cw.n, Size: out.n}.save(r.Checkpoint)
+		if errFlush != nil {
+			return errFlush
+		}
+
+		comp, errFlush = newCompressor(cw, r.Compression)
+		bw.Reset(comp)
+		return errFlush
+	})
+	if err != nil {
+		return 0, err
+	}
+	err = bw.Flush()
+	if err != nil {
+		return 0, err
+	}
+	return out.n, comp.Close()
+}
+
+// extension is the extension of the results file of the run by its format and compression
+func (r Run) extension() string {
+	ext := ".csv"
+	switch r.Format {
+	case formatJSONL:
+		ext = ".jsonl"
+	case formatValues:
+		ext = ".txt"
+	}
+	return ext + compressionExtension(r.Compression)
+}
+
+// formatOf is the format of a results file by the extension of its path before
+// the extension of its compression, empty for other extensions
+func formatOf(path string) string {
+	if compressionOf(path) != compressionNone {
+		path = strings.TrimSuffix(path, filepath.Ext(path))
+	}
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".csv":
+		return formatCSV
+	case ".jsonl":
+		return formatJSONL
+	case ".txt":
+		return formatValues
+	default:
+		return ""
+	}
+}
+
+// parseProbabilities parses comma separated probabilities, i.e. "0.3, 0.5, 0.2"
+func parseProbabilities(s string) ([]float64, error) {
+	return parseFloats(s, "probability", "probabilities")
+}
+
+// parseValues parses the comma separated values of the indexes, i.e. "0, 5, 20"
+func parseValues(s string) ([]float64, error) {
+	return parseFloats(s, "value", "values")
+}
+
+// parseFloats parses comma separated floats, the names of one and of several are used in errors
+func parseFloats(s string, singular string, plural string) ([]float64, error) {
+	if len(strings.TrimSpace(s)) == 0 {
+		return nil, fmt.Errorf("no %s set", plural)
+	}
+
+	parts := strings.Split(s, ",")
+	floats := make([]float64, len(parts))
+	for i, v := range parts {
+		f, err := strconv.ParseFloat(strings.TrimSpace(v), 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s: %w", singular, err)
+		}
+		floats[i] = f
+	}
+	return floats, nil
+}
@@ -0,0 +1,194 @@
+package main
+
+import (
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const testSeed = "0f6aa358754a2b1fac2206849282b6c68dd6e086f635df2796acea52ae920ddc"
+
+// testRun draws more outcomes than a shard holds, so several workers share the run
+func testRun(output string) Run {
+	return Run{
+		Function:      functionDeterministicRandom,
+		Count:         3*shardSize + 123,
+		Seed:          testSeed,
+		Start:         1000,
+		Probabilities: []float64{0.5, 0.3, 0.15, 0.05},
+		Values:        []float64{0, 1, 5, 20},
+		Cost:          1,
+		Output:        output,
+		Analyze:       true,
+	}
+}
+
+func Test_Execute_Workers(t *testing.T) {
+	dir := t.TempDir()
+	var results [][]byte
+	var reports []Reports
+	for _, workers := range []int{1, 8} {
+		for _, ext := range []string{".csv", ".csv.gz"} {
+			r := testRun(filepath.Join(dir, strings.Repeat("w", workers)+ext))
+			r.Workers = workers
+			path, rep, err := execute(r)
+			assert.Nil(t, err)
+			assert.Equal(t, r.Output, path)
+
+			m, err := readManifest(path + manifestSuffix)
+			assert.Nil(t, err)
+			content, err := readResults(path, m)
+			assert.Nil(t, err)
+			results = append(results, content)
+			reports = append(reports, rep)
+		}
+	}
+
+	// The outcomes, their order and the reports do not depend on the number of workers or the compression
+	assert.Equal(t, "sequence,index\n1000,", string(results[0][:20]))
+	assert.Equal(t, testRun("").Count, reports[0].Samples)
+	for i := 1; i < len(results); i++ {
+		assert.Equal(t, results[0], results[i])
+		assert.Equal(t, reports[0], reports[i])
+	}
+}
+
+func Test_Execute_Resume(t *testing.T) {
+	dir := t.TempDir()
+	full := testRun(filepath.Join(dir, "full.csv"))
+	_, want, err := execute(full)
+	assert.Nil(t, err)
+	content, err := os.ReadFile(full.Output)
+	assert.Nil(t, err)
+	lines := strings.SplitAfter(string(content), "\n")
+
+	for _, compression := range []string{compressionNone, compressionGzip} {
+		r := testRun(filepath.Join(dir, "resumed.csv"+compressionExtension(compression)))
+		r.Checkpoint = filepath.Join(dir, "resumed.checkpoint")
+		normalized := r
+		assert.Nil(t, normalized.normalize())
+
+		// An interrupted run: the header and 1000 draws up to the checkpoint, then a partial line
+		draws := int64(1000)
+		saved := strings.Join(lines[:draws+1], "")
+		var file bytes.Buffer
+		if compression == compressionGzip {
+			gw := gzip.NewWriter(&file)
+			_, err = gw.Write([]byte(saved))
+			assert.Nil(t, err)
+			assert.Nil(t, gw.Close())
+		} else {
+			file.WriteString(saved)
+		}
+		offset := int64(file.Len())
+		file.WriteString(lines[draws+1][:3])
+		assert.Nil(t, os.WriteFile(r.Output, file.Bytes(), 0600))
+		assert.Nil(t, checkpoint{Run: normalized.identity(), Draws: draws, Offset: offset, Size: int64(len(saved))}.save(r.Checkpoint))
+
+		_, got, err := execute(r)
+		assert.Nil(t, err)
+		assert.Equal(t, want, got)
+
+		m, err := readManifest(r.Output + manifestSuffix)
+		assert.Nil(t, err)
+		resumed, err := readResults(r.Output, m)
+		assert.Nil(t, err)
+		assert.Equal(t, content, resumed)
+		assert.NoFileExists(t, r.Checkpoint)
+	}
+
+	// A checkpoint of other parameters does not resume
+	r := testRun(filepath.Join(dir, "other.csv"))
+	r.Checkpoint = filepath.Join(dir, "other.checkpoint")
+	assert.Nil(t, checkpoint{Run: "other", Draws: 1, Offset: 1}.save(r.Checkpoint))
+	_, _, err = execute(r)
+	assert.ErrorContains(t, err, "other parameters")
+}
+
+func Test_Execute_RemoteFailures(t *testing.T) {
+	// The target fails every fourth request
+	var requests atomic.Int64
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if requests.Add(1)%4 == 0 {
+			http.Error(w, "unavailable", http.StatusServiceUnavailable)
+			return
+		}
+		fmt.Fprintln(w, "3")
+	}))
+	defer srv.Close()
+
+	r := Run{Function: functionUniformInt64, Count: 40, Min: 1, Max: 6, Target: srv.URL, Output: filepath.Join(t.TempDir(), "remote.csv")}
+	_, reports, err := execute(r)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(10), reports.Remote.Errors)
+
+	m, err := readManifest(r.Output + manifestSuffix)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(40), m.Count)
+	assert.Equal(t, int64(30), m.Draws)
+	assert.Equal(t, int64(10), m.Failed)
+	content, err := readResults(r.Output, m)
+	assert.Nil(t, err)
+	assert.Equal(t, "value\n"+strings.Repeat("3\n", 30), string(content))
+
+	// The written draws are in range, the failed ones are reported rather than missing
+	out, err := captureStdout(t, func() error { return runVerify([]string{r.Output}) })
+	assert.ErrorContains(t, err, "do not match")
+	assert.Contains(t, out, "failed:       10 draws failed at "+srv.URL+" and are not in the results\n")
+	assert.Contains(t, out, "lines:        31 compared, 0 mismatched, 0 missing, 0 extra\n")
+}
+
+func Test_Run_Format(t *testing.T) {
+	for _, tc := range []struct {
+		output string
+		format string
+		want   string
+	}{
+		{"", "", formatCSV},
+		{"-", "jsonl", formatJSONL},
+		{"results.jsonl", "", formatJSONL},
+		{"results.JSONL.gz", "", formatJSONL},
+		{"results.txt.zst", "", formatValues},
+		{"results.csv", "CSV", formatCSV},
+		{"results.out", "values", formatValues},
+		{"results.gz", "", formatCSV},
+		{"results.jsonl", "csv", ""},
+		{"results.csv.gz", "values", ""},
+	} {
+		r := Run{Function: functionUniformFloat64, Count: 1, Output: tc.output, Format: tc.format}
+		err := r.normalize()
+		if tc.want == "" {
+			assert.ErrorContains(t, err, "contradicts the extension", tc.output)
+			continue
+		}
+		assert.Nil(t, err, tc.output)
+		assert.Equal(t, tc.want, r.Format, tc.output)
+	}
+}
+
+// readResults reads the results at path decompressed and checks them against their manifest
+func readResults(path string, m Manifest) ([]byte, error) {
+	var content bytes.Buffer
+	rr, err := openManifestResults(path, m, &content)
+	if err != nil {
+		return nil, err
+	}
+	defer rr.Close()
+	_, err = rr.WriteTo(new(bytes.Buffer))
+	if err != nil {
+		return nil, err
+	}
+	if int64(content.Len()) != m.ContentSize {
+		return nil, fmt.Errorf("read %v bytes of results, the manifest has %v", content.Len(), m.ContentSize)
+	}
+	return content.Bytes(), nil
+}
@@ -0,0 +1,69 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Scenario is a file describing several runs, in YAML or JSON:
+//
+//	runs:
+//	  - name: prize-table
+//	    function: DeterministicRandom
+//	    count: 100000
+//	    seedFile: seed.txt
+//	    probabilities: [0.01, 0.4, 0.59]
+type Scenario struct {
+	Runs []Run `json:"runs" yaml:"runs"`
+}
+
+// loadScenario reads a scenario, by the extension of its path, and checks all of its runs
+func loadScenario(path string) (Scenario, error) {
+	data, err := os.ReadFile(filepath.Clean(path))
+	if err != nil {
+		return Scenario{}, err
+	}
+
+	var s Scenario
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		dec := yaml.NewDecoder(bytes.NewReader(data))
+		dec.KnownFields(true)
+		err = dec.Decode(&s)
+	case ".json":
+		dec := json.NewDecoder(bytes.NewReader(data))
+		dec.DisallowUnknownFields()
+		err = dec.Decode(&s)
+	default:
+		return Scenario{}, errors.New("scenario must be a .yaml, .yml or .json file")
+	}
+	if err != nil {
+		return Scenario{}, fmt.Errorf("invalid scenario %s: %w", path, err)
+	} else if len(s.Runs) == 0 {
+		return Scenario{}, fmt.Errorf("scenario %s has no runs", path)
+	}
+
+	// Catch mistakes before the first run starts, not halfway through the scenario
+	for i := range s.Runs {
+		err = s.Runs[i].normalize()
+		if err != nil {
+			return Scenario{}, fmt.Errorf("%s: %w", runLabel(i, s.Runs[i]), err)
+		}
+	}
+	return s, nil
+}
+
+// runLabel names the i-th run of a scenario in messages
+func runLabel(i int, r Run) string {
+	if len(r.Name) == 0 {
+		return fmt.Sprintf("run %v", i+1)
+	}
+	return fmt.Sprintf("run %v (%s)", i+1, r.Name)
+}
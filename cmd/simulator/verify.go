@@ -0,0 +1,238 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"cmp"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	"github.com/fasttrack-solutions/random"
+)
+
+// mismatch is a line of results that differs from the line expected
+type mismatch struct {
+	// line counts from 1, the header included
+	line     int64
+	expected string
+	got      string
+}
+
+// comparer compares the lines written to it with the lines of the results
+type comparer struct {
+	results    *resultsReader
+	lines      int64
+	mismatches int64
+	missing    int64
+	first      *mismatch
+}
+
+func (c *comparer) Write(p []byte) (int, error) {
+	for rest := p; len(rest) > 0; {
+		i := bytes.IndexByte(rest, '\n')
+		expected := rest[:i+1]
+		rest = rest[i+1:]
+		c.lines++
+
+		got, err := c.results.ReadSlice('\n')
+		if len(got) == 0 && errors.Is(err, io.EOF) {
+			c.missing++
+			c.mismatch(expected, []byte("end of results"))
+			continue
+		} else if err != nil && !errors.Is(err, io.EOF) {
+			return 0, err
+		}
+		if !bytes.Equal(expected, got) {
+			c.mismatches++
+			c.mismatch(expected, got)
+		}
+	}
+	return len(p), nil
+}
+
+// mismatch keeps the first mismatch
+func (c *comparer) mismatch(expected []byte, got []byte) {
+	if c.first == nil {
+		c.first = &mismatch{
+			line:     c.lines,
+			expected: strings.TrimSuffix(string(expected), "\n"),
+			got:      strings.TrimSuffix(string(got), "\n"),
+		}
+	}
+}
+
+// runVerify re-derives the outcomes of a results file from the seed and its
+// manifest and compares them line by line. The outcomes of UniformInt64 and
+// UniformFloat64 cannot be re-derived, their lines are checked to be in range.
+func runVerify(args []string) error {
+	fs := flag.NewFlagSet("simulator verify", flag.ContinueOnError)
+	manifestPath := fs.String("manifest", "", "Manifest of the results, defaults to the results path followed by "+manifestSuffix)
+	seed := fs.String("seed", "", "Hex seed of DeterministicRandom, defaults to $SEED_HEX")
+	seedFile := fs.String("seed-file", "", "File holding the hex seed of DeterministicRandom")
+	workers := fs.Int("workers", runtime.GOMAXPROCS(0), "Number of goroutines re-deriving the outcomes")
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "usage: simulator verify [flags] <results>")
+		fs.PrintDefaults()
+	}
+	err := fs.Parse(args)
+	if err != nil {
+		return err
+	} else if fs.NArg() != 1 {
+		fs.Usage()
+		return errors.New("verify takes one results file")
+	} else if *workers < 1 {
+		return errors.New("-workers must be at least 1")
+	}
+
+	path := fs.Arg(0)
+	m, err := readManifest(cmp.Or(*manifestPath, path+manifestSuffix))
+	if err != nil {
+		return err
+	}
+	r := m.run()
+	r.Workers = *workers
+	if m.Failed > 0 && r.Function == functionDeterministicRandom {
+		// The sequences of the failed draws are unknown, the lines cannot be matched with the draws
+		return fmt.Errorf("%v of the %v draws failed at %s and are not in the results, they cannot be re-derived line by line", m.Failed, m.Count, m.Target)
+	}
+	// The outcomes of the other functions are checked in range, the failed draws are left out of them
+	r.Count = m.Draws
+
+	if r.Function == functionDeterministicRandom {
+		if m.AlgorithmVersion != random.AlgorithmVersion {
+			return fmt.Errorf("the results were drawn with algorithm version %s, only version %s can be re-derived", m.AlgorithmVersion, random.AlgorithmVersion)
+		}
+		r.Seed, err = resolveSeed(*seed, *seedFile)
+		if err != nil {
+			return err
+		}
+		fingerprint, errFingerprint := random.SeedFingerprint(r.Seed)
+		if errFingerprint != nil {
+			return errFingerprint
+		} else if fingerprint != m.SeedFingerprint {
+			return fmt.Errorf("the seed has fingerprint %s, the results were drawn with %s", fingerprint, m.SeedFingerprint)
+		}
+	}
+
+	content := sha256.New()
+	counted := &countingWriter{w: content}
+	results, err := openManifestResults(path, m, counted)
+	if err != nil {
+		return err
+	}
+	defer results.Close()
+
+	c := &comparer{results: results}
+	_, err = io.WriteString(c, r.header())
+	if err != nil {
+		return err
+	}
+	if r.Function == functionDeterministicRandom {
+		prog := newProgress(r.Count, 0)
+		err = generate(c, r, 0, func(float64) error { return nil }, func(draws int64) error {
+			prog.update(draws)
+			return nil
+		})
+		prog.finish()
+	} else {
+		err = checkRange(c, r)
+	}
+	if err != nil {
+		return err
+	}
+
+	// Lines after the last draw
+	extra := int64(0)
+	for {
+		line, errRead := results.ReadSlice('\n')
+		if len(line) > 0 {
+			extra++
+		}
+		if errors.Is(errRead, io.EOF) {
+			break
+		} else if errRead != nil && !errors.Is(errRead, bufio.ErrBufferFull) {
+			return errRead
+		}
+	}
+	sum := hex.EncodeToString(content.Sum(nil))
+
+	fmt.Printf("results:      %s, %v %s\n", filepath.Clean(path), r.Count, r.Function)
+	if m.Failed > 0 {
+		fmt.Printf("failed:       %v draws failed at %s and are not in the results\n", m.Failed, m.Target)
+	}
+	if len(m.SeedFingerprint) > 0 {
+		fmt.Printf("seed:         %s, algorithm version %s\n", m.SeedFingerprint, m.AlgorithmVersion)
+	}
+	fmt.Printf("lines:        %v compared, %v mismatched, %v missing, %v extra\n", c.lines, c.mismatches, c.missing, extra)
+	hashOK := sum == m.ContentSHA256 && counted.n == m.ContentSize
+	if hashOK {
+		fmt.Printf("content hash: %s matches the manifest\n", sum)
+	} else {
+		fmt.Printf("content hash: %s of %v bytes, the manifest has %s of %v bytes\n", sum, counted.n, m.ContentSHA256, m.ContentSize)
+	}
+	if c.first != nil {
+		fmt.Printf("first mismatch at line %v", c.first.line)
+		if draw := c.first.line - int64(strings.Count(r.header(), "\n")) - 1; draw >= 0 && r.Function == functionDeterministicRandom {
+			fmt.Printf(" (sequence %v)", r.Start+draw)
+		}
+		fmt.Printf(": expected %q, got %q\n", c.first.expected, c.first.got)
+	}
+
+	if c.first != nil || extra > 0 || !hashOK || m.Failed > 0 {
+		return errors.New("the results do not match")
+	}
+	fmt.Println("the results match")
+	return nil
+}
+
+// checkRange compares the header of the results of UniformInt64 or UniformFloat64
+// and checks that every outcome is in the range of the function
+func checkRange(c *comparer, r Run) error {
+	for i := int64(0); i < r.Count; i++ {
+		c.lines++
+		line, err := c.results.ReadSlice('\n')
+		if len(line) == 0 && errors.Is(err, io.EOF) {
+			c.missing += r.Count - i
+			c.mismatch([]byte(fmt.Sprintf("draw %v", i)), []byte("end of results"))
+			return nil
+		} else if err != nil && !errors.Is(err, io.EOF) {
+			return err
+		}
+
+		v, err := parseOutcome(string(line))
+		valid := err == nil && bytes.Equal(r.appendLine(nil, i, v), line)
+		if valid && r.Function == functionUniformInt64 {
+			valid = v >= float64(r.Min) && v <= float64(r.Max)
+		} else if valid {
+			valid = v >= 0 && v <= 1
+		}
+		if !valid {
+			c.mismatches++
+			c.mismatch([]byte(fmt.Sprintf("%s outcome", r.Function)), line)
+		}
+	}
+	return nil
+}
+
+// resolveSeed returns the seed of the flags, the file or $SEED_HEX
+func resolveSeed(seed string, seedFile string) (string, error) {
+	if len(seedFile) > 0 {
+		if len(seed) > 0 {
+			return "", errors.New("-seed and -seed-file are mutually exclusive")
+		}
+		b, err := os.ReadFile(filepath.Clean(seedFile))
+		if err != nil {
+			return "", err
+		}
+		return strings.TrimSpace(string(b)), nil
+	}
+	return cmp.Or(seed, os.Getenv("SEED_HEX")), nil
+}
@@ -0,0 +1,73 @@
+package main
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Verify(t *testing.T) {
+	dir := t.TempDir()
+	r := testRun(filepath.Join(dir, "results.csv"))
+	_, _, err := execute(r)
+	assert.Nil(t, err)
+	content, err := os.ReadFile(r.Output)
+	assert.Nil(t, err)
+
+	out, err := captureStdout(t, func() error { return runVerify([]string{"-seed", testSeed, r.Output}) })
+	assert.Nil(t, err)
+	assert.Contains(t, out, "lines:        49276 compared, 0 mismatched, 0 missing, 0 extra\n")
+	assert.Contains(t, out, "the results match\n")
+
+	// Another seed is refused before comparing
+	_, err = captureStdout(t, func() error {
+		return runVerify([]string{"-seed", strings.Repeat("ab", 32), r.Output})
+	})
+	assert.ErrorContains(t, err, "fingerprint")
+
+	// A tampered line, of the same length, is reported with its sequence
+	lines := strings.SplitAfter(string(content), "\n")
+	index := lines[11][strings.IndexByte(lines[11], ',')+1 : len(lines[11])-1]
+	tampered := "1010,1\n"
+	if index == "1" {
+		tampered = "1010,2\n"
+	}
+	lines[11] = tampered
+	assert.Nil(t, os.WriteFile(r.Output, []byte(strings.Join(lines, "")), 0600))
+	out, err = captureStdout(t, func() error { return runVerify([]string{"-seed", testSeed, r.Output}) })
+	assert.ErrorContains(t, err, "do not match")
+	assert.Contains(t, out, "1 mismatched, 0 missing, 0 extra\n")
+	assert.Contains(t, out, "content hash: ")
+	assert.Contains(t, out, "the manifest has ")
+	assert.Contains(t, out, `first mismatch at line 12 (sequence 1010): expected "1010,`+index+`", got "`+tampered[:len(tampered)-1]+`"`)
+
+	// A truncated file misses its last draws, the last element of lines is empty
+	lines = strings.SplitAfter(string(content), "\n")
+	assert.Nil(t, os.WriteFile(r.Output, []byte(strings.Join(lines[:len(lines)-4], "")), 0600))
+	out, err = captureStdout(t, func() error { return runVerify([]string{"-seed", testSeed, r.Output}) })
+	assert.ErrorContains(t, err, "do not match")
+	assert.Contains(t, out, "0 mismatched, 3 missing, 0 extra\n")
+	assert.Contains(t, out, `got "end of results"`)
+}
+
+// captureStdout returns what fn prints on stdout and its error
+func captureStdout(t *testing.T, fn func() error) (string, error) {
+	f, err := os.CreateTemp(t.TempDir(), "stdout")
+	assert.Nil(t, err)
+	defer f.Close()
+
+	stdout := os.Stdout
+	os.Stdout = f
+	errFn := fn()
+	os.Stdout = stdout
+
+	_, err = f.Seek(0, io.SeekStart)
+	assert.Nil(t, err)
+	out, err := io.ReadAll(f)
+	assert.Nil(t, err)
+	return string(out), errFn
+}
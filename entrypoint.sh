@@ -5,7 +5,10 @@ if [ "$1" = "grpc" ]; then
   exec ./grpc
 elif [ "$1" = "http" ]; then
   exec ./http
+elif [ "$1" = "randomctl" ]; then
+  shift
+  exec ./randomctl "$@"
 else
-  echo "Usage: docker run <image> [grpc|http]"
+  echo "Usage: docker run <image> [grpc|http|randomctl]"
   exit 1
 fi
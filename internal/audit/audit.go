@@ -0,0 +1,383 @@
+package audit
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+)
+
+const (
+	// FsyncAlways syncs the segment after every entry, before the draw is returned.
+	FsyncAlways = "always"
+	// FsyncInterval syncs the segment in the background every interval.
+	FsyncInterval = "interval"
+	// FsyncNever leaves syncing to the operating system.
+	FsyncNever = "never"
+)
+
+// GenesisHash is the previous hash of the first entry of a log
+var GenesisHash = hex.EncodeToString(make([]byte, sha256.Size))
+
+// Entry is a single draw in the audit log.
+// Input and Output hold the request and response messages in protobuf JSON.
+type Entry struct {
+	Index            uint64          `json:"index"`
+	Time             time.Time       `json:"time"`
+	Client           string          `json:"client,omitempty"`
+	RPC              string          `json:"rpc"`
+	Input            json.RawMessage `json:"input"`
+	Output           json.RawMessage `json:"output"`
+	AlgorithmVersion string          `json:"algorithmVersion"`
+	SeedFingerprint  string          `json:"seedFingerprint,omitempty"`
+	PrevHash         string          `json:"prevHash"`
+	Hash             string          `json:"hash,omitempty"`
+}
+
+// NewEntry creates an entry for a draw of rpc with its request and response message
+func NewEntry(client string, rpc string, req proto.Message, resp proto.Message) (Entry, error) {
+	input, err := marshalMessage(req)
+	if err != nil {
+		return Entry{}, fmt.Errorf("failed to encode %s request: %w", rpc, err)
+	}
+
+	output, err := marshalMessage(resp)
+	if err != nil {
+		return Entry{}, fmt.Errorf("failed to encode %s response: %w", rpc, err)
+	}
+
+	return Entry{
+		Time:   time.Now().UTC(),
+		Client: client,
+		RPC:    rpc,
+		Input:  input,
+		Output: output,
+	}, nil
+}
+
+// marshalMessage encodes m as compact protobuf JSON, protojson adds random whitespace on purpose
+func marshalMessage(m proto.Message) (json.RawMessage, error) {
+	b, err := protojson.MarshalOptions{EmitUnpopulated: true}.Marshal(m)
+	if err != nil {
+		return nil, err
+	}
+
+	var compact bytes.Buffer
+	err = json.Compact(&compact, b)
+	if err != nil {
+		return nil, err
+	}
+	return compact.Bytes(), nil
+}
+
+// ComputeHash returns the SHA-256 over the JSON encoding of the entry without its hash
+func (e Entry) ComputeHash() (string, error) {
+	e.Hash = ""
+	b, err := json.Marshal(e)
+	if err != nil {
+		return "", err
+	}
+
+	sum := sha256.Sum256(b)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// Options configure a Log
+type Options struct {
+	// Dir holds the segment files.
+	Dir string
+	// MaxSegmentSize is the size in bytes after which a new segment is started.
+	MaxSegmentSize int64
+	// Fsync is one of FsyncAlways, FsyncInterval or FsyncNever.
+	Fsync string
+	// FsyncInterval is the period of background syncs with FsyncInterval.
+	FsyncInterval time.Duration
+}
+
+// Log is an append-only, hash-chained log of draws split in segment files.
+// Every entry holds the hash of the entry before it, so removing or changing
+// an entry breaks the chain from that point on.
+type Log struct {
+	opts Options
+
+	mu       sync.Mutex
+	segment  *os.File
+	number   int
+	size     int64
+	next     uint64
+	prevHash string
+	dirty    bool
+
+	stop      chan struct{}
+	done      chan struct{}
+	closeOnce sync.Once
+}
+
+// Open opens the log in opts.Dir, continuing the chain of the last segment
+func Open(opts Options) (*Log, error) {
+	if opts.Fsync != FsyncAlways && opts.Fsync != FsyncInterval && opts.Fsync != FsyncNever {
+		return nil, fmt.Errorf("invalid audit fsync policy %q; valid policies are %q, %q and %q", opts.Fsync, FsyncAlways, FsyncInterval, FsyncNever)
+	} else if opts.Fsync == FsyncInterval && opts.FsyncInterval <= 0 {
+		return nil, errors.New("audit fsync interval must be larger than 0")
+	} else if opts.MaxSegmentSize <= 0 {
+		return nil, errors.New("audit segment size must be larger than 0")
+	}
+
+	err := os.MkdirAll(opts.Dir, 0750)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create audit directory: %w", err)
+	}
+
+	l := &Log{
+		opts:     opts,
+		prevHash: GenesisHash,
+	}
+
+	segments, err := Segments(opts.Dir)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(segments) == 0 {
+		err = l.openSegment(1)
+	} else {
+		err = l.recover(segments[len(segments)-1])
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if opts.Fsync == FsyncInterval {
+		l.stop = make(chan struct{})
+		l.done = make(chan struct{})
+		go l.syncLoop()
+	}
+
+	return l, nil
+}
+
+// recover continues the last segment after its last complete entry.
+// A partially written entry, i.e. after a crash, is cut off.
+func (l *Log) recover(segment Segment) error {
+	f, err := os.OpenFile(filepath.Clean(segment.Path), os.O_RDWR, 0600)
+	if err != nil {
+		return fmt.Errorf("failed to open audit segment: %w", err)
+	}
+
+	var last *Entry
+	good, err := scanSegment(f, func(e Entry) error {
+		last = &e
+		return nil
+	})
+	if err != nil {
+		_ = f.Close()
+		return err
+	}
+
+	info, err := f.Stat()
+	if err != nil {
+		_ = f.Close()
+		return err
+	}
+	if info.Size() > good {
+		slog.Warn("truncating partially written audit entry", "segment", segment.Path, "bytes", info.Size()-good)
+		err = f.Truncate(good)
+		if err != nil {
+			_ = f.Close()
+			return fmt.Errorf("failed to truncate audit segment: %w", err)
+		}
+	}
+
+	_, err = f.Seek(good, 0)
+	if err != nil {
+		_ = f.Close()
+		return err
+	}
+
+	l.segment = f
+	l.number = segment.Number
+	l.size = good
+	if last != nil {
+		l.next = last.Index + 1
+		l.prevHash = last.Hash
+	} else {
+		// An empty segment continues the chain of the segment before it
+		return l.chainFromPrevious(segment.Number)
+	}
+	return nil
+}
+
+func (l *Log) chainFromPrevious(number int) error {
+	segments, err := Segments(l.opts.Dir)
+	if err != nil {
+		return err
+	}
+
+	for i := len(segments) - 1; i >= 0; i-- {
+		if segments[i].Number >= number {
+			continue
+		}
+
+		var last *Entry
+		err = ReadSegment(segments[i].Path, func(e Entry) error {
+			last = &e
+			return nil
+		})
+		if err != nil {
+			return err
+		} else if last != nil {
+			l.next = last.Index + 1
+			l.prevHash = last.Hash
+			return nil
+		}
+	}
+	return nil
+}
+
+func (l *Log) openSegment(number int) error {
+	path := filepath.Join(l.opts.Dir, segmentName(number))
+	f, err := os.OpenFile(filepath.Clean(path), os.O_RDWR|os.O_CREATE|os.O_EXCL, 0600)
+	if err != nil {
+		return fmt.Errorf("failed to create audit segment: %w", err)
+	}
+
+	l.segment = f
+	l.number = number
+	l.size = 0
+	return nil
+}
+
+// Append chains the entry to the log and writes it. The entry is returned
+// with its index and hashes filled in.
+func (l *Log) Append(e Entry) (Entry, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.segment == nil {
+		return Entry{}, errors.New("audit log is closed")
+	}
+
+	e.Index = l.next
+	e.PrevHash = l.prevHash
+	hash, err := e.ComputeHash()
+	if err != nil {
+		return Entry{}, err
+	}
+	e.Hash = hash
+
+	line, err := json.Marshal(e)
+	if err != nil {
+		return Entry{}, err
+	}
+	line = append(line, '\n')
+
+	if l.size > 0 && l.size+int64(len(line)) > l.opts.MaxSegmentSize {
+		err = l.rotate()
+		if err != nil {
+			return Entry{}, err
+		}
+	}
+
+	_, err = l.segment.Write(line)
+	if err != nil {
+		// Cut off what was written so the next entry does not follow a partial line
+		if errTruncate := l.segment.Truncate(l.size); errTruncate == nil {
+			_, _ = l.segment.Seek(l.size, 0)
+		}
+		return Entry{}, fmt.Errorf("failed to write audit entry: %w", err)
+	}
+	l.size += int64(len(line))
+
+	if l.opts.Fsync == FsyncAlways {
+		err = l.segment.Sync()
+		if err != nil {
+			return Entry{}, fmt.Errorf("failed to sync audit entry: %w", err)
+		}
+	} else {
+		l.dirty = true
+	}
+
+	l.next++
+	l.prevHash = e.Hash
+	return e, nil
+}
+
+// rotate closes the current segment and starts the next one
+func (l *Log) rotate() error {
+	err := l.segment.Sync()
+	if err != nil {
+		return fmt.Errorf("failed to sync audit segment: %w", err)
+	}
+
+	err = l.segment.Close()
+	if err != nil {
+		return fmt.Errorf("failed to close audit segment: %w", err)
+	}
+
+	l.dirty = false
+	return l.openSegment(l.number + 1)
+}
+
+// Sync flushes the current segment to disk
+func (l *Log) Sync() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.segment == nil || !l.dirty {
+		return nil
+	}
+
+	l.dirty = false
+	return l.segment.Sync()
+}
+
+func (l *Log) syncLoop() {
+	defer close(l.done)
+
+	ticker := time.NewTicker(l.opts.FsyncInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-l.stop:
+			return
+		case <-ticker.C:
+			err := l.Sync()
+			if err != nil {
+				slog.Error("failed to sync audit log", "error", err.Error())
+			}
+		}
+	}
+}
+
+// Close syncs and closes the log
+func (l *Log) Close() error {
+	l.closeOnce.Do(func() {
+		if l.stop != nil {
+			close(l.stop)
+			<-l.done
+		}
+	})
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.segment == nil {
+		return nil
+	}
+
+	errSync := l.segment.Sync()
+	errClose := l.segment.Close()
+	l.segment = nil
+	return errors.Join(errSync, errClose)
+}
@@ -0,0 +1,117 @@
+package audit
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	"github.com/fasttrack-solutions/random/pkg/pb"
+	"github.com/stretchr/testify/assert"
+)
+
+func appendDraws(t *testing.T, l *Log, count int) []Entry {
+	var entries []Entry
+	for i := 0; i < count; i++ {
+		e, err := NewEntry("client-1", "GetDeterministicRandom",
+			&pb.GetDeterministicRandomRequest{Sequence: int64(i), Probabilities: []float64{0.5, 0.5}, Namespace: "campaign"},
+			&pb.GetDeterministicRandomResponse{Number: int64(i % 2)},
+		)
+		assert.Nil(t, err)
+		e.AlgorithmVersion = "1"
+
+		e, err = l.Append(e)
+		assert.Nil(t, err)
+		entries = append(entries, e)
+	}
+	return entries
+}
+
+func readAll(t *testing.T, dir string) []Entry {
+	segments, err := Segments(dir)
+	assert.Nil(t, err)
+
+	var entries []Entry
+	for _, segment := range segments {
+		err = ReadSegment(segment.Path, func(e Entry) error {
+			entries = append(entries, e)
+			return nil
+		})
+		assert.Nil(t, err)
+	}
+	return entries
+}
+
+func Test_Log(t *testing.T) {
+	dir := t.TempDir()
+	opts := Options{Dir: dir, MaxSegmentSize: 1024, Fsync: FsyncInterval, FsyncInterval: time.Millisecond}
+
+	l, err := Open(opts)
+	assert.Nil(t, err)
+	written := appendDraws(t, l, 10)
+	assert.Nil(t, l.Close())
+
+	// Entries are chained
+	assert.Equal(t, GenesisHash, written[0].PrevHash)
+	for i, e := range written {
+		assert.Equal(t, uint64(i), e.Index)
+		hash, errHash := e.ComputeHash()
+		assert.Nil(t, errHash)
+		assert.Equal(t, hash, e.Hash)
+		if i > 0 {
+			assert.Equal(t, written[i-1].Hash, e.PrevHash)
+		}
+	}
+
+	// Rotation splits the log in segments
+	segments, err := Segments(dir)
+	assert.Nil(t, err)
+	assert.Greater(t, len(segments), 1)
+
+	// Reopening continues the chain
+	l, err = Open(opts)
+	assert.Nil(t, err)
+	more := appendDraws(t, l, 1)
+	assert.Nil(t, l.Close())
+	assert.Equal(t, uint64(10), more[0].Index)
+	assert.Equal(t, written[9].Hash, more[0].PrevHash)
+
+	assert.Equal(t, append(written, more...), readAll(t, dir))
+}
+
+func Test_Log_PartialEntry(t *testing.T) {
+	dir := t.TempDir()
+	opts := Options{Dir: dir, MaxSegmentSize: 1 << 20, Fsync: FsyncAlways}
+
+	l, err := Open(opts)
+	assert.Nil(t, err)
+	written := appendDraws(t, l, 3)
+	assert.Nil(t, l.Close())
+
+	// Simulate a crash halfway through writing an entry
+	segments, err := Segments(dir)
+	assert.Nil(t, err)
+	f, err := os.OpenFile(segments[0].Path, os.O_APPEND|os.O_WRONLY, 0600)
+	assert.Nil(t, err)
+	_, err = f.WriteString(`{"index":3,"time":"20`)
+	assert.Nil(t, err)
+	assert.Nil(t, f.Close())
+
+	l, err = Open(opts)
+	assert.Nil(t, err)
+	more := appendDraws(t, l, 1)
+	assert.Nil(t, l.Close())
+
+	assert.Equal(t, written[2].Hash, more[0].PrevHash)
+	assert.Equal(t, append(written, more...), readAll(t, dir))
+}
+
+func Test_Open_InvalidOptions(t *testing.T) {
+	_, err := Open(Options{Dir: t.TempDir(), MaxSegmentSize: 1024, Fsync: "sometimes"})
+	assert.NotNil(t, err)
+
+	_, err = Open(Options{Dir: t.TempDir(), MaxSegmentSize: 1024, Fsync: FsyncInterval})
+	assert.NotNil(t, err)
+
+	_, err = Open(Options{Dir: t.TempDir(), Fsync: FsyncNever})
+	assert.NotNil(t, err)
+}
@@ -0,0 +1,203 @@
+package audit
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/fasttrack-solutions/random/pkg/pb"
+)
+
+const (
+	// FormatCSV exports a header row and a row per entry
+	FormatCSV = "csv"
+	// FormatJSON exports a JSON array of entries
+	FormatJSON = "json"
+)
+
+// Filter selects the entries to export, zero values do not filter
+type Filter struct {
+	Namespace string
+	From      time.Time
+	To        time.Time
+	// FromSequence and ToSequence select deterministic draws by their inclusive sequence range
+	FromSequence *int64
+	ToSequence   *int64
+}
+
+// Draw is the flattened view of an entry used for exports
+type Draw struct {
+	Entry
+	Namespace     string    `json:"namespace,omitempty"`
+	Sequence      *int64    `json:"sequence,omitempty"`
+	Probabilities []float64 `json:"probabilities,omitempty"`
+	Min           *int32    `json:"min,omitempty"`
+	Max           *int32    `json:"max,omitempty"`
+	Number        string    `json:"number"`
+	Replayed      bool      `json:"replayed"`
+}
+
+// NewDraw flattens the request and response of the entry
+func NewDraw(e Entry) (Draw, error) {
+	req, resp, err := e.Messages()
+	if err != nil {
+		return Draw{}, err
+	}
+
+	d := Draw{Entry: e}
+	switch r := req.(type) {
+	case *pb.GetRandomFloat64Request:
+		out := resp.(*pb.GetRandomFloat64Response)
+		d.Number, d.Replayed = strconv.FormatFloat(out.Number, 'g', -1, 64), out.Replayed
+	case *pb.GetRandomInt64Request:
+		out := resp.(*pb.GetRandomInt64Response)
+		d.Min, d.Max = &r.Min, &r.Max
+		d.Number, d.Replayed = strconv.FormatInt(out.Number, 10), out.Replayed
+	case *pb.GetDeterministicRandomRequest:
+		out := resp.(*pb.GetDeterministicRandomResponse)
+		d.Namespace, d.Sequence, d.Probabilities = r.Namespace, &r.Sequence, r.Probabilities
+		d.Number, d.Replayed = strconv.FormatInt(out.Number, 10), out.Replayed
+	case *pb.DrawDeterministicRandomRequest:
+		out := resp.(*pb.DrawDeterministicRandomResponse)
+		d.Namespace, d.Sequence, d.Probabilities = r.Namespace, &out.Sequence, r.Probabilities
+		d.Number = strconv.FormatInt(out.Number, 10)
+	}
+	return d, nil
+}
+
+// Match reports whether the draw is selected by the filter
+func (f Filter) Match(d Draw) bool {
+	if len(f.Namespace) > 0 && d.Namespace != f.Namespace {
+		return false
+	} else if !f.From.IsZero() && d.Time.Before(f.From) {
+		return false
+	} else if !f.To.IsZero() && !d.Time.Before(f.To) {
+		return false
+	}
+
+	if f.FromSequence != nil || f.ToSequence != nil {
+		if d.Sequence == nil {
+			return false
+		} else if f.FromSequence != nil && *d.Sequence < *f.FromSequence {
+			return false
+		} else if f.ToSequence != nil && *d.Sequence > *f.ToSequence {
+			return false
+		}
+	}
+	return true
+}
+
+// Export writes the entries of the log in dir that match the filter to w.
+// It returns the number of exported entries.
+func Export(dir string, filter Filter, format string, w io.Writer) (int, error) {
+	if format != FormatCSV && format != FormatJSON {
+		return 0, fmt.Errorf("invalid export format %q; valid formats are %q and %q", format, FormatCSV, FormatJSON)
+	}
+
+	segments, err := Segments(dir)
+	if err != nil {
+		return 0, err
+	}
+
+	var writeDraw func(d Draw) error
+	var finish func() error
+	count := 0
+
+	if format == FormatCSV {
+		cw := csv.NewWriter(w)
+		err = cw.Write([]string{"index", "time", "client", "rpc", "namespace", "sequence", "probabilities", "min", "max", "number", "replayed", "algorithmVersion", "seedFingerprint", "prevHash", "hash"})
+		if err != nil {
+			return 0, err
+		}
+
+		writeDraw = func(d Draw) error {
+			return cw.Write([]string{
+				strconv.FormatUint(d.Index, 10),
+				d.Time.Format(time.RFC3339Nano),
+				d.Client,
+				d.RPC,
+				d.Namespace,
+				formatOptional(d.Sequence),
+				formatProbabilities(d.Probabilities),
+				formatOptional(d.Min),
+				formatOptional(d.Max),
+				d.Number,
+				strconv.FormatBool(d.Replayed),
+				d.AlgorithmVersion,
+				d.SeedFingerprint,
+				d.PrevHash,
+				d.Hash,
+			})
+		}
+		finish = func() error {
+			cw.Flush()
+			return cw.Error()
+		}
+	} else {
+		_, err = io.WriteString(w, "[")
+		if err != nil {
+			return 0, err
+		}
+
+		writeDraw = func(d Draw) error {
+			if count > 0 {
+				_, errWrite := io.WriteString(w, ",\n")
+				if errWrite != nil {
+					return errWrite
+				}
+			}
+			b, errMarshal := json.Marshal(d)
+			if errMarshal != nil {
+				return errMarshal
+			}
+			_, errWrite := w.Write(b)
+			return errWrite
+		}
+		finish = func() error {
+			_, errWrite := io.WriteString(w, "]\n")
+			return errWrite
+		}
+	}
+
+	for _, segment := range segments {
+		err = ReadSegment(segment.Path, func(e Entry) error {
+			d, errDraw := NewDraw(e)
+			if errDraw != nil {
+				return fmt.Errorf("entry %v: %w", e.Index, errDraw)
+			} else if !filter.Match(d) {
+				return nil
+			}
+
+			errWrite := writeDraw(d)
+			if errWrite != nil {
+				return errWrite
+			}
+			count++
+			return nil
+		})
+		if err != nil {
+			return count, err
+		}
+	}
+
+	return count, finish()
+}
+
+func formatOptional[T int32 | int64](v *T) string {
+	if v == nil {
+		return ""
+	}
+	return strconv.FormatInt(int64(*v), 10)
+}
+
+func formatProbabilities(probabilities []float64) string {
+	values := make([]string, len(probabilities))
+	for i, p := range probabilities {
+		values[i] = strconv.FormatFloat(p, 'g', -1, 64)
+	}
+	return strings.Join(values, ";")
+}
@@ -0,0 +1,51 @@
+package audit
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Export(t *testing.T) {
+	dir := writeDeterministicLog(t, 5)
+
+	// CSV of a sequence range
+	from, to := int64(1), int64(3)
+	var buf bytes.Buffer
+	count, err := Export(dir, Filter{Namespace: "campaign", FromSequence: &from, ToSequence: &to}, FormatCSV, &buf)
+	assert.Nil(t, err)
+	assert.Equal(t, 3, count)
+
+	rows, err := csv.NewReader(&buf).ReadAll()
+	assert.Nil(t, err)
+	assert.Len(t, rows, 4)
+	assert.Equal(t, "sequence", rows[0][5])
+	assert.Equal(t, []string{"1", "2", "3"}, []string{rows[1][5], rows[2][5], rows[3][5]})
+	assert.Equal(t, "0.2;0.2;0.2;0.2;0.2", rows[1][6])
+
+	// JSON of everything
+	buf.Reset()
+	count, err = Export(dir, Filter{}, FormatJSON, &buf)
+	assert.Nil(t, err)
+	assert.Equal(t, 6, count)
+
+	var draws []Draw
+	assert.Nil(t, json.Unmarshal(buf.Bytes(), &draws))
+	assert.Len(t, draws, 6)
+	assert.Equal(t, "GetRandomFloat64", draws[5].RPC)
+	assert.Equal(t, "0.5", draws[5].Number)
+
+	// Time range
+	buf.Reset()
+	count, err = Export(dir, Filter{From: time.Now().Add(time.Hour)}, FormatJSON, &buf)
+	assert.Nil(t, err)
+	assert.Equal(t, 0, count)
+	assert.Equal(t, "[]\n", buf.String())
+
+	_, err = Export(dir, Filter{}, "xml", &buf)
+	assert.NotNil(t, err)
+}
@@ -0,0 +1,96 @@
+package audit
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strconv"
+)
+
+var segmentPattern = regexp.MustCompile(`^audit-(\d{8})\.jsonl$`)
+
+// Segment is a file of the audit log
+type Segment struct {
+	Number int
+	Path   string
+}
+
+func segmentName(number int) string {
+	return fmt.Sprintf("audit-%08d.jsonl", number)
+}
+
+// Segments lists the segment files in dir in log order
+func Segments(dir string) ([]Segment, error) {
+	files, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list audit segments: %w", err)
+	}
+
+	var segments []Segment
+	for _, f := range files {
+		m := segmentPattern.FindStringSubmatch(f.Name())
+		if f.IsDir() || m == nil {
+			continue
+		}
+
+		number, errAtoi := strconv.Atoi(m[1])
+		if errAtoi != nil {
+			return nil, errAtoi
+		}
+		segments = append(segments, Segment{Number: number, Path: filepath.Join(dir, f.Name())})
+	}
+
+	slices.SortFunc(segments, func(a, b Segment) int {
+		return a.Number - b.Number
+	})
+	return segments, nil
+}
+
+// ReadSegment calls fn for every complete entry of the segment at path
+func ReadSegment(path string, fn func(e Entry) error) error {
+	f, err := os.Open(filepath.Clean(path))
+	if err != nil {
+		return fmt.Errorf("failed to open audit segment: %w", err)
+	}
+	defer f.Close()
+
+	_, err = scanSegment(f, fn)
+	return err
+}
+
+// scanSegment calls fn for every entry of r and returns the offset after the last complete entry.
+// A last line without newline or that cannot be decoded is a partially written entry and is skipped.
+func scanSegment(r io.Reader, fn func(e Entry) error) (int64, error) {
+	br := bufio.NewReader(r)
+	var offset int64
+
+	for lineNr := 1; ; lineNr++ {
+		line, err := br.ReadBytes('\n')
+		if errors.Is(err, io.EOF) {
+			return offset, nil
+		} else if err != nil {
+			return offset, err
+		}
+
+		var e Entry
+		errUnmarshal := json.Unmarshal(line, &e)
+		if errUnmarshal != nil {
+			if _, errPeek := br.Peek(1); errors.Is(errPeek, io.EOF) {
+				return offset, nil
+			}
+			return offset, fmt.Errorf("invalid audit entry on line %v: %w", lineNr, errUnmarshal)
+		}
+
+		err = fn(e)
+		if err != nil {
+			return offset, err
+		}
+		offset += int64(len(line))
+	}
+}
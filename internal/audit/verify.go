@@ -0,0 +1,309 @@
+package audit
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/fasttrack-solutions/random"
+	"github.com/fasttrack-solutions/random/pkg/hashchain"
+	"github.com/fasttrack-solutions/random/pkg/pb"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+)
+
+const (
+	// ProblemGap is an index or segment that is missing
+	ProblemGap = "gap"
+	// ProblemTampered is an entry whose hash or chain link does not match
+	ProblemTampered = "tampered"
+	// ProblemMismatch is a deterministic outcome or crash round that differs from the recomputed one
+	ProblemMismatch = "mismatch"
+	// ProblemTruncated is an expected head that is not in the log, i.e. entries removed from its end
+	ProblemTruncated = "truncated"
+	// ProblemUnverifiable is a deterministic outcome that cannot be recomputed, i.e. drawn with another seed
+	ProblemUnverifiable = "unverifiable"
+)
+
+// Problem is an issue found while verifying the log
+type Problem struct {
+	Segment string
+	Index   uint64
+	Kind    string
+	Detail  string
+}
+
+func (p Problem) String() string {
+	return fmt.Sprintf("%s: entry %v in %s: %s", p.Kind, p.Index, p.Segment, p.Detail)
+}
+
+// Report is the result of verifying a log
+type Report struct {
+	Segments     int
+	Entries      uint64
+	Recomputed   uint64
+	CrashRounds  uint64
+	LastHash     string
+	Problems     []Problem
+	MaxProblems  int
+	MoreProblems uint64
+}
+
+// OK reports whether no problems were found
+func (r *Report) OK() bool {
+	return len(r.Problems) == 0
+}
+
+func (r *Report) add(p Problem) {
+	if r.MaxProblems > 0 && len(r.Problems) >= r.MaxProblems {
+		r.MoreProblems++
+		return
+	}
+	r.Problems = append(r.Problems, p)
+}
+
+// Messages decodes the request and response of the entry into the messages of its RPC
+func (e Entry) Messages() (proto.Message, proto.Message, error) {
+	method := pb.File_pkg_pb_service_proto.Services().ByName("Random").Methods().ByName(protoreflect.Name(e.RPC))
+	if method == nil {
+		return nil, nil, fmt.Errorf("unknown rpc %q", e.RPC)
+	}
+
+	req, err := unmarshalMessage(method.Input(), e.Input)
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid %s request: %w", e.RPC, err)
+	}
+
+	resp, err := unmarshalMessage(method.Output(), e.Output)
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid %s response: %w", e.RPC, err)
+	}
+
+	return req, resp, nil
+}
+
+func unmarshalMessage(desc protoreflect.MessageDescriptor, b []byte) (proto.Message, error) {
+	t, err := protoregistry.GlobalTypes.FindMessageByName(desc.FullName())
+	if err != nil {
+		return nil, err
+	}
+
+	m := t.New().Interface()
+	err = protojson.Unmarshal(b, m)
+	if err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// VerifyOptions are the checks of Verify besides the chain of entries
+type VerifyOptions struct {
+	// Seeds recompute the deterministic outcomes drawn with them, outcomes of other
+	// seeds are reported as unverifiable unless Seeds is empty
+	Seeds []string
+	// CrashHouseEdge recomputes the crash points of crash rounds
+	CrashHouseEdge float64
+	// MaxProblems caps the number of problems kept in the report, 0 keeps all
+	MaxProblems int
+	// ExpectEntries and ExpectLastHash are a head of the log recorded earlier, i.e. the
+	// entries and last hash of a previous verification. The log must still hold
+	// it, removing entries or segments from its end cannot be told apart from a
+	// log that was never longer otherwise. Either is ignored when not set.
+	ExpectEntries  uint64
+	ExpectLastHash string
+}
+
+// Verify walks every segment in dir and checks that indexes are consecutive,
+// every entry hash matches its content and links to the previous entry.
+// Deterministic outcomes are recomputed with the seeds of opts, and the seed of
+// every crash round is checked against the terminating hash of its chain.
+// The log must reach the expected head of opts, if any.
+func Verify(dir string, opts VerifyOptions) (*Report, error) {
+	segments, err := Segments(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	byFingerprint := map[string]string{}
+	for _, seedHex := range opts.Seeds {
+		fingerprint, errFingerprint := random.SeedFingerprint(seedHex)
+		if errFingerprint != nil {
+			return nil, errFingerprint
+		}
+		byFingerprint[fingerprint] = seedHex
+	}
+	rounds := crashRounds{houseEdge: opts.CrashHouseEdge, latest: map[string]crashLink{}}
+
+	report := &Report{
+		Segments:    len(segments),
+		LastHash:    GenesisHash,
+		MaxProblems: opts.MaxProblems,
+	}
+	next := uint64(0)
+	headReached := opts.ExpectEntries == 0 && len(opts.ExpectLastHash) == 0
+
+	for i, segment := range segments {
+		if i > 0 && segment.Number != segments[i-1].Number+1 {
+			report.add(Problem{Segment: segment.Path, Index: next, Kind: ProblemGap, Detail: fmt.Sprintf("segment %v follows segment %v", segment.Number, segments[i-1].Number)})
+		}
+
+		err = ReadSegment(segment.Path, func(e Entry) error {
+			report.Entries++
+
+			if e.Index != next {
+				report.add(Problem{Segment: segment.Path, Index: e.Index, Kind: ProblemGap, Detail: fmt.Sprintf("expected index %v", next)})
+			}
+			if e.PrevHash != report.LastHash {
+				report.add(Problem{Segment: segment.Path, Index: e.Index, Kind: ProblemTampered, Detail: "previous hash does not match the entry before it"})
+			}
+			hash, errHash := e.ComputeHash()
+			if errHash != nil {
+				return errHash
+			} else if hash != e.Hash {
+				report.add(Problem{Segment: segment.Path, Index: e.Index, Kind: ProblemTampered, Detail: "hash does not match the content"})
+			}
+
+			if e.RPC == "NextCrashRound" {
+				problem := rounds.verify(e)
+				if problem != nil {
+					problem.Segment = segment.Path
+					report.add(*problem)
+				} else {
+					report.CrashRounds++
+				}
+			} else if len(byFingerprint) > 0 {
+				recomputed, problem := recompute(e, byFingerprint)
+				if problem != nil {
+					problem.Segment = segment.Path
+					report.add(*problem)
+				} else if recomputed {
+					report.Recomputed++
+				}
+			}
+
+			if opts.ExpectEntries > 0 && e.Index+1 == opts.ExpectEntries {
+				headReached = true
+				if len(opts.ExpectLastHash) > 0 && e.Hash != opts.ExpectLastHash {
+					report.add(Problem{Segment: segment.Path, Index: e.Index, Kind: ProblemTampered, Detail: fmt.Sprintf("hash %s is not the expected head %s", e.Hash, opts.ExpectLastHash)})
+				}
+			} else if opts.ExpectEntries == 0 && e.Hash == opts.ExpectLastHash {
+				headReached = true
+			}
+
+			next = e.Index + 1
+			report.LastHash = e.Hash
+			return nil
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", segment.Path, err)
+		}
+	}
+
+	if !headReached {
+		report.add(Problem{Segment: dir, Index: next, Kind: ProblemTruncated, Detail: fmt.Sprintf("the log ends at %v entries with hash %s before the expected head", report.Entries, report.LastHash)})
+	}
+	return report, nil
+}
+
+// recompute checks a deterministic outcome, it returns false for draws that are not deterministic
+func recompute(e Entry, byFingerprint map[string]string) (bool, *Problem) {
+	var sequence, number int64
+	var probabilities []float64
+	var proof []byte
+	var namespace string
+
+	req, resp, err := e.Messages()
+	if err != nil {
+		return false, &Problem{Index: e.Index, Kind: ProblemUnverifiable, Detail: err.Error()}
+	}
+
+	switch r := req.(type) {
+	case *pb.GetDeterministicRandomRequest:
+		drawn := resp.(*pb.GetDeterministicRandomResponse)
+		sequence, probabilities, number, proof, namespace = r.Sequence, r.Probabilities, drawn.Number, drawn.VrfProof, r.Namespace
+	case *pb.DrawDeterministicRandomRequest:
+		drawn := resp.(*pb.DrawDeterministicRandomResponse)
+		sequence, probabilities, number, proof, namespace = drawn.Sequence, r.Probabilities, drawn.Number, drawn.VrfProof, r.Namespace
+	default:
+		return false, nil
+	}
+
+	seedHex, ok := byFingerprint[e.SeedFingerprint]
+	if !ok {
+		return false, &Problem{Index: e.Index, Kind: ProblemUnverifiable, Detail: fmt.Sprintf("drawn with seed %s", e.SeedFingerprint)}
+	}
+
+	// Derived versions draw every namespace with its own seed
+	if e.AlgorithmVersion == random.AlgorithmVersionHKDF || e.AlgorithmVersion == random.AlgorithmVersionVRFHKDF {
+		seedHex, err = random.DeriveSeed(seedHex, random.PurposeDeterministic, namespace)
+		if err != nil {
+			return false, &Problem{Index: e.Index, Kind: ProblemMismatch, Detail: err.Error()}
+		}
+	}
+
+	var expected int64
+	var expectedProof []byte
+	switch e.AlgorithmVersion {
+	case random.AlgorithmVersion, random.AlgorithmVersionHKDF:
+		expected, err = random.DeterministicRandom(seedHex, sequence, probabilities)
+	case random.AlgorithmVersionVRF, random.AlgorithmVersionVRFHKDF:
+		expected, expectedProof, err = random.VRFDeterministicRandom(seedHex, sequence, probabilities)
+	default:
+		return false, &Problem{Index: e.Index, Kind: ProblemUnverifiable, Detail: fmt.Sprintf("drawn with algorithm version %s", e.AlgorithmVersion)}
+	}
+
+	if err != nil {
+		return false, &Problem{Index: e.Index, Kind: ProblemMismatch, Detail: err.Error()}
+	} else if expected != number {
+		return false, &Problem{Index: e.Index, Kind: ProblemMismatch, Detail: fmt.Sprintf("sequence %v recorded %v, recomputed %v", sequence, number, expected)}
+	} else if !bytes.Equal(expectedProof, proof) {
+		return false, &Problem{Index: e.Index, Kind: ProblemMismatch, Detail: fmt.Sprintf("sequence %v recorded another proof than the recomputed one", sequence)}
+	}
+	return true, nil
+}
+
+// crashLink is the seed of a verified crash round
+type crashLink struct {
+	round int64
+	seed  []byte
+}
+
+// crashRounds verifies crash rounds, keeping the latest verified round of every
+// chain so a later round only hashes to it instead of to the terminating hash
+type crashRounds struct {
+	houseEdge float64
+	latest    map[string]crashLink
+}
+
+// verify checks that the seed of the round belongs to its chain and derives its crash point
+func (c *crashRounds) verify(e Entry) *Problem {
+	_, resp, err := e.Messages()
+	if err != nil {
+		return &Problem{Index: e.Index, Kind: ProblemUnverifiable, Detail: err.Error()}
+	}
+	drawn := resp.(*pb.NextCrashRoundResponse)
+	round := drawn.GetRound()
+
+	terminal := string(drawn.TerminalHash)
+	target, steps := drawn.TerminalHash, round.GetRound()
+	if latest, ok := c.latest[terminal]; ok && latest.round < round.GetRound() {
+		target, steps = latest.seed, round.GetRound()-latest.round
+	}
+	err = hashchain.Verify(target, steps, round.GetSeed())
+	if err != nil {
+		return &Problem{Index: e.Index, Kind: ProblemMismatch, Detail: fmt.Sprintf("crash round %v: %v", round.GetRound(), err)}
+	}
+
+	multiplier, err := hashchain.CrashMultiplier(round.GetSeed(), c.houseEdge)
+	if err != nil {
+		return &Problem{Index: e.Index, Kind: ProblemMismatch, Detail: err.Error()}
+	} else if multiplier != round.GetMultiplier() {
+		return &Problem{Index: e.Index, Kind: ProblemMismatch, Detail: fmt.Sprintf("crash round %v recorded %v, recomputed %v", round.GetRound(), round.GetMultiplier(), multiplier)}
+	}
+
+	if latest, ok := c.latest[terminal]; !ok || latest.round < round.GetRound() {
+		c.latest[terminal] = crashLink{round: round.GetRound(), seed: round.GetSeed()}
+	}
+	return nil
+}
@@ -0,0 +1,323 @@
+package audit
+
+import (
+	"encoding/json"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/fasttrack-solutions/random"
+	"github.com/fasttrack-solutions/random/pkg/hashchain"
+	"github.com/fasttrack-solutions/random/pkg/pb"
+	"github.com/stretchr/testify/assert"
+)
+
+const testSeedHex = "9912f3bcf715a55ae5c9d47f9f6562599912f3bcf715a55ae5c9d47f9f656259"
+
+// writeDeterministicLog writes count correct deterministic draws and returns the log directory
+func writeDeterministicLog(t *testing.T, count int) string {
+	dir := t.TempDir()
+	l, err := Open(Options{Dir: dir, MaxSegmentSize: 1 << 20, Fsync: FsyncNever})
+	assert.Nil(t, err)
+
+	fingerprint, err := random.SeedFingerprint(testSeedHex)
+	assert.Nil(t, err)
+
+	probabilities := []float64{0.2, 0.2, 0.2, 0.2, 0.2}
+	for i := 0; i < count; i++ {
+		number, errDraw := random.DeterministicRandom(testSeedHex, int64(i), probabilities)
+		assert.Nil(t, errDraw)
+
+		e, errEntry := NewEntry("client-1", "GetDeterministicRandom",
+			&pb.GetDeterministicRandomRequest{Sequence: int64(i), Probabilities: probabilities, Namespace: "campaign"},
+			&pb.GetDeterministicRandomResponse{Number: number},
+		)
+		assert.Nil(t, errEntry)
+		e.AlgorithmVersion = random.AlgorithmVersion
+		e.SeedFingerprint = fingerprint
+
+		_, err = l.Append(e)
+		assert.Nil(t, err)
+	}
+
+	e, err := NewEntry("client-1", "GetRandomFloat64", &pb.GetRandomFloat64Request{}, &pb.GetRandomFloat64Response{Number: 0.5})
+	assert.Nil(t, err)
+	_, err = l.Append(e)
+	assert.Nil(t, err)
+
+	assert.Nil(t, l.Close())
+	return dir
+}
+
+// rewriteSegment replaces the lines of the first segment
+func rewriteSegment(t *testing.T, dir string, fn func(lines []string) []string) {
+	segments, err := Segments(dir)
+	assert.Nil(t, err)
+
+	b, err := os.ReadFile(segments[0].Path)
+	assert.Nil(t, err)
+	lines := strings.Split(strings.TrimSuffix(string(b), "\n"), "\n")
+	lines = fn(lines)
+	assert.Nil(t, os.WriteFile(segments[0].Path, []byte(strings.Join(lines, "\n")+"\n"), 0600))
+}
+
+func Test_Verify(t *testing.T) {
+	dir := writeDeterministicLog(t, 5)
+
+	report, err := Verify(dir, VerifyOptions{Seeds: []string{testSeedHex}})
+	assert.Nil(t, err)
+	assert.True(t, report.OK(), report.Problems)
+	assert.Equal(t, uint64(6), report.Entries)
+	assert.Equal(t, uint64(5), report.Recomputed)
+
+	// Logs spanning a seed rotation are verified with every seed
+	report, err = Verify(dir, VerifyOptions{Seeds: []string{strings.Repeat("ab", 32), testSeedHex}})
+	assert.Nil(t, err)
+	assert.True(t, report.OK(), report.Problems)
+	assert.Equal(t, uint64(5), report.Recomputed)
+
+	// Without a seed only the chain is verified
+	report, err = Verify(dir, VerifyOptions{})
+	assert.Nil(t, err)
+	assert.True(t, report.OK())
+	assert.Equal(t, uint64(0), report.Recomputed)
+}
+
+func Test_Verify_RemovedEntry(t *testing.T) {
+	dir := writeDeterministicLog(t, 5)
+	rewriteSegment(t, dir, func(lines []string) []string {
+		return append(lines[:2], lines[3:]...)
+	})
+
+	report, err := Verify(dir, VerifyOptions{Seeds: []string{testSeedHex}})
+	assert.Nil(t, err)
+	assert.False(t, report.OK())
+	assert.Equal(t, ProblemGap, report.Problems[0].Kind)
+	assert.Equal(t, uint64(3), report.Problems[0].Index)
+	assert.Equal(t, ProblemTampered, report.Problems[1].Kind)
+}
+
+func Test_Verify_RemovedTail(t *testing.T) {
+	dir := writeDeterministicLog(t, 5)
+	head, err := Verify(dir, VerifyOptions{})
+	assert.Nil(t, err)
+	assert.Equal(t, uint64(6), head.Entries)
+
+	// The log holds its head and any earlier one
+	report, err := Verify(dir, VerifyOptions{ExpectEntries: 6, ExpectLastHash: head.LastHash})
+	assert.Nil(t, err)
+	assert.True(t, report.OK(), report.Problems)
+	report, err = Verify(dir, VerifyOptions{ExpectEntries: 4})
+	assert.Nil(t, err)
+	assert.True(t, report.OK(), report.Problems)
+
+	// The remaining chain is intact, only the expected head catches the removed entries
+	rewriteSegment(t, dir, func(lines []string) []string {
+		return lines[:4]
+	})
+	report, err = Verify(dir, VerifyOptions{})
+	assert.Nil(t, err)
+	assert.True(t, report.OK(), report.Problems)
+
+	for _, opts := range []VerifyOptions{
+		{ExpectEntries: 6},
+		{ExpectLastHash: head.LastHash},
+		{ExpectEntries: 6, ExpectLastHash: head.LastHash},
+	} {
+		report, err = Verify(dir, opts)
+		assert.Nil(t, err)
+		assert.Len(t, report.Problems, 1, opts)
+		assert.Equal(t, ProblemTruncated, report.Problems[0].Kind)
+		assert.Equal(t, uint64(4), report.Problems[0].Index)
+	}
+
+	// Another entry at the expected index is not the head
+	report, err = Verify(dir, VerifyOptions{ExpectEntries: 4, ExpectLastHash: head.LastHash})
+	assert.Nil(t, err)
+	assert.Len(t, report.Problems, 1)
+	assert.Equal(t, ProblemTampered, report.Problems[0].Kind)
+	assert.Equal(t, uint64(3), report.Problems[0].Index)
+}
+
+func Test_Verify_RemovedSegment(t *testing.T) {
+	dir := t.TempDir()
+	l, err := Open(Options{Dir: dir, MaxSegmentSize: 1024, Fsync: FsyncNever})
+	assert.Nil(t, err)
+	for i := 0; i < 20; i++ {
+		e, errEntry := NewEntry("client-1", "GetRandomFloat64", &pb.GetRandomFloat64Request{}, &pb.GetRandomFloat64Response{Number: 0.5})
+		assert.Nil(t, errEntry)
+		_, err = l.Append(e)
+		assert.Nil(t, err)
+	}
+	assert.Nil(t, l.Close())
+
+	head, err := Verify(dir, VerifyOptions{})
+	assert.Nil(t, err)
+	assert.True(t, head.OK(), head.Problems)
+	assert.Greater(t, head.Segments, 2)
+
+	segments, err := Segments(dir)
+	assert.Nil(t, err)
+	assert.Nil(t, os.Remove(segments[len(segments)-1].Path))
+
+	report, err := Verify(dir, VerifyOptions{})
+	assert.Nil(t, err)
+	assert.True(t, report.OK(), report.Problems)
+
+	report, err = Verify(dir, VerifyOptions{ExpectEntries: head.Entries, ExpectLastHash: head.LastHash})
+	assert.Nil(t, err)
+	assert.Len(t, report.Problems, 1)
+	assert.Equal(t, ProblemTruncated, report.Problems[0].Kind)
+}
+
+func Test_Verify_ChangedOutcome(t *testing.T) {
+	dir := writeDeterministicLog(t, 5)
+	rewriteSegment(t, dir, func(lines []string) []string {
+		// Change the outcome and fix up the hash, the chain still breaks at the next entry
+		var e Entry
+		assert.Nil(t, json.Unmarshal([]byte(lines[1]), &e))
+		e.Output = []byte(`{"number":"4","replayed":false}`)
+		e.Hash, _ = e.ComputeHash()
+		b, err := json.Marshal(e)
+		assert.Nil(t, err)
+		lines[1] = string(b)
+		return lines
+	})
+
+	report, err := Verify(dir, VerifyOptions{Seeds: []string{testSeedHex}})
+	assert.Nil(t, err)
+	kinds := []string{}
+	for _, p := range report.Problems {
+		kinds = append(kinds, p.Kind)
+	}
+	assert.Contains(t, kinds, ProblemMismatch)
+	assert.Contains(t, kinds, ProblemTampered)
+}
+
+func Test_Verify_OtherSeed(t *testing.T) {
+	dir := writeDeterministicLog(t, 2)
+
+	report, err := Verify(dir, VerifyOptions{Seeds: []string{strings.Repeat("ab", 32)}, MaxProblems: 1})
+	assert.Nil(t, err)
+	assert.Len(t, report.Problems, 1)
+	assert.Equal(t, ProblemUnverifiable, report.Problems[0].Kind)
+	assert.Equal(t, uint64(1), report.MoreProblems)
+}
+
+func Test_Verify_VRF(t *testing.T) {
+	dir := t.TempDir()
+	l, err := Open(Options{Dir: dir, MaxSegmentSize: 1 << 20, Fsync: FsyncNever})
+	assert.Nil(t, err)
+
+	fingerprint, err := random.SeedFingerprint(testSeedHex)
+	assert.Nil(t, err)
+
+	probabilities := []float64{0.5, 0.5}
+	for i := 0; i < 3; i++ {
+		number, proof, errDraw := random.VRFDeterministicRandom(testSeedHex, int64(i), probabilities)
+		assert.Nil(t, errDraw)
+		if i == 2 {
+			// A proof of another sequence
+			_, proof, _ = random.VRFDeterministicRandom(testSeedHex, 7, probabilities)
+		}
+
+		e, errEntry := NewEntry("client-1", "DrawDeterministicRandom",
+			&pb.DrawDeterministicRandomRequest{Namespace: "campaign", Probabilities: probabilities},
+			&pb.DrawDeterministicRandomResponse{Sequence: int64(i), Number: number, VrfProof: proof},
+		)
+		assert.Nil(t, errEntry)
+		e.AlgorithmVersion = random.AlgorithmVersionVRF
+		e.SeedFingerprint = fingerprint
+
+		_, err = l.Append(e)
+		assert.Nil(t, err)
+	}
+	assert.Nil(t, l.Close())
+
+	report, err := Verify(dir, VerifyOptions{Seeds: []string{testSeedHex}})
+	assert.Nil(t, err)
+	assert.Equal(t, uint64(2), report.Recomputed)
+	assert.Len(t, report.Problems, 1)
+	assert.Equal(t, ProblemMismatch, report.Problems[0].Kind)
+	assert.Equal(t, uint64(2), report.Problems[0].Index)
+}
+
+func Test_Verify_Derived(t *testing.T) {
+	dir := t.TempDir()
+	l, err := Open(Options{Dir: dir, MaxSegmentSize: 1 << 20, Fsync: FsyncNever})
+	assert.Nil(t, err)
+
+	fingerprint, err := random.SeedFingerprint(testSeedHex)
+	assert.Nil(t, err)
+	derived, err := random.DeriveSeed(testSeedHex, random.PurposeDeterministic, "campaign")
+	assert.Nil(t, err)
+
+	probabilities := make([]float64, 10)
+	for i := range probabilities {
+		probabilities[i] = 0.1
+	}
+	for i := 0; i < 10; i++ {
+		number, errDraw := random.DeterministicRandom(derived, int64(i), probabilities)
+		assert.Nil(t, errDraw)
+
+		e, errEntry := NewEntry("client-1", "GetDeterministicRandom",
+			&pb.GetDeterministicRandomRequest{Sequence: int64(i), Probabilities: probabilities, Namespace: "campaign"},
+			&pb.GetDeterministicRandomResponse{Number: number},
+		)
+		assert.Nil(t, errEntry)
+		e.AlgorithmVersion = random.AlgorithmVersionHKDF
+		e.SeedFingerprint = fingerprint
+
+		_, err = l.Append(e)
+		assert.Nil(t, err)
+	}
+	assert.Nil(t, l.Close())
+
+	report, err := Verify(dir, VerifyOptions{Seeds: []string{testSeedHex}})
+	assert.Nil(t, err)
+	assert.True(t, report.OK(), report.Problems)
+	assert.Equal(t, uint64(10), report.Recomputed)
+}
+
+func Test_Verify_CrashRounds(t *testing.T) {
+	chain, err := hashchain.Build(make([]byte, hashchain.HashSize), 100, 10)
+	assert.Nil(t, err)
+
+	dir := t.TempDir()
+	l, err := Open(Options{Dir: dir, MaxSegmentSize: 1 << 20, Fsync: FsyncNever})
+	assert.Nil(t, err)
+	appendRound := func(number int64, seed []byte, multiplier int64) {
+		e, errEntry := NewEntry("client-1", "NextCrashRound", &pb.NextCrashRoundRequest{}, &pb.NextCrashRoundResponse{
+			Round:        &pb.CrashRound{Round: number, Seed: seed, Multiplier: multiplier},
+			TerminalHash: chain.Terminal(),
+		})
+		assert.Nil(t, errEntry)
+		_, err = l.Append(e)
+		assert.Nil(t, err)
+	}
+
+	for number := int64(1); number <= 5; number++ {
+		seed, errRound := chain.Round(number)
+		assert.Nil(t, errRound)
+		multiplier, errMultiplier := hashchain.CrashMultiplier(seed, 0.01)
+		assert.Nil(t, errMultiplier)
+		appendRound(number, seed, multiplier)
+	}
+	// A seed outside the chain and a crash point that does not follow from the seed
+	appendRound(6, []byte(strings.Repeat("x", hashchain.HashSize)), 100)
+	seed, err := chain.Round(7)
+	assert.Nil(t, err)
+	appendRound(7, seed, 1<<40)
+	assert.Nil(t, l.Close())
+
+	report, err := Verify(dir, VerifyOptions{CrashHouseEdge: 0.01})
+	assert.Nil(t, err)
+	assert.Equal(t, uint64(5), report.CrashRounds)
+	assert.Len(t, report.Problems, 2)
+	for i, p := range report.Problems {
+		assert.Equal(t, ProblemMismatch, p.Kind)
+		assert.Equal(t, uint64(5+i), p.Index)
+	}
+	assert.Contains(t, report.Problems[0].Detail, "crash round 6")
+	assert.Contains(t, report.Problems[1].Detail, "crash round 7 recorded")
+}
@@ -0,0 +1,90 @@
+// Package bitstream reads the raw output of the generators, for statistical
+// test suites: the crypto path that UniformInt64 and UniformFloat64 draw from
+// and the hash stream DeterministicRandom selects its outcomes from.
+package bitstream
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+)
+
+const (
+	// SourceCrypto is crypto/rand, the source of the uniform draws
+	SourceCrypto = "crypto"
+	// SourceDeterministic is the hash stream of the deterministic draws of a seed
+	SourceDeterministic = "deterministic"
+)
+
+// Open returns the stream of source, the seed and the first sequence number only apply to SourceDeterministic
+func Open(source string, seedHex string, start int64) (io.Reader, error) {
+	switch source {
+	case SourceCrypto:
+		return rand.Reader, nil
+	case SourceDeterministic:
+		return NewDeterministic(seedHex, start)
+	default:
+		return nil, fmt.Errorf("unknown source %q, expected %s or %s", source, SourceCrypto, SourceDeterministic)
+	}
+}
+
+// Deterministic streams the 64-bit values deterministic draws select their
+// outcome from: the first 8 bytes of SHA-256(seed || sequence) for every sequence
+// from start on. A draw of sequence s with any probabilities depends on these
+// bytes only.
+type Deterministic struct {
+	// input is the seed followed by the big-endian sequence number
+	input    [40]byte
+	sequence int64
+	buf      [8]byte
+	off      int
+}
+
+// NewDeterministic creates the stream of seedHex from sequence start
+func NewDeterministic(seedHex string, start int64) (*Deterministic, error) {
+	seed, err := hex.DecodeString(seedHex)
+	if err != nil || len(seed) != 32 {
+		return nil, errors.New("seed must be 64 hex characters")
+	} else if start < 0 {
+		return nil, errors.New("start must be at least 0")
+	}
+
+	d := &Deterministic{sequence: start, off: 8}
+	copy(d.input[:32], seed)
+	return d, nil
+}
+
+// Read fills p with the stream, it only fails once the sequence numbers are exhausted
+func (d *Deterministic) Read(p []byte) (int, error) {
+	n := 0
+	for n < len(p) {
+		if d.off == len(d.buf) {
+			if d.sequence < 0 {
+				return n, io.EOF
+			}
+			binary.BigEndian.PutUint64(d.input[32:], uint64(d.sequence)) // #nosec G115 -- checked above
+			sum := sha256.Sum256(d.input[:])
+			copy(d.buf[:], sum[:8])
+			d.off = 0
+			// The sequence number overflows to negative after the last one
+			d.sequence++
+		}
+
+		c := copy(p[n:], d.buf[d.off:])
+		d.off += c
+		n += c
+	}
+	return n, nil
+}
+
+// Sequence is the sequence number of the next 8 bytes that are not read yet, partly read bytes included
+func (d *Deterministic) Sequence() int64 {
+	if d.off == len(d.buf) {
+		return d.sequence
+	}
+	return d.sequence - 1
+}
@@ -0,0 +1,141 @@
+package bitstream
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io"
+	"math"
+	"testing"
+
+	"github.com/fasttrack-solutions/random"
+	"github.com/stretchr/testify/assert"
+)
+
+const testSeed = "0f6aa358754a2b1fac2206849282b6c68dd6e086f635df2796acea52ae920ddc"
+
+func Test_Deterministic(t *testing.T) {
+	d, err := NewDeterministic(testSeed, 100)
+	assert.NoError(t, err)
+
+	// Read in odd sizes to cross the boundaries of the sequences
+	buf := make([]byte, 8*64)
+	for off := 0; off < len(buf); off += 3 {
+		n, errRead := d.Read(buf[off:min(off+3, len(buf))])
+		assert.NoError(t, errRead)
+		assert.Equal(t, min(3, len(buf)-off), n)
+	}
+	assert.Equal(t, int64(164), d.Sequence())
+
+	// The stream is the value the draws select from, with two halves the first bit is the outcome
+	for i := range 64 {
+		x := binary.BigEndian.Uint64(buf[i*8:])
+		index, errDraw := random.DeterministicRandom(testSeed, int64(100+i), []float64{0.5, 0.5})
+		assert.NoError(t, errDraw)
+		assert.Equal(t, int64(x>>63), index, i)
+	}
+}
+
+func Test_Open(t *testing.T) {
+	r, err := Open(SourceCrypto, "", 0)
+	assert.NoError(t, err)
+	_, err = io.ReadFull(r, make([]byte, 64))
+	assert.NoError(t, err)
+
+	r, err = Open(SourceDeterministic, testSeed, 0)
+	assert.NoError(t, err)
+	d, err := NewDeterministic(testSeed, 0)
+	assert.NoError(t, err)
+	a, b := make([]byte, 100), make([]byte, 100)
+	_, _ = io.ReadFull(r, a)
+	_, _ = io.ReadFull(d, b)
+	assert.Equal(t, b, a)
+
+	_, err = Open(SourceDeterministic, "00", 0)
+	assert.Error(t, err)
+	_, err = Open(SourceDeterministic, testSeed, -1)
+	assert.Error(t, err)
+	_, err = Open("file", "", 0)
+	assert.Error(t, err)
+}
+
+func Test_Parallel(t *testing.T) {
+	for _, workers := range []int{1, 3} {
+		p, err := NewParallel(testSeed, 7, workers)
+		assert.NoError(t, err)
+		d, err := NewDeterministic(testSeed, 7)
+		assert.NoError(t, err)
+
+		// Cross the boundaries of the chunks
+		a, b := make([]byte, 8*chunkSequences*3+5), make([]byte, 8*chunkSequences*3+5)
+		_, err = io.ReadFull(p, a)
+		assert.NoError(t, err)
+		_, _ = io.ReadFull(d, b)
+		assert.Equal(t, b, a, workers)
+		assert.NoError(t, p.Close())
+	}
+
+	// The stream ends at the last sequence number
+	p, err := NewParallel(testSeed, math.MaxInt64-1, 2)
+	assert.NoError(t, err)
+	all, err := io.ReadAll(p)
+	assert.NoError(t, err)
+	assert.Len(t, all, 16)
+	assert.NoError(t, p.Close())
+
+	// A last chunk of exactly chunkSequences sequences ends the stream too
+	p, err = NewParallel(testSeed, math.MaxInt64-chunkSequences+1, 2)
+	assert.NoError(t, err)
+	d, err := NewDeterministic(testSeed, math.MaxInt64-chunkSequences+1)
+	assert.NoError(t, err)
+	all, err = io.ReadAll(p)
+	assert.NoError(t, err)
+	want, err := io.ReadAll(d)
+	assert.NoError(t, err)
+	assert.Len(t, all, 8*chunkSequences)
+	assert.Equal(t, want, all)
+	assert.NoError(t, p.Close())
+
+	_, err = NewParallel(testSeed, 0, 0)
+	assert.Error(t, err)
+	_, err = NewParallel("00", 0, 1)
+	assert.Error(t, err)
+}
+
+func Test_Export(t *testing.T) {
+	stream := []byte{0x01, 0x02, 0x03, 0x04, 0xff, 0xff, 0xff, 0xff, 0x00, 0x00, 0x00, 0x07}
+
+	var buf bytes.Buffer
+	n, err := Export(&buf, bytes.NewReader(stream), FormatRaw, 0, "test")
+	assert.NoError(t, err)
+	assert.Equal(t, int64(12), n)
+	assert.Equal(t, stream, buf.Bytes())
+
+	buf.Reset()
+	n, err = Export(&buf, bytes.NewReader(stream), FormatUint32, 8, "test")
+	assert.NoError(t, err)
+	assert.Equal(t, int64(8), n)
+	assert.Equal(t, []byte{0x04, 0x03, 0x02, 0x01, 0xff, 0xff, 0xff, 0xff}, buf.Bytes())
+
+	buf.Reset()
+	_, err = Export(&buf, bytes.NewReader(stream), FormatDieharder, 12, "test")
+	assert.NoError(t, err)
+	assert.Equal(t, "#==================================================================\n"+
+		"# generator test\n"+
+		"#==================================================================\n"+
+		"type: d\ncount: 3\nnumbit: 32\n"+
+		"16909060\n4294967295\n7\n", buf.String())
+
+	// The stream is shorter than the size
+	_, err = Export(io.Discard, bytes.NewReader(stream), FormatRaw, 16, "test")
+	assert.Error(t, err)
+	// The stream ends within a word
+	_, err = Export(io.Discard, bytes.NewReader(stream[:6]), FormatUint32, 0, "test")
+	assert.Error(t, err)
+
+	_, err = Export(io.Discard, bytes.NewReader(stream), FormatUint32, 6, "test")
+	assert.Error(t, err)
+	_, err = Export(io.Discard, bytes.NewReader(stream), FormatDieharder, 0, "test")
+	assert.Error(t, err)
+	_, err = Export(io.Discard, bytes.NewReader(stream), "hex", 0, "test")
+	assert.Error(t, err)
+}
@@ -0,0 +1,105 @@
+package bitstream
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+)
+
+const (
+	// FormatRaw writes the bytes of the stream as they are
+	FormatRaw = "raw"
+	// FormatUint32 writes the stream as 32-bit words, each of 4 bytes of the stream read big-endian,
+	// in little-endian: the native words of TestU01 and PractRand (stdin32) on x86 and ARM
+	FormatUint32 = "u32"
+	// FormatDieharder writes the same words in decimal after the header of the ASCII file input of Dieharder (-g 202)
+	FormatDieharder = "dieharder"
+)
+
+// exportBuffer is the size of the reads and writes of Export
+const exportBuffer = 1 << 20
+
+// Export writes size bytes of r to w in format. A size of 0 writes until r
+// ends or w fails, except for FormatDieharder of which the header holds the
+// number of words. generator names the source in the Dieharder header.
+func Export(w io.Writer, r io.Reader, format string, size int64, generator string) (int64, error) {
+	if size < 0 {
+		return 0, errors.New("size must be at least 0")
+	}
+	switch format {
+	case FormatRaw:
+	case FormatUint32, FormatDieharder:
+		if size%4 != 0 {
+			return 0, errors.New("size must be a multiple of 4 bytes for 32-bit words")
+		} else if format == FormatDieharder && size == 0 {
+			return 0, errors.New("size is required for the header of Dieharder")
+		}
+	default:
+		return 0, fmt.Errorf("unknown format %q, expected %s, %s or %s", format, FormatRaw, FormatUint32, FormatDieharder)
+	}
+
+	if size > 0 {
+		r = io.LimitReader(r, size)
+	}
+	bw := bufio.NewWriterSize(w, exportBuffer)
+	if format == FormatDieharder {
+		_, err := fmt.Fprintf(bw, "#==================================================================\n"+
+			"# generator %s\n"+
+			"#==================================================================\n"+
+			"type: d\ncount: %v\nnumbit: 32\n", generator, size/4)
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	buf := make([]byte, exportBuffer)
+	var line []byte
+	var written int64
+	for {
+		n, err := io.ReadFull(r, buf)
+		if errors.Is(err, io.ErrUnexpectedEOF) && format != FormatRaw && n%4 != 0 {
+			return written, errors.New("stream ended within a 32-bit word")
+		}
+		chunk := buf[:n]
+
+		switch format {
+		case FormatRaw:
+			_, errWrite := bw.Write(chunk)
+			if errWrite != nil {
+				return written, errWrite
+			}
+		case FormatUint32:
+			for i := 0; i+4 <= len(chunk); i += 4 {
+				binary.LittleEndian.PutUint32(chunk[i:], binary.BigEndian.Uint32(chunk[i:]))
+			}
+			_, errWrite := bw.Write(chunk)
+			if errWrite != nil {
+				return written, errWrite
+			}
+		case FormatDieharder:
+			for i := 0; i+4 <= len(chunk); i += 4 {
+				line = strconv.AppendUint(line[:0], uint64(binary.BigEndian.Uint32(chunk[i:])), 10)
+				line = append(line, '\n')
+				_, errWrite := bw.Write(line)
+				if errWrite != nil {
+					return written, errWrite
+				}
+			}
+		}
+		written += int64(n)
+
+		if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+			break
+		} else if err != nil {
+			return written, err
+		}
+	}
+
+	if size > 0 && written < size {
+		return written, fmt.Errorf("stream ended after %v of %v bytes", written, size)
+	}
+	return written, bw.Flush()
+}
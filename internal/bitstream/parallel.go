@@ -0,0 +1,139 @@
+package bitstream
+
+import (
+	"encoding/hex"
+	"errors"
+	"io"
+	"math"
+	"sync"
+)
+
+// chunkSequences is the number of sequences a worker hashes at a time, 512 KiB of the stream
+const chunkSequences = 1 << 16
+
+// Parallel reads the same stream as Deterministic, hashing chunks of sequences on several workers
+type Parallel struct {
+	// chunks delivers the chunks in the order of their sequences, each on its own channel
+	chunks chan chan chunk
+	done   chan struct{}
+	once   sync.Once
+	wg     sync.WaitGroup
+
+	buf []byte
+	off int
+	err error
+}
+
+// chunk is the stream of a job, err is the error reading it, after the bytes read
+type chunk struct {
+	buf []byte
+	err error
+}
+
+type chunkJob struct {
+	start int64
+	count int64
+	out   chan chunk
+}
+
+// NewParallel creates the stream of seedHex from sequence start, hashed by workers. Close stops the workers.
+func NewParallel(seedHex string, start int64, workers int) (*Parallel, error) {
+	seed, err := hex.DecodeString(seedHex)
+	if err != nil || len(seed) != 32 {
+		return nil, errors.New("seed must be 64 hex characters")
+	} else if start < 0 {
+		return nil, errors.New("start must be at least 0")
+	} else if workers < 1 {
+		return nil, errors.New("workers must be at least 1")
+	}
+
+	p := &Parallel{
+		chunks: make(chan chan chunk, 2*workers),
+		done:   make(chan struct{}),
+	}
+	jobs := make(chan chunkJob, 2*workers)
+
+	for range workers {
+		p.wg.Add(1)
+		go func() {
+			defer p.wg.Done()
+			d := &Deterministic{}
+			copy(d.input[:32], seed)
+			for job := range jobs {
+				buf := make([]byte, 8*job.count)
+				d.sequence, d.off = job.start, 8
+				n, errRead := d.Read(buf)
+				job.out <- chunk{buf: buf[:n], err: errRead}
+			}
+		}()
+	}
+
+	// Queue the chunks in order, the workers fill them in any order
+	p.wg.Add(1)
+	go func() {
+		defer p.wg.Done()
+		defer close(jobs)
+		defer close(p.chunks)
+		for next := start; ; {
+			count := int64(chunkSequences)
+			last := next > math.MaxInt64-count
+			if last {
+				// The last chunk ends at the last sequence number
+				count = math.MaxInt64 - next + 1
+			}
+
+			out := make(chan chunk, 1)
+			select {
+			case p.chunks <- out:
+			case <-p.done:
+				return
+			}
+			select {
+			case jobs <- chunkJob{start: next, count: count, out: out}:
+			case <-p.done:
+				return
+			}
+
+			if last {
+				return
+			}
+			next += count
+		}
+	}()
+	return p, nil
+}
+
+// Read fills b with the stream, it fails with io.EOF once the sequence numbers are exhausted
+func (p *Parallel) Read(b []byte) (int, error) {
+	n := 0
+	for n < len(b) {
+		if p.off == len(p.buf) {
+			if p.err != nil {
+				return n, p.err
+			}
+			out, ok := <-p.chunks
+			if !ok {
+				if n > 0 {
+					return n, nil
+				}
+				return 0, io.EOF
+			}
+			c := <-out
+			p.buf, p.off, p.err = c.buf, 0, c.err
+		}
+
+		c := copy(b[n:], p.buf[p.off:])
+		p.off += c
+		n += c
+	}
+	return n, nil
+}
+
+// Close stops the workers
+func (p *Parallel) Close() error {
+	p.once.Do(func() {
+		close(p.done)
+	})
+	p.wg.Wait()
+	return nil
+}
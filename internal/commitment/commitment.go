@@ -0,0 +1,203 @@
+// Package commitment publishes a hash commitment of every seed of the keyring
+// before its epoch starts and reveals the seed once the epoch has ended, so
+// auditors can check that the seed of a campaign was fixed in advance.
+package commitment
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/fasttrack-solutions/random"
+	"github.com/fasttrack-solutions/random/internal/keyring"
+	"github.com/fasttrack-solutions/random/internal/storage"
+)
+
+// keyPrefix is the storage prefix of the commitments, followed by the epoch
+const keyPrefix = "commitment/"
+
+var (
+	// ErrMismatch is returned when a key does not match the commitment published for its epoch
+	ErrMismatch = errors.New("key does not match the published commitment of its epoch")
+	// ErrNotExpired is returned when revealing the seed of an epoch that has not ended
+	ErrNotExpired = errors.New("epoch has not ended")
+	// ErrNotPublished is returned for an epoch without a commitment
+	ErrNotPublished = errors.New("no commitment was published for the epoch")
+)
+
+// Commitment is the persisted commitment of the seed of an epoch, it never changes once published
+type Commitment struct {
+	KeyID       string    `json:"keyId"`
+	Epoch       int64     `json:"epoch"`
+	ActiveFrom  time.Time `json:"activeFrom"`
+	Commitment  string    `json:"commitment"`
+	PublishedAt time.Time `json:"publishedAt"`
+	// Late is set when the key was already active when its commitment was
+	// published, the commitment does not prove the seed was fixed before its draws
+	Late bool `json:"late,omitempty"`
+}
+
+// Epoch is a commitment and the time its epoch ends, zero while no later epoch is scheduled
+type Epoch struct {
+	Commitment
+	ActiveUntil time.Time
+}
+
+// Registry persists the commitments in the store
+type Registry struct {
+	store storage.Store
+	now   func() time.Time
+}
+
+// New creates a registry keeping the commitments in store
+func New(store storage.Store) *Registry {
+	return &Registry{
+		store: store,
+		now:   time.Now,
+	}
+}
+
+// Publish persists the commitments of the keys that have none yet. A key
+// that is already active, without a later ActiveFrom, is committed as Late. It
+// fails with ErrMismatch when a key differs from the commitment published for
+// its epoch, a keyring replacing a committed seed must not be used.
+func (r *Registry) Publish(ctx context.Context, ring *keyring.Keyring) error {
+	now := r.now().UTC()
+	return r.store.Update(ctx, func(tx storage.Tx) error {
+		for _, k := range ring.Keys() {
+			c, err := commit(k)
+			if err != nil {
+				return err
+			}
+
+			published, err := get(tx, k.Epoch)
+			if err == nil {
+				if published.KeyID != c.KeyID || published.Commitment != c.Commitment {
+					return fmt.Errorf("%w: key %q of epoch %v", ErrMismatch, k.ID, k.Epoch)
+				}
+				continue
+			} else if !errors.Is(err, ErrNotPublished) {
+				return err
+			}
+
+			c.PublishedAt = now
+			c.Late = !c.ActiveFrom.After(now)
+			v, err := json.Marshal(c)
+			if err != nil {
+				return err
+			}
+			err = tx.Put(key(k.Epoch), v, 0)
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// Current returns the commitments of the active epoch and the epochs scheduled after it
+func (r *Registry) Current(ctx context.Context, ring *keyring.Keyring) ([]Epoch, error) {
+	now := r.now()
+	keys := ring.Keys()
+	active, err := ring.Active(now)
+	if err != nil && !errors.Is(err, keyring.ErrKeyNotActive) {
+		return nil, err
+	}
+
+	var epochs []Epoch
+	err = r.store.View(ctx, func(tx storage.Tx) error {
+		for i, k := range keys {
+			if k.Epoch < active.Epoch {
+				continue
+			}
+
+			published, errGet := get(tx, k.Epoch)
+			if errGet != nil {
+				return errGet
+			}
+			epochs = append(epochs, epoch(published, keys, i))
+		}
+		return nil
+	})
+	return epochs, err
+}
+
+// Reveal returns the commitment and the seed of the key with the id and/or epoch, once a later epoch is active
+func (r *Registry) Reveal(ctx context.Context, ring *keyring.Keyring, id string, epochNumber int64) (Epoch, string, error) {
+	now := r.now()
+	keys := ring.Keys()
+	for i, k := range keys {
+		if (len(id) > 0 && k.ID != id) || (epochNumber != 0 && k.Epoch != epochNumber) {
+			continue
+		}
+
+		var published Commitment
+		err := r.store.View(ctx, func(tx storage.Tx) error {
+			var errGet error
+			published, errGet = get(tx, k.Epoch)
+			return errGet
+		})
+		if err != nil {
+			return Epoch{}, "", err
+		}
+
+		e := epoch(published, keys, i)
+		if e.ActiveUntil.IsZero() || e.ActiveUntil.After(now) {
+			return Epoch{}, "", fmt.Errorf("%w: key %q of epoch %v is in use until a later epoch is active", ErrNotExpired, k.ID, k.Epoch)
+		}
+
+		c, err := commit(k)
+		if err != nil {
+			return Epoch{}, "", err
+		} else if c.Commitment != published.Commitment {
+			return Epoch{}, "", fmt.Errorf("%w: key %q of epoch %v", ErrMismatch, k.ID, k.Epoch)
+		}
+		return e, k.SeedHex, nil
+	}
+
+	if len(id) == 0 && epochNumber == 0 {
+		return Epoch{}, "", fmt.Errorf("%w: a key id or epoch is required", keyring.ErrUnknownKey)
+	}
+	return Epoch{}, "", fmt.Errorf("%w: key %q of epoch %v", keyring.ErrUnknownKey, id, epochNumber)
+}
+
+func commit(k keyring.Key) (Commitment, error) {
+	commitment, err := random.SeedCommitment(k.SeedHex)
+	if err != nil {
+		return Commitment{}, err
+	}
+	return Commitment{
+		KeyID:      k.ID,
+		Epoch:      k.Epoch,
+		ActiveFrom: k.ActiveFrom,
+		Commitment: commitment,
+	}, nil
+}
+
+// epoch ends the commitment of keys[i] when the next key becomes active
+func epoch(c Commitment, keys []keyring.Key, i int) Epoch {
+	e := Epoch{Commitment: c}
+	if i+1 < len(keys) {
+		e.ActiveUntil = keys[i+1].ActiveFrom
+	}
+	return e
+}
+
+func get(tx storage.Tx, epoch int64) (Commitment, error) {
+	v, err := tx.Get(key(epoch))
+	if errors.Is(err, storage.ErrNotFound) {
+		return Commitment{}, fmt.Errorf("%w: epoch %v", ErrNotPublished, epoch)
+	} else if err != nil {
+		return Commitment{}, err
+	}
+
+	var c Commitment
+	err = json.Unmarshal(v, &c)
+	return c, err
+}
+
+func key(epoch int64) string {
+	return fmt.Sprintf("%s%020d", keyPrefix, epoch)
+}
@@ -0,0 +1,162 @@
+package commitment
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/fasttrack-solutions/random"
+	"github.com/fasttrack-solutions/random/internal/deterministic"
+	"github.com/fasttrack-solutions/random/internal/keyring"
+	"github.com/fasttrack-solutions/random/internal/storage"
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	seedA = "0f6aa358754a2b1fac2206849282b6c68dd6e086f635df2796acea52ae920ddc"
+	seedB = "38cc89e51e52d12e44fc73d51efb003a51770e1df62228162bfbaf99fd5406a0"
+	seedC = "4db0a9ca611ab3aad0139ce633bfbbe10f576969254a48c460deec58fc6c7d10"
+)
+
+var start = time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+
+func Test_Registry(t *testing.T) {
+	ctx := context.Background()
+	ring, err := keyring.New([]keyring.Key{
+		{ID: "2025-01", Epoch: 1, ActiveFrom: start, SeedHex: seedA},
+		{ID: "2025-02", Epoch: 2, ActiveFrom: start.AddDate(0, 1, 0), SeedHex: seedB},
+	})
+	assert.Nil(t, err)
+
+	r := New(storage.NewMemoryStore())
+	r.now = func() time.Time { return start.Add(time.Hour) }
+	assert.Nil(t, r.Publish(ctx, ring))
+
+	epochs, err := r.Current(ctx, ring)
+	assert.Nil(t, err)
+	assert.Len(t, epochs, 2)
+	commitmentA, err := random.SeedCommitment(seedA)
+	assert.Nil(t, err)
+	assert.Equal(t, Epoch{
+		Commitment: Commitment{
+			KeyID:       "2025-01",
+			Epoch:       1,
+			ActiveFrom:  start,
+			Commitment:  commitmentA,
+			PublishedAt: start.Add(time.Hour),
+			// Epoch 1 was active before its commitment was published
+			Late: true,
+		},
+		ActiveUntil: start.AddDate(0, 1, 0),
+	}, epochs[0])
+	assert.True(t, epochs[1].ActiveUntil.IsZero())
+	assert.False(t, epochs[1].Late)
+	assert.True(t, epochs[1].PublishedAt.Before(epochs[1].ActiveFrom))
+
+	// Epoch 1 is still active
+	_, _, err = r.Reveal(ctx, ring, "", 1)
+	assert.ErrorIs(t, err, ErrNotExpired)
+
+	// Once epoch 2 is active, epoch 1 is revealed and no longer listed
+	r.now = func() time.Time { return start.AddDate(0, 1, 1) }
+	e, seedHex, err := r.Reveal(ctx, ring, "2025-01", 0)
+	assert.Nil(t, err)
+	assert.Equal(t, seedA, seedHex)
+	assert.Equal(t, commitmentA, e.Commitment.Commitment)
+	assert.Equal(t, start.Add(time.Hour), e.PublishedAt)
+
+	_, _, err = r.Reveal(ctx, ring, "2025-02", 0)
+	assert.ErrorIs(t, err, ErrNotExpired)
+	_, _, err = r.Reveal(ctx, ring, "2025-03", 0)
+	assert.ErrorIs(t, err, keyring.ErrUnknownKey)
+
+	epochs, err = r.Current(ctx, ring)
+	assert.Nil(t, err)
+	assert.Len(t, epochs, 1)
+	assert.Equal(t, int64(2), epochs[0].Epoch)
+
+	// Adding an epoch keeps the earlier commitments
+	added, err := keyring.New(append(ring.Keys(), keyring.Key{ID: "2025-03", Epoch: 3, ActiveFrom: start.AddDate(0, 2, 0), SeedHex: seedC}))
+	assert.Nil(t, err)
+	assert.Nil(t, r.Publish(ctx, added))
+	epochs, err = r.Current(ctx, added)
+	assert.Nil(t, err)
+	assert.Len(t, epochs, 2)
+	assert.Equal(t, start.Add(time.Hour), epochs[0].PublishedAt)
+	assert.Equal(t, start.AddDate(0, 1, 1), epochs[1].PublishedAt)
+	assert.False(t, epochs[1].Late)
+
+	// A committed seed can not be replaced
+	replaced, err := keyring.New([]keyring.Key{
+		{ID: "2025-01", Epoch: 1, ActiveFrom: start, SeedHex: seedA},
+		{ID: "2025-02", Epoch: 2, ActiveFrom: start.AddDate(0, 1, 0), SeedHex: seedC},
+	})
+	assert.Nil(t, err)
+	assert.ErrorIs(t, r.Publish(ctx, replaced), ErrMismatch)
+	_, _, err = r.Reveal(ctx, replaced, "", 2)
+	assert.ErrorIs(t, err, ErrNotExpired)
+}
+
+func Test_Registry_Late(t *testing.T) {
+	ctx := context.Background()
+	ring, err := keyring.New([]keyring.Key{
+		{ID: "default", Epoch: 1, SeedHex: seedA},
+		{ID: "2025-01", Epoch: 2, ActiveFrom: start, SeedHex: seedB},
+	})
+	assert.Nil(t, err)
+
+	// A key without ActiveFrom, or with one that is not after the publication, is late
+	r := New(storage.NewMemoryStore())
+	r.now = func() time.Time { return start }
+	assert.Nil(t, r.Publish(ctx, ring))
+
+	epochs, err := r.Current(ctx, ring)
+	assert.Nil(t, err)
+	assert.Len(t, epochs, 1)
+	assert.Equal(t, "2025-01", epochs[0].KeyID)
+	assert.True(t, epochs[0].Late)
+
+	e, _, err := r.Reveal(ctx, ring, "default", 0)
+	assert.Nil(t, err)
+	assert.True(t, e.Late)
+
+	// Publishing again does not change the commitments
+	r.now = func() time.Time { return start.AddDate(0, 1, 0) }
+	assert.Nil(t, r.Publish(ctx, ring))
+	e, _, err = r.Reveal(ctx, ring, "default", 0)
+	assert.Nil(t, err)
+	assert.Equal(t, start, e.PublishedAt)
+}
+
+func Test_Registry_RevealedKeyDoesNotDraw(t *testing.T) {
+	ctx := context.Background()
+	now := time.Now().UTC()
+	ring, err := keyring.New([]keyring.Key{
+		{ID: "first", Epoch: 1, ActiveFrom: now.Add(-48 * time.Hour), SeedHex: seedA},
+		{ID: "second", Epoch: 2, ActiveFrom: now.Add(-24 * time.Hour), SeedHex: seedB},
+	})
+	assert.Nil(t, err)
+	r := New(storage.NewMemoryStore())
+	assert.Nil(t, r.Publish(ctx, ring))
+	drawer, err := deterministic.New(ring, deterministic.AlgorithmSHA256, deterministic.DerivationNone)
+	assert.Nil(t, err)
+
+	// Once revealed anyone computes the outcomes of the first seed
+	_, seedHex, err := r.Reveal(ctx, ring, "first", 0)
+	assert.Nil(t, err)
+	assert.Equal(t, seedA, seedHex)
+
+	// so it draws no new outcome, by key id or epoch, unless replaying an earlier draw
+	notReplayed := func(keyring.Key) (bool, error) { return false, nil }
+	_, err = drawer.DrawKey("first", 0, notReplayed)
+	assert.ErrorIs(t, err, keyring.ErrKeyRetired)
+	_, err = drawer.DrawKey("", 1, notReplayed)
+	assert.ErrorIs(t, err, keyring.ErrKeyRetired)
+	key, err := drawer.DrawKey("", 0, notReplayed)
+	assert.Nil(t, err)
+	assert.Equal(t, "second", key.ID)
+
+	// The seed of the active epoch is not revealed
+	_, _, err = r.Reveal(ctx, ring, "second", 0)
+	assert.ErrorIs(t, err, ErrNotExpired)
+}
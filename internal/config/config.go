@@ -3,12 +3,37 @@ package config
 import (
 	"flag"
 	"github.com/fasttrack-solutions/envs"
+	"time"
 )
 
 var (
-	GRPCPort = flag.Int("grpc-port", 3401, "Port for gRPC server")
-	HTTPPort = flag.Int("http-port", 3402, "Port for HTTP server")
-	SEEDHEX  = flag.String("seed-hex", "0000000000000000000000000000000000000000000000000000000000000000", "Seed for the deterministic random number")
+	GRPCPort               = flag.Int("grpc-port", 3401, "Port for gRPC server")
+	HTTPPort               = flag.Int("http-port", 3402, "Port for HTTP server")
+	SEEDHEX                = flag.String("seed-hex", "0000000000000000000000000000000000000000000000000000000000000000", "Seed for the deterministic random number")
+	SeedFile               = flag.String("seed-file", "", "File holding the hex seed, i.e. a mounted secret, replaces -seed-hex when set")
+	KeyringFile            = flag.String("keyring-file", "", "JSON file of seeds with ids, epochs and activation times, replaces -seed-hex when set")
+	KeyringDir             = flag.String("keyring-dir", "", "Directory of JSON key files, one key per file, replaces -seed-hex when set")
+	KeystoreFile           = flag.String("keystore-file", "", "Passphrase encrypted keystore created with randomctl keystore, replaces -seed-hex when set")
+	KeystorePassphraseFile = flag.String("keystore-passphrase-file", "", "File holding the keystore passphrase, read from $KEYSTORE_PASSPHRASE if empty")
+	SeedSharesAddr         = flag.String("seed-shares-addr", "", "Loopback address of an admin endpoint collecting Shamir shares of the seed before the server starts, i.e. 127.0.0.1:3403; replaces -seed-hex when set")
+	SeedSharesFingerprint  = flag.String("seed-shares-fingerprint", "", "Fingerprint of the seed whose shares -seed-shares-addr accepts, any seed if empty")
+	SeedCommitments        = flag.Bool("seed-commitments", false, "Publish a commitment of every seed before its epoch on ListSeedCommitments and /seedCommitments, requires a persistent -store")
+	SeedReveal             = flag.Bool("seed-reveal", false, "Reveal the seeds of ended epochs on RevealSeed and /revealSeed, to check them against their published commitment; publishes the commitments and requires a persistent -store")
+	KeyringReloadInterval  = flag.Duration("keyring-reload-interval", 10*time.Second, "How often the seed, keyring or keystore files are checked for changes")
+	DeterministicAlgorithm = flag.String("deterministic-algorithm", "sha256", "Algorithm of deterministic draws: sha256, or vrf to return a publicly verifiable proof with every outcome")
+	SeedDerivation         = flag.String("seed-derivation", "none", "How namespaces are drawn: none shares the seed, hkdf derives an independent seed per namespace")
+	SequenceMode           = flag.String("sequence-mode", "client", "Who chooses the sequence of deterministic draws: client or server")
+	ReplayTokens           = flag.String("replay-tokens", "", "Comma separated tokens of clients allowed to replay sequences in server mode")
+	SingleUse              = flag.String("single-use", "off", "What to do when a client chosen sequence is drawn again: off, reject or replay")
+	IdempotencyTTL         = flag.Duration("idempotency-ttl", 24*time.Hour, "How long the result of a request with an idempotency key is kept")
+	AuditDir               = flag.String("audit-dir", "", "Directory of the hash-chained audit log of every draw, disabled if empty")
+	AuditSegmentSize       = flag.Int64("audit-segment-size", 64<<20, "Size in bytes after which the audit log starts a new segment file")
+	AuditFsync             = flag.String("audit-fsync", "always", "When the audit log is synced to disk: always, interval or never")
+	AuditFsyncInterval     = flag.Duration("audit-fsync-interval", time.Second, "Period of background syncs of the audit log with -audit-fsync interval")
+	Store                  = flag.String("store", "", "State store: a file path, file:///path, redis://host:port/db or in-memory if empty")
+	CrashChainFile         = flag.String("crash-chain-file", "", "Hash chain created with randomctl chain generate that serves the rounds of a crash game, disabled if empty; requires a persistent -store")
+	CrashHouseEdge         = flag.Float64("crash-house-edge", 0.01, "House edge of crash points, between 0 and 1")
+	ReceiptKeyFile         = flag.String("receipt-key-file", "", "PEM encoded Ed25519 private key that signs a receipt of every draw, disabled if empty")
 )
 
 func init() {
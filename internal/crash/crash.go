@@ -0,0 +1,153 @@
+// Package crash serves the rounds of a crash game from a hash chain, keeping
+// the number of the last round in the store so restarts never repeat a round.
+package crash
+
+import (
+	"context"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/fasttrack-solutions/random/internal/storage"
+	"github.com/fasttrack-solutions/random/pkg/hashchain"
+)
+
+// keyPrefix is the storage prefix of the last round, followed by the terminating hash of the chain
+const keyPrefix = "crash/"
+
+// ErrNotPlayed is returned for a round that has not been served yet, its seed is still secret
+var ErrNotPlayed = errors.New("round has not been played")
+
+// Round is a round of the game
+type Round struct {
+	Number int64
+	Seed   []byte
+	// Multiplier is the crash point in hundredths, 100 is 1.00x
+	Multiplier int64
+}
+
+// Game serves the rounds of a chain in order
+type Game struct {
+	chain     *hashchain.Chain
+	houseEdge float64
+	store     storage.Store
+	key       string
+}
+
+// New creates a game of the chain with a house edge between 0 and 1
+func New(chain *hashchain.Chain, houseEdge float64, store storage.Store) (*Game, error) {
+	// Check the house edge once instead of on every round
+	_, err := hashchain.CrashMultiplier(make([]byte, hashchain.HashSize), houseEdge)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Game{
+		chain:     chain,
+		houseEdge: houseEdge,
+		store:     store,
+		key:       keyPrefix + hex.EncodeToString(chain.Terminal()),
+	}, nil
+}
+
+// Open reads a chain file written by hashchain.Chain.WriteTo and creates its game
+func Open(path string, houseEdge float64, store storage.Store) (*Game, error) {
+	f, err := os.Open(filepath.Clean(path))
+	if err != nil {
+		return nil, fmt.Errorf("failed to open hash chain: %w", err)
+	}
+	defer f.Close()
+
+	chain, err := hashchain.Read(f)
+	if err != nil {
+		return nil, err
+	}
+	return New(chain, houseEdge, store)
+}
+
+// Terminal returns the terminating hash of the chain
+func (g *Game) Terminal() []byte {
+	return g.chain.Terminal()
+}
+
+// Length is the number of rounds of the chain
+func (g *Game) Length() int64 {
+	return g.chain.Length()
+}
+
+// HouseEdge is the house edge of the crash points
+func (g *Game) HouseEdge() float64 {
+	return g.houseEdge
+}
+
+// Next starts the next round
+func (g *Game) Next(ctx context.Context) (Round, error) {
+	var number int64
+	err := g.store.Update(ctx, func(tx storage.Tx) error {
+		played, err := played(tx, g.key)
+		if err != nil {
+			return err
+		} else if played >= g.chain.Length() {
+			return fmt.Errorf("%w after %v rounds", hashchain.ErrExhausted, played)
+		}
+
+		number = played + 1
+		var buf [8]byte
+		binary.BigEndian.PutUint64(buf[:], uint64(number)) // #nosec G115 -- rounds are positive
+		return tx.Put(g.key, buf[:], 0)
+	})
+	if err != nil {
+		return Round{}, err
+	}
+
+	return g.round(number)
+}
+
+// Get returns a round that has been played
+func (g *Game) Get(ctx context.Context, number int64) (Round, error) {
+	last, err := g.Played(ctx)
+	if err != nil {
+		return Round{}, err
+	} else if number < 1 || number > last {
+		return Round{}, fmt.Errorf("%w: round %v, %v rounds were played", ErrNotPlayed, number, last)
+	}
+	return g.round(number)
+}
+
+// Played returns the number of rounds played
+func (g *Game) Played(ctx context.Context) (int64, error) {
+	var last int64
+	err := g.store.View(ctx, func(tx storage.Tx) error {
+		var err error
+		last, err = played(tx, g.key)
+		return err
+	})
+	return last, err
+}
+
+func (g *Game) round(number int64) (Round, error) {
+	seed, err := g.chain.Round(number)
+	if err != nil {
+		return Round{}, err
+	}
+	multiplier, err := hashchain.CrashMultiplier(seed, g.houseEdge)
+	if err != nil {
+		return Round{}, err
+	}
+	return Round{Number: number, Seed: seed, Multiplier: multiplier}, nil
+}
+
+func played(tx storage.Tx, key string) (int64, error) {
+	v, err := tx.Get(key)
+	if errors.Is(err, storage.ErrNotFound) {
+		return 0, nil
+	} else if err != nil {
+		return 0, err
+	} else if len(v) != 8 {
+		return 0, fmt.Errorf("invalid round counter %s", key)
+	}
+	return int64(binary.BigEndian.Uint64(v)), nil // #nosec G115 -- only positive values are stored
+}
@@ -0,0 +1,50 @@
+package crash
+
+import (
+	"context"
+	"testing"
+
+	"github.com/fasttrack-solutions/random/internal/storage"
+	"github.com/fasttrack-solutions/random/pkg/hashchain"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Game(t *testing.T) {
+	ctx := context.Background()
+	chain, err := hashchain.Generate(3, 2)
+	assert.Nil(t, err)
+	store := storage.NewMemoryStore()
+
+	g, err := New(chain, 0.01, store)
+	assert.Nil(t, err)
+
+	_, err = g.Get(ctx, 1)
+	assert.ErrorIs(t, err, ErrNotPlayed)
+
+	previous := g.Terminal()
+	for number := int64(1); number <= 3; number++ {
+		r, errNext := g.Next(ctx)
+		assert.Nil(t, errNext)
+		assert.Equal(t, number, r.Number)
+		assert.Nil(t, hashchain.VerifyNext(previous, r.Seed))
+		assert.GreaterOrEqual(t, r.Multiplier, int64(100))
+		previous = r.Seed
+
+		got, errGet := g.Get(ctx, number)
+		assert.Nil(t, errGet)
+		assert.Equal(t, r, got)
+	}
+
+	_, err = g.Next(ctx)
+	assert.ErrorIs(t, err, hashchain.ErrExhausted)
+
+	// The rounds played survive a restart
+	restarted, err := New(chain, 0.01, store)
+	assert.Nil(t, err)
+	played, err := restarted.Played(ctx)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(3), played)
+
+	_, err = New(chain, 1.5, store)
+	assert.NotNil(t, err)
+}
@@ -0,0 +1,184 @@
+// Package deterministic draws deterministic outcomes with the algorithm the server is configured with.
+package deterministic
+
+import (
+	"errors"
+	"fmt"
+	"sync/atomic"
+	"time"
+
+	"github.com/fasttrack-solutions/random"
+	"github.com/fasttrack-solutions/random/internal/keyring"
+)
+
+const (
+	// AlgorithmSHA256 derives outcomes from a SHA-256 of the seed and sequence, see random.DeterministicRandom
+	AlgorithmSHA256 = "sha256"
+	// AlgorithmVRF derives outcomes from an ECVRF proof keyed by the seed, see random.VRFDeterministicRandom
+	AlgorithmVRF = "vrf"
+)
+
+const (
+	// DerivationNone draws every namespace with the seed of the key
+	DerivationNone = "none"
+	// DerivationHKDF draws every namespace with its own seed derived from the seed of the key, see random.DeriveSeed
+	DerivationHKDF = "hkdf"
+)
+
+// Drawer draws deterministic outcomes with the keys of a keyring and one algorithm.
+// The keyring can be replaced while drawing.
+type Drawer struct {
+	algorithm  string
+	derivation string
+	ring       atomic.Pointer[keyring.Keyring]
+	now        func() time.Time
+}
+
+// New creates a drawer
+func New(ring *keyring.Keyring, algorithm string, derivation string) (*Drawer, error) {
+	if algorithm != AlgorithmSHA256 && algorithm != AlgorithmVRF {
+		return nil, fmt.Errorf("invalid deterministic algorithm %q; valid algorithms are %q and %q", algorithm, AlgorithmSHA256, AlgorithmVRF)
+	} else if derivation != DerivationNone && derivation != DerivationHKDF {
+		return nil, fmt.Errorf("invalid seed derivation %q; valid derivations are %q and %q", derivation, DerivationNone, DerivationHKDF)
+	}
+
+	d := &Drawer{
+		algorithm:  algorithm,
+		derivation: derivation,
+		now:        time.Now,
+	}
+	d.ring.Store(ring)
+	return d, nil
+}
+
+// SetKeyring rotates the keys, draws in flight finish with the key they looked up
+func (d *Drawer) SetKeyring(ring *keyring.Keyring) {
+	d.ring.Store(ring)
+}
+
+// Keyring returns the keyring in use
+func (d *Drawer) Keyring() *keyring.Keyring {
+	return d.ring.Load()
+}
+
+// Key returns the key with the id and/or epoch, or the active key when both are
+// empty, to replay or verify a draw. New draws use DrawKey.
+func (d *Drawer) Key(id string, epoch int64) (keyring.Key, error) {
+	return d.ring.Load().Lookup(id, epoch, d.now())
+}
+
+// DrawKey returns the key of a draw, the active key when both the id and epoch
+// are empty. An earlier key only replays a draw, when replay reports that the
+// request replays one drawn with it; otherwise it fails with keyring.ErrKeyRetired.
+func (d *Drawer) DrawKey(id string, epoch int64, replay func(earlier keyring.Key) (bool, error)) (keyring.Key, error) {
+	ring, now := d.ring.Load(), d.now()
+	key, err := ring.Current(id, epoch, now)
+	if !errors.Is(err, keyring.ErrKeyRetired) {
+		return key, err
+	}
+
+	earlier, errEarlier := ring.Lookup(id, epoch, now)
+	if errEarlier != nil {
+		return keyring.Key{}, errEarlier
+	}
+	replayed, errReplay := replay(earlier)
+	if errReplay != nil {
+		return keyring.Key{}, errReplay
+	} else if !replayed {
+		return keyring.Key{}, err
+	}
+	return earlier, nil
+}
+
+// Fingerprint returns the seed fingerprint of a key id, or of the active key when the id is unknown
+func (d *Drawer) Fingerprint(id string) string {
+	k, err := d.ring.Load().Lookup(id, 0, d.now())
+	if err != nil {
+		k, err = d.ring.Load().Active(d.now())
+		if err != nil {
+			return ""
+		}
+	}
+	return k.Fingerprint()
+}
+
+// VRF reports whether outcomes come with a proof
+func (d *Drawer) VRF() bool {
+	return d.algorithm == AlgorithmVRF
+}
+
+// Derived reports whether every namespace is drawn with its own derived seed
+func (d *Drawer) Derived() bool {
+	return d.derivation == DerivationHKDF
+}
+
+// Version returns the algorithm version recorded with the outcomes in the audit log
+func (d *Drawer) Version() string {
+	switch {
+	case d.VRF() && d.Derived():
+		return random.AlgorithmVersionVRFHKDF
+	case d.VRF():
+		return random.AlgorithmVersionVRF
+	case d.Derived():
+		return random.AlgorithmVersionHKDF
+	}
+	return random.AlgorithmVersion
+}
+
+// seed returns the seed the namespace is drawn with under the key
+func (d *Drawer) seed(key keyring.Key, namespace string) (string, error) {
+	if d.Derived() {
+		return random.DeriveSeed(key.SeedHex, random.PurposeDeterministic, namespace)
+	}
+	return key.SeedHex, nil
+}
+
+// PublicKey returns the ECVRF public key that proves the draws of the namespace under the key
+func (d *Drawer) PublicKey(key keyring.Key, namespace string) ([]byte, error) {
+	if !d.Derived() {
+		return key.VRFPublicKey(), nil
+	}
+
+	seed, err := d.seed(key, namespace)
+	if err != nil {
+		return nil, err
+	}
+	return random.VRFPublicKey(seed)
+}
+
+// Validate checks the input of a draw without drawing it
+func (d *Drawer) Validate(key keyring.Key, sequence int64, probabilities []float64) error {
+	_, err := random.DeterministicRandom(key.SeedHex, sequence, probabilities)
+	return err
+}
+
+// Draw returns the outcome of the sequence of the namespace under the key and, with the vrf algorithm, its proof
+func (d *Drawer) Draw(key keyring.Key, namespace string, sequence int64, probabilities []float64) (int64, []byte, error) {
+	seed, err := d.seed(key, namespace)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	if d.VRF() {
+		return random.VRFDeterministicRandom(seed, sequence, probabilities)
+	}
+
+	number, err := random.DeterministicRandom(seed, sequence, probabilities)
+	return number, nil, err
+}
+
+// Verify checks a proof of an outcome. When publicKey is empty the public key
+// of the namespace under the key with keyID is used, or under the active key when keyID is empty.
+func (d *Drawer) Verify(publicKey []byte, keyID string, namespace string, sequence int64, probabilities []float64, number int64, proof []byte) error {
+	if len(publicKey) == 0 {
+		key, err := d.Key(keyID, 0)
+		if err != nil {
+			return err
+		}
+		publicKey, err = d.PublicKey(key, namespace)
+		if err != nil {
+			return err
+		}
+	}
+	return random.VerifyVRFDeterministicRandom(publicKey, sequence, probabilities, number, proof)
+}
@@ -0,0 +1,188 @@
+package deterministic
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/fasttrack-solutions/random"
+	"github.com/fasttrack-solutions/random/internal/keyring"
+	"github.com/stretchr/testify/assert"
+)
+
+// Seeds drawn from crypto/rand, the example seed of random_test.go fails validation
+const (
+	testSeed  = "0f6aa358754a2b1fac2206849282b6c68dd6e086f635df2796acea52ae920ddc"
+	testSeed2 = "c8bfdd8e79e4a001038ac5c0370f1eb7cd222f97333c9657e17483233729f282"
+)
+
+func Test_Drawer(t *testing.T) {
+	probabilities := []float64{0.1, 0.2, 0.7}
+	ring, err := keyring.Single(testSeed)
+	assert.Nil(t, err)
+
+	sha, err := New(ring, AlgorithmSHA256, DerivationNone)
+	assert.Nil(t, err)
+	assert.False(t, sha.VRF())
+	assert.Equal(t, random.AlgorithmVersion, sha.Version())
+
+	key, err := sha.Key("", 0)
+	assert.Nil(t, err)
+	number, proof, err := sha.Draw(key, "", 3, probabilities)
+	assert.Nil(t, err)
+	assert.Nil(t, proof)
+	expected, err := random.DeterministicRandom(testSeed, 3, probabilities)
+	assert.Nil(t, err)
+	assert.Equal(t, expected, number)
+
+	vrf, err := New(ring, AlgorithmVRF, DerivationNone)
+	assert.Nil(t, err)
+	assert.True(t, vrf.VRF())
+	assert.Equal(t, random.AlgorithmVersionVRF, vrf.Version())
+
+	number, proof, err = vrf.Draw(key, "", 3, probabilities)
+	assert.Nil(t, err)
+	assert.Len(t, proof, 80)
+	assert.Nil(t, vrf.Verify(nil, "", "", 3, probabilities, number, proof))
+	assert.Nil(t, sha.Verify(key.VRFPublicKey(), "", "", 3, probabilities, number, proof))
+	assert.NotNil(t, vrf.Verify(nil, key.ID, "", 4, probabilities, number, proof))
+
+	_, err = New(ring, "md5", DerivationNone)
+	assert.EqualError(t, err, `invalid deterministic algorithm "md5"; valid algorithms are "sha256" and "vrf"`)
+}
+
+func Test_Drawer_Rotation(t *testing.T) {
+	probabilities := []float64{0.5, 0.5}
+	start := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	first, err := keyring.New([]keyring.Key{{ID: "first", Epoch: 1, ActiveFrom: start, SeedHex: testSeed}})
+	assert.Nil(t, err)
+
+	d, err := New(first, AlgorithmVRF, DerivationNone)
+	assert.Nil(t, err)
+	d.now = func() time.Time { return start.Add(time.Hour) }
+
+	key, err := d.Key("", 0)
+	assert.Nil(t, err)
+	assert.Equal(t, "first", key.ID)
+	number, proof, err := d.Draw(key, "", 1, probabilities)
+	assert.Nil(t, err)
+
+	rotated, err := keyring.New([]keyring.Key{
+		{ID: "first", Epoch: 1, ActiveFrom: start, SeedHex: testSeed},
+		{ID: "second", Epoch: 2, ActiveFrom: start.Add(time.Minute), SeedHex: testSeed2},
+	})
+	assert.Nil(t, err)
+	d.SetKeyring(rotated)
+
+	key, err = d.Key("", 0)
+	assert.Nil(t, err)
+	assert.Equal(t, "second", key.ID)
+
+	// Draws of the first epoch can still be replayed and verified
+	key, err = d.Key("", 1)
+	assert.Nil(t, err)
+	replayed, replayedProof, err := d.Draw(key, "", 1, probabilities)
+	assert.Nil(t, err)
+	assert.Equal(t, number, replayed)
+	assert.Equal(t, proof, replayedProof)
+	assert.Nil(t, d.Verify(nil, "first", "", 1, probabilities, number, proof))
+	assert.NotNil(t, d.Verify(nil, "second", "", 1, probabilities, number, proof))
+	assert.Equal(t, key.Fingerprint(), d.Fingerprint("first"))
+}
+
+func Test_Drawer_DrawKey(t *testing.T) {
+	start := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	ring, err := keyring.New([]keyring.Key{
+		{ID: "first", Epoch: 1, ActiveFrom: start, SeedHex: testSeed},
+		{ID: "second", Epoch: 2, ActiveFrom: start.Add(time.Hour), SeedHex: testSeed2},
+	})
+	assert.Nil(t, err)
+	d, err := New(ring, AlgorithmSHA256, DerivationNone)
+	assert.Nil(t, err)
+	d.now = func() time.Time { return start.Add(2 * time.Hour) }
+
+	var asked []string
+	replay := func(replayed bool, err error) func(keyring.Key) (bool, error) {
+		return func(earlier keyring.Key) (bool, error) {
+			asked = append(asked, earlier.ID)
+			return replayed, err
+		}
+	}
+
+	// New draws use the active key, named or not, without asking for a replay
+	for _, selector := range []struct {
+		id    string
+		epoch int64
+	}{{"", 0}, {"second", 0}, {"", 2}, {"second", 2}} {
+		key, errKey := d.DrawKey(selector.id, selector.epoch, replay(false, nil))
+		assert.Nil(t, errKey)
+		assert.Equal(t, "second", key.ID)
+	}
+	assert.Empty(t, asked)
+
+	// An earlier key only replays
+	_, err = d.DrawKey("first", 0, replay(false, nil))
+	assert.ErrorIs(t, err, keyring.ErrKeyRetired)
+	_, err = d.DrawKey("", 1, replay(false, nil))
+	assert.ErrorIs(t, err, keyring.ErrKeyRetired)
+	key, err := d.DrawKey("", 1, replay(true, nil))
+	assert.Nil(t, err)
+	assert.Equal(t, "first", key.ID)
+	assert.Equal(t, []string{"first", "first", "first"}, asked)
+
+	errLookup := errors.New("store unavailable")
+	_, err = d.DrawKey("first", 0, replay(true, errLookup))
+	assert.ErrorIs(t, err, errLookup)
+
+	// Unknown keys and keys not active yet are refused before any replay
+	asked = nil
+	_, err = d.DrawKey("third", 0, replay(true, nil))
+	assert.ErrorIs(t, err, keyring.ErrUnknownKey)
+	d.now = func() time.Time { return start.Add(time.Minute) }
+	_, err = d.DrawKey("second", 0, replay(true, nil))
+	assert.ErrorIs(t, err, keyring.ErrKeyNotActive)
+	assert.Empty(t, asked)
+}
+
+func Test_Drawer_Derivation(t *testing.T) {
+	probabilities := []float64{0.25, 0.25, 0.25, 0.25}
+	ring, err := keyring.Single(testSeed)
+	assert.Nil(t, err)
+	key, err := ring.Active(time.Now())
+	assert.Nil(t, err)
+
+	d, err := New(ring, AlgorithmSHA256, DerivationHKDF)
+	assert.Nil(t, err)
+	assert.Equal(t, random.AlgorithmVersionHKDF, d.Version())
+
+	derived, err := random.DeriveSeed(testSeed, random.PurposeDeterministic, "campaign-a")
+	assert.Nil(t, err)
+	for sequence := int64(0); sequence < 10; sequence++ {
+		number, _, errDraw := d.Draw(key, "campaign-a", sequence, probabilities)
+		assert.Nil(t, errDraw)
+		expected, errExpected := random.DeterministicRandom(derived, sequence, probabilities)
+		assert.Nil(t, errExpected)
+		assert.Equal(t, expected, number)
+	}
+
+	// Every namespace is proven by its own public key
+	vrf, err := New(ring, AlgorithmVRF, DerivationHKDF)
+	assert.Nil(t, err)
+	assert.Equal(t, random.AlgorithmVersionVRFHKDF, vrf.Version())
+
+	number, proof, err := vrf.Draw(key, "campaign-a", 5, probabilities)
+	assert.Nil(t, err)
+	publicKeyA, err := vrf.PublicKey(key, "campaign-a")
+	assert.Nil(t, err)
+	publicKeyB, err := vrf.PublicKey(key, "campaign-b")
+	assert.Nil(t, err)
+	assert.NotEqual(t, publicKeyA, publicKeyB)
+	assert.NotEqual(t, key.VRFPublicKey(), publicKeyA)
+
+	assert.Nil(t, vrf.Verify(nil, "", "campaign-a", 5, probabilities, number, proof))
+	assert.Nil(t, vrf.Verify(publicKeyA, "", "", 5, probabilities, number, proof))
+	assert.NotNil(t, vrf.Verify(nil, "", "campaign-b", 5, probabilities, number, proof))
+
+	_, err = New(ring, AlgorithmSHA256, "pbkdf2")
+	assert.EqualError(t, err, `invalid seed derivation "pbkdf2"; valid derivations are "none" and "hkdf"`)
+}
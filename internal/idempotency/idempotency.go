@@ -0,0 +1,124 @@
+package idempotency
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+	"unicode"
+
+	"github.com/fasttrack-solutions/random/internal/storage"
+)
+
+// keyPrefix is the storage prefix of stored results, followed by "<scope>/<key>"
+const keyPrefix = "idempotency/"
+
+// MaxKeyLength is the longest accepted idempotency key
+const MaxKeyLength = 255
+
+// ErrKeyReused is returned when a key is sent again with different request parameters
+var ErrKeyReused = errors.New("idempotency key was already used with different parameters")
+
+// Cache stores the first result of a request under its idempotency key, so
+// retries of the same request receive the identical result.
+type Cache struct {
+	store storage.Store
+	ttl   time.Duration
+}
+
+// New creates a cache that keeps results in store for ttl
+func New(store storage.Store, ttl time.Duration) (*Cache, error) {
+	if ttl <= 0 {
+		return nil, errors.New("idempotency ttl must be larger than 0")
+	}
+
+	return &Cache{
+		store: store,
+		ttl:   ttl,
+	}, nil
+}
+
+// ValidateKey checks that a key is 1-255 printable ASCII characters
+func ValidateKey(key string) error {
+	if len(key) == 0 {
+		return errors.New("idempotency key must not be empty")
+	} else if len(key) > MaxKeyLength {
+		return fmt.Errorf("idempotency key must be at most %v characters", MaxKeyLength)
+	}
+	for _, r := range key {
+		if r > unicode.MaxASCII || !unicode.IsPrint(r) {
+			return errors.New("idempotency key must only contain printable ASCII characters")
+		}
+	}
+	return nil
+}
+
+type entry[T any] struct {
+	Params [sha256.Size]byte `json:"params"`
+	Result T                 `json:"result"`
+}
+
+// Do returns the result stored for key within scope (i.e. the RPC name), or calls fn
+// and stores its result. The returned bool is true when the stored result is returned.
+// Without a key fn is called and nothing is stored. params identify the request,
+// reusing a key with other params fails with ErrKeyReused.
+func Do[T any](ctx context.Context, c *Cache, scope string, key string, params any, fn func() (T, error)) (T, bool, error) {
+	var result T
+	if len(key) == 0 {
+		result, err := fn()
+		return result, false, err
+	}
+
+	err := ValidateKey(key)
+	if err != nil {
+		return result, false, err
+	}
+
+	p, err := json.Marshal(params)
+	if err != nil {
+		return result, false, err
+	}
+	fingerprint := sha256.Sum256(p)
+
+	replayed := false
+	err = c.store.Update(ctx, func(tx storage.Tx) error {
+		replayed = false
+		storageKey := keyPrefix + scope + "/" + key
+
+		v, errGet := tx.Get(storageKey)
+		if errGet == nil {
+			var stored entry[T]
+			errUnmarshal := json.Unmarshal(v, &stored)
+			if errUnmarshal != nil {
+				return errUnmarshal
+			} else if stored.Params != fingerprint {
+				return ErrKeyReused
+			}
+			result = stored.Result
+			replayed = true
+			return nil
+		} else if !errors.Is(errGet, storage.ErrNotFound) {
+			return errGet
+		}
+
+		var errFn error
+		result, errFn = fn()
+		if errFn != nil {
+			return errFn
+		}
+
+		v, errMarshal := json.Marshal(entry[T]{Params: fingerprint, Result: result})
+		if errMarshal != nil {
+			return errMarshal
+		}
+		return tx.Put(storageKey, v, c.ttl)
+	})
+	if err != nil {
+		var zero T
+		return zero, false, err
+	}
+
+	return result, replayed, nil
+}
@@ -0,0 +1,63 @@
+package idempotency
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/fasttrack-solutions/random/internal/storage"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Do(t *testing.T) {
+	ctx := context.Background()
+	cache, err := New(storage.NewMemoryStore(), time.Hour)
+	assert.Nil(t, err)
+
+	calls := 0
+	next := func() (int64, error) {
+		calls++
+		return int64(calls), nil
+	}
+
+	// The first result is returned for retries
+	number, replayed, err := Do(ctx, cache, "GetRandomInt64", "spin-1", []int32{0, 10}, next)
+	assert.Nil(t, err)
+	assert.False(t, replayed)
+	assert.Equal(t, int64(1), number)
+
+	number, replayed, err = Do(ctx, cache, "GetRandomInt64", "spin-1", []int32{0, 10}, next)
+	assert.Nil(t, err)
+	assert.True(t, replayed)
+	assert.Equal(t, int64(1), number)
+
+	// Keys are independent per scope
+	number, replayed, err = Do(ctx, cache, "GetRandomFloat64", "spin-1", nil, next)
+	assert.Nil(t, err)
+	assert.False(t, replayed)
+	assert.Equal(t, int64(2), number)
+
+	// Other parameters with the same key are refused
+	_, _, err = Do(ctx, cache, "GetRandomInt64", "spin-1", []int32{0, 20}, next)
+	assert.ErrorIs(t, err, ErrKeyReused)
+
+	// Without a key nothing is stored
+	for i := 0; i < 2; i++ {
+		_, replayed, err = Do(ctx, cache, "GetRandomInt64", "", []int32{0, 10}, next)
+		assert.Nil(t, err)
+		assert.False(t, replayed)
+	}
+	assert.Equal(t, 4, calls)
+
+	_, err = New(storage.NewMemoryStore(), 0)
+	assert.NotNil(t, err)
+}
+
+func Test_ValidateKey(t *testing.T) {
+	assert.Nil(t, ValidateKey("3f2c9a1e-8b7d-4c6a-9e5f-1a2b3c4d5e6f"))
+	assert.NotNil(t, ValidateKey(""))
+	assert.NotNil(t, ValidateKey(strings.Repeat("k", MaxKeyLength+1)))
+	assert.NotNil(t, ValidateKey("spin\n1"))
+	assert.NotNil(t, ValidateKey("spin-ü"))
+}
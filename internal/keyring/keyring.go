@@ -0,0 +1,201 @@
+// Package keyring holds the seeds of deterministic draws. Every key has an id
+// and an epoch and becomes active at its activation time, so seeds can be
+// rotated while draws of earlier epochs can still be replayed.
+package keyring
+
+import (
+	"cmp"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"time"
+
+	"github.com/fasttrack-solutions/random"
+)
+
+var (
+	// ErrUnknownKey is returned for a key id or epoch that is not in the keyring
+	ErrUnknownKey = errors.New("unknown key")
+	// ErrKeyNotActive is returned for a key whose activation time has not been reached
+	ErrKeyNotActive = errors.New("key is not active yet")
+	// ErrKeyRetired is returned when a new draw selects a key other than the active one
+	ErrKeyRetired = errors.New("key is not the active key, only replays of earlier draws may use it")
+)
+
+// Key is a seed of the keyring
+type Key struct {
+	ID string `json:"id"`
+	// Epoch numbers the keys from 1 in order of activation
+	Epoch      int64     `json:"epoch"`
+	ActiveFrom time.Time `json:"activeFrom"`
+	SeedHex    string    `json:"seedHex"`
+
+	fingerprint  string
+	vrfPublicKey []byte
+}
+
+// Fingerprint returns the seed fingerprint of the key
+func (k Key) Fingerprint() string {
+	return k.fingerprint
+}
+
+// VRFPublicKey returns the ECVRF public key of the seed
+func (k Key) VRFPublicKey() []byte {
+	return k.vrfPublicKey
+}
+
+// file is the JSON format of a keyring file
+type file struct {
+	Keys []Key `json:"keys"`
+}
+
+// Keyring is an immutable set of keys, rotation replaces the whole keyring
+type Keyring struct {
+	keys []Key
+}
+
+// New validates the keys and creates a keyring of them.
+// Ids and epochs must be unique and a later epoch must become active after an earlier one.
+func New(keys []Key) (*Keyring, error) {
+	if len(keys) == 0 {
+		return nil, errors.New("keyring must hold at least one key")
+	}
+
+	sorted := slices.Clone(keys)
+	slices.SortStableFunc(sorted, func(a, b Key) int {
+		return cmp.Compare(a.Epoch, b.Epoch)
+	})
+
+	ids := map[string]bool{}
+	for i := range sorted {
+		k := &sorted[i]
+		if len(k.ID) == 0 {
+			return nil, fmt.Errorf("key of epoch %v has no id", k.Epoch)
+		} else if ids[k.ID] {
+			return nil, fmt.Errorf("duplicate key id %q", k.ID)
+		} else if k.Epoch < 1 {
+			return nil, fmt.Errorf("key %q: epoch must be at least 1", k.ID)
+		} else if i > 0 && k.Epoch == sorted[i-1].Epoch {
+			return nil, fmt.Errorf("key %q: duplicate epoch %v", k.ID, k.Epoch)
+		} else if i > 0 && !k.ActiveFrom.After(sorted[i-1].ActiveFrom) {
+			return nil, fmt.Errorf("key %q: epoch %v must become active after epoch %v", k.ID, k.Epoch, sorted[i-1].Epoch)
+		}
+		ids[k.ID] = true
+
+		err := random.ValidateSeed(k.SeedHex)
+		if err != nil {
+			return nil, fmt.Errorf("key %q: %w", k.ID, err)
+		}
+
+		k.fingerprint, err = random.SeedFingerprint(k.SeedHex)
+		if err != nil {
+			return nil, fmt.Errorf("key %q: %w", k.ID, err)
+		}
+		k.vrfPublicKey, err = random.VRFPublicKey(k.SeedHex)
+		if err != nil {
+			return nil, fmt.Errorf("key %q: %w", k.ID, err)
+		}
+	}
+
+	return &Keyring{
+		keys: sorted,
+	}, nil
+}
+
+// Single creates a keyring of one seed, active since forever, whose id is the seed fingerprint
+func Single(seedHex string) (*Keyring, error) {
+	err := random.ValidateSeed(seedHex)
+	if err != nil {
+		return nil, err
+	}
+
+	fingerprint, err := random.SeedFingerprint(seedHex)
+	if err != nil {
+		return nil, err
+	}
+
+	return New([]Key{{ID: fingerprint, Epoch: 1, SeedHex: seedHex}})
+}
+
+// Load reads a keyring file: {"keys": [{"id", "epoch", "activeFrom", "seedHex"}, ...]}
+func Load(path string) (*Keyring, error) {
+	b, err := os.ReadFile(filepath.Clean(path))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read keyring: %w", err)
+	}
+
+	var f file
+	err = json.Unmarshal(b, &f)
+	if err != nil {
+		return nil, fmt.Errorf("invalid keyring %s: %w", path, err)
+	}
+	return New(f.Keys)
+}
+
+// Keys returns the keys in order of epoch
+func (r *Keyring) Keys() []Key {
+	return slices.Clone(r.keys)
+}
+
+// Active returns the key of the latest epoch that is active at now
+func (r *Keyring) Active(now time.Time) (Key, error) {
+	for i := len(r.keys) - 1; i >= 0; i-- {
+		if !r.keys[i].ActiveFrom.After(now) {
+			return r.keys[i], nil
+		}
+	}
+	return Key{}, fmt.Errorf("%w: no key is active at %s", ErrKeyNotActive, now.Format(time.RFC3339))
+}
+
+// Lookup returns the key with the id and/or epoch, or the active key when both are empty
+func (r *Keyring) Lookup(id string, epoch int64, now time.Time) (Key, error) {
+	if len(id) == 0 && epoch == 0 {
+		return r.Active(now)
+	}
+
+	for _, k := range r.keys {
+		if (len(id) > 0 && k.ID != id) || (epoch != 0 && k.Epoch != epoch) {
+			continue
+		} else if k.ActiveFrom.After(now) {
+			return Key{}, fmt.Errorf("%w: key %q becomes active at %s", ErrKeyNotActive, k.ID, k.ActiveFrom.Format(time.RFC3339))
+		}
+		return k, nil
+	}
+
+	if len(id) > 0 && epoch != 0 {
+		return Key{}, fmt.Errorf("%w: no key %q of epoch %v", ErrUnknownKey, id, epoch)
+	} else if len(id) > 0 {
+		return Key{}, fmt.Errorf("%w: %q", ErrUnknownKey, id)
+	}
+	return Key{}, fmt.Errorf("%w: epoch %v", ErrUnknownKey, epoch)
+}
+
+// Current returns the key with the id and/or epoch for a new draw, or the active
+// key when both are empty. Only the active key draws: the seed of an ended epoch
+// may have been revealed, anyone could compute the outcomes it draws.
+func (r *Keyring) Current(id string, epoch int64, now time.Time) (Key, error) {
+	k, err := r.Lookup(id, epoch, now)
+	if err != nil {
+		return Key{}, err
+	}
+	active, err := r.Active(now)
+	if err != nil {
+		return Key{}, err
+	} else if k.Epoch != active.Epoch {
+		return Key{}, fmt.Errorf("%w: key %q of epoch %v, the active key is %q of epoch %v", ErrKeyRetired, k.ID, k.Epoch, active.ID, active.Epoch)
+	}
+	return k, nil
+}
+
+// ByFingerprint returns the key of a seed fingerprint
+func (r *Keyring) ByFingerprint(fingerprint string) (Key, bool) {
+	for _, k := range r.keys {
+		if k.fingerprint == fingerprint {
+			return k, true
+		}
+	}
+	return Key{}, false
+}
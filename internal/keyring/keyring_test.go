@@ -0,0 +1,149 @@
+package keyring
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/fasttrack-solutions/random"
+	"github.com/stretchr/testify/assert"
+)
+
+// Seeds drawn from crypto/rand, the seeds of other tests fail validation
+const (
+	seedA = "0f6aa358754a2b1fac2206849282b6c68dd6e086f635df2796acea52ae920ddc"
+	seedB = "38cc89e51e52d12e44fc73d51efb003a51770e1df62228162bfbaf99fd5406a0"
+	seedC = "4db0a9ca611ab3aad0139ce633bfbbe10f576969254a48c460deec58fc6c7d10"
+)
+
+var start = time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+
+func testKeys() []Key {
+	return []Key{
+		{ID: "2025-02", Epoch: 2, ActiveFrom: start.AddDate(0, 1, 0), SeedHex: seedB},
+		{ID: "2025-01", Epoch: 1, ActiveFrom: start, SeedHex: seedA},
+	}
+}
+
+func Test_Lookup(t *testing.T) {
+	ring, err := New(testKeys())
+	assert.Nil(t, err)
+	assert.Equal(t, "2025-01", ring.Keys()[0].ID)
+
+	_, err = ring.Active(start.Add(-time.Second))
+	assert.ErrorIs(t, err, ErrKeyNotActive)
+
+	k, err := ring.Active(start.AddDate(0, 0, 15))
+	assert.Nil(t, err)
+	assert.Equal(t, "2025-01", k.ID)
+
+	k, err = ring.Lookup("", 0, start.AddDate(0, 2, 0))
+	assert.Nil(t, err)
+	assert.Equal(t, "2025-02", k.ID)
+	assert.Len(t, k.Fingerprint(), 16)
+	assert.Len(t, k.VRFPublicKey(), 32)
+
+	k, err = ring.Lookup("", 1, start.AddDate(0, 2, 0))
+	assert.Nil(t, err)
+	assert.Equal(t, "2025-01", k.ID)
+
+	k, err = ring.Lookup("2025-01", 1, start.AddDate(0, 2, 0))
+	assert.Nil(t, err)
+	assert.Equal(t, int64(1), k.Epoch)
+
+	_, err = ring.Lookup("2025-01", 2, start.AddDate(0, 2, 0))
+	assert.ErrorIs(t, err, ErrUnknownKey)
+
+	_, err = ring.Lookup("2024-12", 0, start.AddDate(0, 2, 0))
+	assert.ErrorIs(t, err, ErrUnknownKey)
+
+	// Keys cannot be used before they become active
+	_, err = ring.Lookup("2025-02", 0, start)
+	assert.ErrorIs(t, err, ErrKeyNotActive)
+
+	// New draws only use the active key
+	current, err := ring.Current("", 0, start.AddDate(0, 2, 0))
+	assert.Nil(t, err)
+	assert.Equal(t, "2025-02", current.ID)
+	_, err = ring.Current("2025-02", 2, start.AddDate(0, 2, 0))
+	assert.Nil(t, err)
+	_, err = ring.Current("2025-01", 0, start.AddDate(0, 2, 0))
+	assert.ErrorIs(t, err, ErrKeyRetired)
+	_, err = ring.Current("", 1, start.AddDate(0, 2, 0))
+	assert.ErrorIs(t, err, ErrKeyRetired)
+	_, err = ring.Current("2025-02", 0, start)
+	assert.ErrorIs(t, err, ErrKeyNotActive)
+
+	byFingerprint, ok := ring.ByFingerprint(k.Fingerprint())
+	assert.True(t, ok)
+	assert.Equal(t, "2025-01", byFingerprint.ID)
+}
+
+func Test_New_Invalid(t *testing.T) {
+	testCases := []struct {
+		change func(keys []Key)
+		err    string
+	}{
+		{func(keys []Key) { keys[0].ID = "2025-01" }, `duplicate key id "2025-01"`},
+		{func(keys []Key) { keys[0].Epoch = 1 }, `key "2025-01": duplicate epoch 1`},
+		{func(keys []Key) { keys[0].Epoch = 0 }, `key "2025-02": epoch must be at least 1`},
+		{func(keys []Key) { keys[0].ActiveFrom = start }, `key "2025-02": epoch 2 must become active after epoch 1`},
+		{func(keys []Key) { keys[0].SeedHex = "abcd" }, `key "2025-02": seed must be 64 hex characters`},
+		{func(keys []Key) { keys[1].ID = "" }, `key of epoch 1 has no id`},
+	}
+
+	for _, tc := range testCases {
+		keys := testKeys()
+		tc.change(keys)
+		_, err := New(keys)
+		assert.EqualError(t, err, tc.err)
+	}
+
+	_, err := New(nil)
+	assert.NotNil(t, err)
+
+	_, err = Single(strings.Repeat("0", 64))
+	assert.ErrorIs(t, err, random.ErrWeakSeed)
+
+	_, err = Single(strings.Repeat("ab", 32))
+	assert.ErrorIs(t, err, random.ErrWeakSeed)
+}
+
+func Test_Watch(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "keyring.json")
+	write := func(content string) {
+		assert.Nil(t, os.WriteFile(path, []byte(content), 0600))
+	}
+	write(`{"keys":[{"id":"a","epoch":1,"activeFrom":"2025-01-01T00:00:00Z","seedHex":"` + seedA + `"}]}`)
+
+	ring, err := Load(path)
+	assert.Nil(t, err)
+	assert.Len(t, ring.Keys(), 1)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	reloaded := make(chan *Keyring, 1)
+	go Watch(ctx, Source{File: path}, 10*time.Millisecond, func(r *Keyring) { reloaded <- r })
+
+	// Invalid content keeps the current keys
+	time.Sleep(30 * time.Millisecond)
+	write(`{"keys":[]}`)
+	time.Sleep(30 * time.Millisecond)
+	select {
+	case <-reloaded:
+		t.Fatal("invalid keyring was loaded")
+	default:
+	}
+
+	write(`{"keys":[{"id":"a","epoch":1,"activeFrom":"2025-01-01T00:00:00Z","seedHex":"` + seedA + `"},{"id":"b","epoch":2,"activeFrom":"2025-02-01T00:00:00Z","seedHex":"` + seedB + `"}]}`)
+	select {
+	case r := <-reloaded:
+		assert.Len(t, r.Keys(), 2)
+	case <-time.After(time.Second):
+		t.Fatal(errors.New("keyring was not reloaded"))
+	}
+}
@@ -0,0 +1,164 @@
+package keyring
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"golang.org/x/crypto/scrypt"
+)
+
+const (
+	keystoreVersion = 1
+	keystoreKDF     = "scrypt"
+	keystoreCipher  = "aes-256-gcm"
+	// maxScryptN bounds the work and memory a keystore can ask for, 1<<20 takes 1 GiB with r 8
+	maxScryptN = 1 << 20
+)
+
+// ErrWrongPassphrase is returned when a keystore can not be decrypted with the passphrase
+var ErrWrongPassphrase = errors.New("wrong passphrase or corrupted keystore")
+
+// KeystoreParams are the scrypt cost parameters of a keystore
+type KeystoreParams struct {
+	N int `json:"n"`
+	R int `json:"r"`
+	P int `json:"p"`
+}
+
+// DefaultKeystoreParams takes about 100ms and 32 MiB to derive the key
+var DefaultKeystoreParams = KeystoreParams{N: 1 << 15, R: 8, P: 1}
+
+// keystore is the JSON format of a keystore file. Everything but the ciphertext
+// is authenticated as additional data, so the parameters can not be swapped.
+type keystore struct {
+	Version    int            `json:"version"`
+	KDF        string         `json:"kdf"`
+	KDFParams  KeystoreParams `json:"kdfParams"`
+	Salt       []byte         `json:"salt"`
+	Cipher     string         `json:"cipher"`
+	Nonce      []byte         `json:"nonce"`
+	Ciphertext []byte         `json:"ciphertext,omitempty"`
+}
+
+// EncryptKeystore encrypts the keys with a key derived from the passphrase
+func EncryptKeystore(keys []Key, passphrase []byte, params KeystoreParams) ([]byte, error) {
+	if len(passphrase) == 0 {
+		return nil, errors.New("passphrase must not be empty")
+	}
+
+	_, err := New(keys)
+	if err != nil {
+		return nil, err
+	}
+
+	plaintext, err := json.Marshal(file{Keys: keys})
+	if err != nil {
+		return nil, err
+	}
+
+	ks := keystore{
+		Version:   keystoreVersion,
+		KDF:       keystoreKDF,
+		KDFParams: params,
+		Salt:      make([]byte, 32),
+		Cipher:    keystoreCipher,
+	}
+	_, err = rand.Read(ks.Salt)
+	if err != nil {
+		return nil, err
+	}
+
+	aead, err := ks.aead(passphrase)
+	if err != nil {
+		return nil, err
+	}
+
+	ks.Nonce = make([]byte, aead.NonceSize())
+	_, err = rand.Read(ks.Nonce)
+	if err != nil {
+		return nil, err
+	}
+
+	header, err := json.Marshal(ks)
+	if err != nil {
+		return nil, err
+	}
+	ks.Ciphertext = aead.Seal(nil, ks.Nonce, plaintext, header)
+
+	return json.MarshalIndent(ks, "", "  ")
+}
+
+// DecryptKeystore decrypts the keys of a keystore
+func DecryptKeystore(data []byte, passphrase []byte) ([]Key, error) {
+	var ks keystore
+	err := json.Unmarshal(data, &ks)
+	if err != nil {
+		return nil, fmt.Errorf("invalid keystore: %w", err)
+	} else if ks.Version != keystoreVersion || ks.KDF != keystoreKDF || ks.Cipher != keystoreCipher {
+		return nil, fmt.Errorf("unsupported keystore version %v with %s and %s", ks.Version, ks.KDF, ks.Cipher)
+	}
+
+	aead, err := ks.aead(passphrase)
+	if err != nil {
+		return nil, err
+	} else if len(ks.Nonce) != aead.NonceSize() {
+		return nil, errors.New("invalid keystore nonce")
+	}
+
+	ciphertext := ks.Ciphertext
+	ks.Ciphertext = nil
+	header, err := json.Marshal(ks)
+	if err != nil {
+		return nil, err
+	}
+
+	plaintext, err := aead.Open(nil, ks.Nonce, ciphertext, header)
+	if err != nil {
+		return nil, ErrWrongPassphrase
+	}
+
+	var f file
+	err = json.Unmarshal(plaintext, &f)
+	if err != nil {
+		return nil, fmt.Errorf("invalid keystore content: %w", err)
+	}
+	return f.Keys, nil
+}
+
+// LoadKeystore reads and decrypts a keystore file
+func LoadKeystore(path string, passphrase []byte) (*Keyring, error) {
+	b, err := os.ReadFile(filepath.Clean(path))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read keystore: %w", err)
+	}
+
+	keys, err := DecryptKeystore(b, passphrase)
+	if err != nil {
+		return nil, fmt.Errorf("keystore %s: %w", path, err)
+	}
+	return New(keys)
+}
+
+func (ks keystore) aead(passphrase []byte) (cipher.AEAD, error) {
+	p := ks.KDFParams
+	if p.N > maxScryptN || p.R < 1 || p.R > 32 || p.P < 1 || p.P > 16 {
+		return nil, fmt.Errorf("scrypt parameters out of range: n %v, r %v, p %v", p.N, p.R, p.P)
+	}
+
+	key, err := scrypt.Key(passphrase, ks.Salt, p.N, p.R, p.P, 32)
+	if err != nil {
+		return nil, err
+	}
+
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
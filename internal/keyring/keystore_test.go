@@ -0,0 +1,56 @@
+package keyring
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// testParams keeps the tests fast, real keystores use DefaultKeystoreParams
+var testParams = KeystoreParams{N: 1 << 10, R: 8, P: 1}
+
+func Test_Keystore(t *testing.T) {
+	data, err := EncryptKeystore(testKeys(), []byte("correct horse"), testParams)
+	assert.Nil(t, err)
+	assert.False(t, bytes.Contains(data, []byte(testKeys()[0].SeedHex)))
+
+	keys, err := DecryptKeystore(data, []byte("correct horse"))
+	assert.Nil(t, err)
+	assert.Equal(t, testKeys(), keys)
+
+	_, err = DecryptKeystore(data, []byte("wrong horse"))
+	assert.ErrorIs(t, err, ErrWrongPassphrase)
+
+	// The parameters are authenticated
+	var ks keystore
+	assert.Nil(t, json.Unmarshal(data, &ks))
+	ks.KDFParams.N = 1 << 11
+	tampered, err := json.Marshal(ks)
+	assert.Nil(t, err)
+	_, err = DecryptKeystore(tampered, []byte("correct horse"))
+	assert.ErrorIs(t, err, ErrWrongPassphrase)
+
+	ks.KDFParams.N = 1 << 30
+	tampered, err = json.Marshal(ks)
+	assert.Nil(t, err)
+	_, err = DecryptKeystore(tampered, []byte("correct horse"))
+	assert.EqualError(t, err, "scrypt parameters out of range: n 1073741824, r 8, p 1")
+
+	path := filepath.Join(t.TempDir(), "seed.keystore")
+	assert.Nil(t, os.WriteFile(path, data, 0600))
+	ring, err := LoadKeystore(path, []byte("correct horse"))
+	assert.Nil(t, err)
+	assert.Len(t, ring.Keys(), 2)
+}
+
+func Test_EncryptKeystore_Invalid(t *testing.T) {
+	_, err := EncryptKeystore(testKeys(), nil, testParams)
+	assert.EqualError(t, err, "passphrase must not be empty")
+
+	_, err = EncryptKeystore(nil, []byte("pass"), testParams)
+	assert.NotNil(t, err)
+}
@@ -0,0 +1,237 @@
+package keyring
+
+import (
+	"bytes"
+	"cmp"
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+)
+
+// PassphraseEnv is the environment variable the keystore passphrase is read from when no passphrase file is set
+const PassphraseEnv = "KEYSTORE_PASSPHRASE"
+
+// Source is where the keys are read from. At most one of the files can be set,
+// the seed is used when none is.
+type Source struct {
+	SeedHex string
+	// SeedFile holds a single hex seed, i.e. a mounted secret
+	SeedFile string
+	// File is a keyring file, see Load
+	File string
+	// Dir holds one JSON key per file, hidden files are skipped
+	Dir string
+	// Keystore is a passphrase encrypted keyring, see EncryptKeystore
+	Keystore   string
+	Passphrase []byte
+}
+
+// Load reads the keyring of the source
+func (s Source) Load() (*Keyring, error) {
+	err := s.validate()
+	if err != nil {
+		return nil, err
+	}
+
+	switch {
+	case len(s.SeedFile) > 0:
+		return LoadSeedFile(s.SeedFile)
+	case len(s.File) > 0:
+		return Load(s.File)
+	case len(s.Dir) > 0:
+		return LoadDir(s.Dir)
+	case len(s.Keystore) > 0:
+		return LoadKeystore(s.Keystore, s.Passphrase)
+	}
+	return Single(s.SeedHex)
+}
+
+// Reloadable reports whether the keys are read from files that can change at runtime
+func (s Source) Reloadable() bool {
+	return len(s.SeedFile) > 0 || len(s.File) > 0 || len(s.Dir) > 0 || len(s.Keystore) > 0
+}
+
+// String describes the source for logs, it never includes a seed
+func (s Source) String() string {
+	switch {
+	case len(s.SeedFile) > 0:
+		return "seed file " + s.SeedFile
+	case len(s.File) > 0:
+		return "keyring file " + s.File
+	case len(s.Dir) > 0:
+		return "keyring directory " + s.Dir
+	case len(s.Keystore) > 0:
+		return "keystore " + s.Keystore
+	}
+	return "seed"
+}
+
+func (s Source) validate() error {
+	set := 0
+	for _, path := range []string{s.SeedFile, s.File, s.Dir, s.Keystore} {
+		if len(path) > 0 {
+			set++
+		}
+	}
+	if set > 1 {
+		return errors.New("only one of seed file, keyring file, keyring directory and keystore can be set")
+	} else if len(s.Keystore) > 0 && len(s.Passphrase) == 0 {
+		return errors.New("keystore requires a passphrase")
+	}
+	return nil
+}
+
+// path returns the file of the source, all but one of them are empty
+func (s Source) path() string {
+	return cmp.Or(s.SeedFile, s.File, s.Dir, s.Keystore)
+}
+
+// digest hashes the files of the source to detect changes
+func (s Source) digest() ([]byte, error) {
+	h := sha256.New()
+	switch {
+	case len(s.Dir) > 0:
+		paths, err := keyFiles(s.Dir)
+		if err != nil {
+			return nil, err
+		}
+		for _, path := range paths {
+			b, errRead := os.ReadFile(path)
+			if errRead != nil {
+				return nil, errRead
+			}
+			fmt.Fprintf(h, "%s\x00%d\x00", filepath.Base(path), len(b))
+			h.Write(b)
+		}
+	case s.Reloadable():
+		b, err := os.ReadFile(filepath.Clean(s.path()))
+		if err != nil {
+			return nil, err
+		}
+		h.Write(b)
+	}
+	return h.Sum(nil), nil
+}
+
+// LoadSeedFile reads a file of a single hex seed, surrounding whitespace is ignored
+func LoadSeedFile(path string) (*Keyring, error) {
+	b, err := os.ReadFile(filepath.Clean(path))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read seed: %w", err)
+	}
+	return Single(strings.TrimSpace(string(b)))
+}
+
+// LoadDir reads a directory of key files, each a JSON key: {"id", "epoch", "activeFrom", "seedHex"}.
+// Hidden files and subdirectories are skipped, so a mounted Kubernetes secret can be read as is.
+func LoadDir(dir string) (*Keyring, error) {
+	paths, err := keyFiles(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	keys := make([]Key, 0, len(paths))
+	for _, path := range paths {
+		b, errRead := os.ReadFile(path)
+		if errRead != nil {
+			return nil, fmt.Errorf("failed to read key: %w", errRead)
+		}
+
+		var k Key
+		errRead = json.Unmarshal(b, &k)
+		if errRead != nil {
+			return nil, fmt.Errorf("invalid key %s: %w", path, errRead)
+		}
+		keys = append(keys, k)
+	}
+	return New(keys)
+}
+
+func keyFiles(dir string) ([]string, error) {
+	entries, err := os.ReadDir(filepath.Clean(dir))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read keyring directory: %w", err)
+	}
+
+	var paths []string
+	for _, e := range entries {
+		if strings.HasPrefix(e.Name(), ".") {
+			continue
+		}
+
+		// Secret mounts link every file into a hidden directory, so follow links
+		path := filepath.Join(dir, e.Name())
+		info, errStat := os.Stat(path)
+		if errStat != nil {
+			return nil, errStat
+		} else if !info.Mode().IsRegular() {
+			continue
+		}
+		paths = append(paths, path)
+	}
+	slices.Sort(paths)
+	return paths, nil
+}
+
+// ReadPassphrase reads the keystore passphrase from a file, or from the environment variable when path is empty.
+// A trailing line break is removed.
+func ReadPassphrase(path string, env string) ([]byte, error) {
+	var b []byte
+	if len(path) > 0 {
+		var err error
+		b, err = os.ReadFile(filepath.Clean(path))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read passphrase: %w", err)
+		}
+	} else {
+		b = []byte(os.Getenv(env))
+	}
+
+	b = bytes.TrimSuffix(b, []byte("\n"))
+	b = bytes.TrimSuffix(b, []byte("\r"))
+	return b, nil
+}
+
+// Watch checks the files of the source every interval and calls fn with the new keyring
+// whenever their content changes, until ctx is done. Invalid files are logged
+// and the keyring in use is kept.
+func Watch(ctx context.Context, source Source, interval time.Duration, fn func(*Keyring)) {
+	last, _ := source.digest()
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		digest, err := source.digest()
+		if err != nil {
+			slog.Error("failed to read keyring", "source", source.String(), "error", err.Error())
+			continue
+		} else if bytes.Equal(digest, last) {
+			continue
+		}
+
+		ring, err := source.Load()
+		if err != nil {
+			slog.Error("failed to reload keyring, keeping the current keys", "source", source.String(), "error", err.Error())
+			last = digest
+			continue
+		}
+
+		last = digest
+		fn(ring)
+	}
+}
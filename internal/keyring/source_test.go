@@ -0,0 +1,64 @@
+package keyring
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Source_Load(t *testing.T) {
+	dir := t.TempDir()
+
+	seedFile := filepath.Join(dir, "seed")
+	assert.Nil(t, os.WriteFile(seedFile, []byte(seedA+"\n"), 0600))
+	ring, err := Source{SeedFile: seedFile}.Load()
+	assert.Nil(t, err)
+	assert.Equal(t, seedA, ring.Keys()[0].SeedHex)
+
+	// A directory laid out like a mounted secret
+	keysDir := filepath.Join(dir, "keys")
+	data := filepath.Join(keysDir, "..2025_01_01")
+	assert.Nil(t, os.MkdirAll(data, 0700))
+	assert.Nil(t, os.WriteFile(filepath.Join(data, "2025-01"), []byte(`{"id":"2025-01","epoch":1,"activeFrom":"2025-01-01T00:00:00Z","seedHex":"`+seedA+`"}`), 0600))
+	assert.Nil(t, os.WriteFile(filepath.Join(data, "2025-02"), []byte(`{"id":"2025-02","epoch":2,"activeFrom":"2025-02-01T00:00:00Z","seedHex":"`+seedB+`"}`), 0600))
+	assert.Nil(t, os.Symlink("..2025_01_01", filepath.Join(keysDir, "..data")))
+	assert.Nil(t, os.Symlink(filepath.Join("..data", "2025-01"), filepath.Join(keysDir, "2025-01")))
+	assert.Nil(t, os.Symlink(filepath.Join("..data", "2025-02"), filepath.Join(keysDir, "2025-02")))
+	ring, err = Source{Dir: keysDir}.Load()
+	assert.Nil(t, err)
+	assert.Len(t, ring.Keys(), 2)
+	assert.Equal(t, "2025-02", ring.Keys()[1].ID)
+
+	keystoreFile := filepath.Join(dir, "seed.keystore")
+	encrypted, err := EncryptKeystore(testKeys(), []byte("pass"), testParams)
+	assert.Nil(t, err)
+	assert.Nil(t, os.WriteFile(keystoreFile, encrypted, 0600))
+	ring, err = Source{Keystore: keystoreFile, Passphrase: []byte("pass")}.Load()
+	assert.Nil(t, err)
+	assert.Len(t, ring.Keys(), 2)
+
+	_, err = Source{Keystore: keystoreFile}.Load()
+	assert.EqualError(t, err, "keystore requires a passphrase")
+
+	_, err = Source{SeedFile: seedFile, Dir: keysDir}.Load()
+	assert.EqualError(t, err, "only one of seed file, keyring file, keyring directory and keystore can be set")
+
+	ring, err = Source{SeedHex: seedC}.Load()
+	assert.Nil(t, err)
+	assert.Equal(t, seedC, ring.Keys()[0].SeedHex)
+}
+
+func Test_ReadPassphrase(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "passphrase")
+	assert.Nil(t, os.WriteFile(path, []byte("secret \r\n"), 0600))
+	b, err := ReadPassphrase(path, "TEST_KEYSTORE_PASSPHRASE")
+	assert.Nil(t, err)
+	assert.Equal(t, "secret ", string(b))
+
+	t.Setenv("TEST_KEYSTORE_PASSPHRASE", "from env")
+	b, err = ReadPassphrase("", "TEST_KEYSTORE_PASSPHRASE")
+	assert.Nil(t, err)
+	assert.Equal(t, "from env", string(b))
+}
@@ -0,0 +1,132 @@
+package nist
+
+import (
+	"math"
+	"math/bits"
+	"math/cmplx"
+	"sync"
+)
+
+// dftModuli returns the moduli of the first len(x)/2 terms of the discrete Fourier transform of x.
+// Lengths that are not a power of two are transformed with Bluestein's algorithm.
+func dftModuli(x []float64) []float64 {
+	n := len(x)
+	var spectrum []complex128
+	if n&(n-1) == 0 {
+		spectrum = make([]complex128, n)
+		for i, v := range x {
+			spectrum[i] = complex(v, 0)
+		}
+		fft(spectrum, twiddles(n), false)
+	} else {
+		spectrum = bluestein(x)
+	}
+
+	moduli := make([]float64, n/2)
+	for i := range moduli {
+		moduli[i] = cmplx.Abs(spectrum[i])
+	}
+	return moduli
+}
+
+// plan is the chirp of Bluestein's algorithm for a length and its transform, which only depend on the length
+type plan struct {
+	n       int
+	size    int
+	chirp   []complex128
+	kernel  []complex128
+	twiddle []complex128
+}
+
+// plans caches the plan of every length, the sequences of a run share their length
+var plans sync.Map
+
+func planFor(n int) *plan {
+	if p, ok := plans.Load(n); ok {
+		return p.(*plan)
+	}
+
+	size := 1 << bits.Len(uint(2*n-1))
+	p := &plan{n: n, size: size, twiddle: twiddles(size)}
+
+	// chirp[k] is exp(-i pi k^2 / n), k^2 is reduced modulo 2n to keep the angle exact
+	p.chirp = make([]complex128, n)
+	for k := range p.chirp {
+		kk := (int64(k) * int64(k)) % (2 * int64(n))
+		p.chirp[k] = cmplx.Rect(1, -math.Pi*float64(kk)/float64(n))
+	}
+
+	p.kernel = make([]complex128, size)
+	for k := range n {
+		p.kernel[k] = cmplx.Conj(p.chirp[k])
+		if k > 0 {
+			p.kernel[size-k] = p.kernel[k]
+		}
+	}
+	fft(p.kernel, p.twiddle, false)
+
+	plans.Store(n, p)
+	return p
+}
+
+// bluestein transforms x of any length as a convolution of a power of two length
+func bluestein(x []float64) []complex128 {
+	p := planFor(len(x))
+
+	a := make([]complex128, p.size)
+	for k, v := range x {
+		a[k] = complex(v, 0) * p.chirp[k]
+	}
+	fft(a, p.twiddle, false)
+	for i := range a {
+		a[i] *= p.kernel[i]
+	}
+	fft(a, p.twiddle, true)
+
+	result := make([]complex128, p.n)
+	scale := complex(float64(p.size), 0)
+	for k := range result {
+		result[k] = a[k] / scale * p.chirp[k]
+	}
+	return result
+}
+
+// twiddles returns exp(-2 pi i j / n) for j below n/2
+func twiddles(n int) []complex128 {
+	twiddle := make([]complex128, n/2)
+	for j := range twiddle {
+		twiddle[j] = cmplx.Rect(1, -2*math.Pi*float64(j)/float64(n))
+	}
+	return twiddle
+}
+
+// fft transforms a in place with the twiddles of its length, which must be a power of two. The inverse is not scaled.
+func fft(a []complex128, twiddle []complex128, inverse bool) {
+	n := len(a)
+	for i, j := 1, 0; i < n; i++ {
+		bit := n >> 1
+		for ; j&bit != 0; bit >>= 1 {
+			j ^= bit
+		}
+		j ^= bit
+		if i < j {
+			a[i], a[j] = a[j], a[i]
+		}
+	}
+
+	for length := 2; length <= n; length <<= 1 {
+		stride := n / length
+		for i := 0; i < n; i += length {
+			for j := range length / 2 {
+				w := twiddle[j*stride]
+				if inverse {
+					w = cmplx.Conj(w)
+				}
+				u := a[i+j]
+				v := a[i+j+length/2] * w
+				a[i+j] = u + v
+				a[i+j+length/2] = u - v
+			}
+		}
+	}
+}
@@ -0,0 +1,110 @@
+// Package nist implements the statistical test suite of NIST SP 800-22 rev. 1a
+// for random number generators, and its report on the uniformity of the
+// p-values and the proportion of passing sequences.
+package nist
+
+import (
+	"fmt"
+	"io"
+)
+
+// Params are the parameters of the tests, DefaultParams are those recommended by NIST SP 800-22
+type Params struct {
+	BlockFrequencyBlockLength     int
+	NonOverlappingTemplateLength  int
+	ApproximateEntropyBlockLength int
+	SerialBlockLength             int
+	LinearComplexityBlockLength   int
+}
+
+// DefaultParams returns the parameters of the NIST statistical test suite
+func DefaultParams() Params {
+	return Params{
+		BlockFrequencyBlockLength:     128,
+		NonOverlappingTemplateLength:  9,
+		ApproximateEntropyBlockLength: 10,
+		SerialBlockLength:             16,
+		LinearComplexityBlockLength:   500,
+	}
+}
+
+// nonOverlappingBlocks is the number of blocks of the non-overlapping template matching test
+const nonOverlappingBlocks = 8
+
+// Result is one p-value of a test on a sequence. Tests with several statistics,
+// one per template or state, have a Result each.
+type Result struct {
+	Name   string
+	PValue float64
+	// Err is set when the test does not apply to the sequence, see ErrNotApplicable
+	Err error
+}
+
+// Run runs all tests on a sequence of bits, each 0 or 1. The results are in the
+// order of the rows of the report, which is the same for every sequence of the
+// same length.
+func Run(bits []byte, params Params) []Result {
+	var results []Result
+	add := func(name string, pValue float64, err error) {
+		results = append(results, Result{Name: name, PValue: pValue, Err: err})
+	}
+	addAll := func(name string, rows int, pValues []float64, err error) {
+		for i := range rows {
+			if err != nil {
+				add(name, 0, err)
+			} else {
+				add(name, pValues[i], nil)
+			}
+		}
+	}
+
+	add("Frequency", Frequency(bits), nil)
+	p, err := BlockFrequency(bits, params.BlockFrequencyBlockLength)
+	add("BlockFrequency", p, err)
+	add("CumulativeSums", CumulativeSums(bits, false), nil)
+	add("CumulativeSums", CumulativeSums(bits, true), nil)
+	add("Runs", Runs(bits), nil)
+	p, err = LongestRunOfOnes(bits)
+	add("LongestRun", p, err)
+	p, err = Rank(bits)
+	add("Rank", p, err)
+	add("FFT", DiscreteFourierTransform(bits), nil)
+	pValues, err := NonOverlappingTemplateMatchings(bits, params.NonOverlappingTemplateLength, nonOverlappingBlocks)
+	addAll("NonOverlappingTemplate", len(AperiodicTemplates(params.NonOverlappingTemplateLength)), pValues, err)
+	p, err = OverlappingTemplateMatchings(bits)
+	add("OverlappingTemplate", p, err)
+	p, err = Universal(bits)
+	add("Universal", p, err)
+	add("ApproximateEntropy", ApproximateEntropy(bits, params.ApproximateEntropyBlockLength), nil)
+	pValues, err = RandomExcursions(bits)
+	addAll("RandomExcursions", len(excursionStates), pValues, err)
+	pValues, err = RandomExcursionsVariant(bits)
+	addAll("RandomExcursionsVariant", len(variantStates), pValues, err)
+	p1, p2 := Serial(bits, params.SerialBlockLength)
+	add("Serial", p1, nil)
+	add("Serial", p2, nil)
+	p, err = LinearComplexity(bits, params.LinearComplexityBlockLength)
+	add("LinearComplexity", p, err)
+	return results
+}
+
+// Bits expands bytes into bits, the most significant bit of each byte first
+func Bits(data []byte) []byte {
+	bits := make([]byte, 0, 8*len(data))
+	for _, b := range data {
+		for i := 7; i >= 0; i-- {
+			bits = append(bits, b>>i&1)
+		}
+	}
+	return bits
+}
+
+// ReadSequence reads a sequence of n bits from r
+func ReadSequence(r io.Reader, n int) ([]byte, error) {
+	data := make([]byte, (n+7)/8)
+	_, err := io.ReadFull(r, data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read a sequence of %v bits: %w", n, err)
+	}
+	return Bits(data)[:n], nil
+}
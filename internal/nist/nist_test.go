@@ -0,0 +1,268 @@
+package nist
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/binary"
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// bitString parses a sequence written as 0 and 1
+func bitString(s string) []byte {
+	bits := make([]byte, len(s))
+	for i, c := range s {
+		bits[i] = byte(c - '0')
+	}
+	return bits
+}
+
+// testBits is a sequence of SHA-256 in counter mode
+func testBits(n int) []byte {
+	data := make([]byte, 0, n/8+32)
+	var counter [8]byte
+	for i := uint64(0); len(data) < n/8+1; i++ {
+		binary.BigEndian.PutUint64(counter[:], i)
+		sum := sha256.Sum256(counter[:])
+		data = append(data, sum[:]...)
+	}
+	return Bits(data)[:n]
+}
+
+// The examples of the sections of NIST SP 800-22 rev. 1a describing each test
+
+func Test_Frequency(t *testing.T) {
+	assert.InDelta(t, 0.527089, Frequency(bitString("1011010101")), 1e-6)
+}
+
+func Test_BlockFrequency(t *testing.T) {
+	p, err := BlockFrequency(bitString("0110011010"), 3)
+	assert.NoError(t, err)
+	assert.InDelta(t, 0.801252, p, 1e-6)
+
+	_, err = BlockFrequency(bitString("01"), 3)
+	assert.ErrorIs(t, err, ErrNotApplicable)
+}
+
+func Test_CumulativeSums(t *testing.T) {
+	assert.InDelta(t, 0.4116588, CumulativeSums(bitString("1011010111"), false), 1e-6)
+}
+
+func Test_Runs(t *testing.T) {
+	assert.InDelta(t, 0.147232, Runs(bitString("1001101011")), 1e-6)
+	assert.Equal(t, 0.0, Runs(bitString("1111111111")))
+}
+
+func Test_LongestRunOfOnes(t *testing.T) {
+	bits := bitString("11001100000101010110110001001100111000000000001001001101010100010001001111010110100000001101011111001100111001101101100010110010")
+	p, err := LongestRunOfOnes(bits)
+	assert.NoError(t, err)
+	assert.InDelta(t, 0.180609, p, 1e-6)
+
+	_, err = LongestRunOfOnes(bits[:100])
+	assert.ErrorIs(t, err, ErrNotApplicable)
+}
+
+func Test_Rank(t *testing.T) {
+	assert.InDelta(t, 0.2888, rankProbability(32, 32), 1e-4)
+	assert.InDelta(t, 0.5776, rankProbability(32, 31), 1e-4)
+
+	var identity [32]uint32
+	for i := range identity {
+		identity[i] = 1 << i
+	}
+	assert.Equal(t, 32, rank(identity))
+	identity[5] = identity[3] ^ identity[7]
+	assert.Equal(t, 31, rank(identity))
+
+	p, err := Rank(testBits(100000))
+	assert.NoError(t, err)
+	assert.Greater(t, p, DefaultAlpha)
+
+	_, err = Rank(testBits(1000))
+	assert.ErrorIs(t, err, ErrNotApplicable)
+}
+
+func Test_DiscreteFourierTransform(t *testing.T) {
+	// The example of the standard counts N1 = 4 with an older threshold, with T = 5.47 of rev. 1a
+	// all 5 moduli (0, 2, 4.47, 2, 4.47) are below it: d = (5 - 4.75) / sqrt(10 * 0.95 * 0.05 / 4)
+	d := 0.25 / math.Sqrt(10*0.95*0.05/4)
+	assert.InDelta(t, math.Erfc(d/math.Sqrt2), DiscreteFourierTransform(bitString("1001010011")), 1e-12)
+	assert.InDelta(t, 0.468160, DiscreteFourierTransform(bitString("1001010011")), 1e-6)
+
+	// Bluestein's algorithm agrees with the direct transform
+	x := []float64{1, -1, -1, 1, 1, 1, -1, 1, -1, -1, 1, 1}
+	moduli := dftModuli(x)
+	for k := range moduli {
+		var sum complex128
+		for j, v := range x {
+			angle := -2 * math.Pi * float64(j*k) / float64(len(x))
+			sum += complex(v*math.Cos(angle), v*math.Sin(angle))
+		}
+		assert.InDelta(t, math.Hypot(real(sum), imag(sum)), moduli[k], 1e-9)
+	}
+}
+
+func Test_NonOverlappingTemplateMatchings(t *testing.T) {
+	assert.Equal(t, []int{0b001, 0b011, 0b100, 0b110}, AperiodicTemplates(3))
+	assert.Len(t, AperiodicTemplates(9), 148)
+	assert.Len(t, AperiodicTemplates(10), 284)
+
+	pValues, err := NonOverlappingTemplateMatchings(bitString("10100100101110010110"), 3, 2)
+	assert.NoError(t, err)
+	assert.Len(t, pValues, 4)
+	assert.InDelta(t, 0.344154, pValues[0], 1e-6)
+}
+
+func Test_OverlappingTemplateMatchings(t *testing.T) {
+	p, err := OverlappingTemplateMatchings(testBits(1000000))
+	assert.NoError(t, err)
+	assert.Greater(t, p, DefaultAlpha)
+
+	p, err = OverlappingTemplateMatchings(bytes.Repeat([]byte{1}, 100000))
+	assert.NoError(t, err)
+	assert.Less(t, p, DefaultAlpha)
+}
+
+func Test_Universal(t *testing.T) {
+	p, err := Universal(testBits(1000000))
+	assert.NoError(t, err)
+	assert.Greater(t, p, DefaultAlpha)
+
+	// A repeating sequence compresses
+	p, err = Universal(bytes.Repeat(testBits(4096), 100))
+	assert.NoError(t, err)
+	assert.Less(t, p, DefaultAlpha)
+
+	_, err = Universal(testBits(1000))
+	assert.ErrorIs(t, err, ErrNotApplicable)
+}
+
+func Test_ApproximateEntropy(t *testing.T) {
+	assert.InDelta(t, 0.261961, ApproximateEntropy(bitString("0100110101"), 3), 1e-6)
+}
+
+func Test_Serial(t *testing.T) {
+	p1, p2 := Serial(bitString("0011011101"), 3)
+	assert.InDelta(t, 0.808792, p1, 1e-6)
+	assert.InDelta(t, 0.670320, p2, 1e-6)
+}
+
+func Test_LinearComplexity(t *testing.T) {
+	assert.Equal(t, 4, berlekampMassey(bitString("1101011110001")))
+
+	// The packed implementation agrees with the textbook one
+	bits := testBits(20 * 500)
+	for i := range 20 {
+		block := bits[i*500 : (i+1)*500]
+		assert.Equal(t, referenceBerlekampMassey(block), berlekampMassey(block))
+	}
+	for _, n := range []int{1, 63, 64, 65, 130} {
+		assert.Equal(t, referenceBerlekampMassey(bits[:n]), berlekampMassey(bits[:n]), n)
+	}
+
+	p, err := LinearComplexity(testBits(100000), 500)
+	assert.NoError(t, err)
+	assert.Greater(t, p, DefaultAlpha)
+
+	// An LFSR of 10 bits has a linear complexity of 10 at most
+	lfsr := make([]byte, 100000)
+	copy(lfsr, bitString("1011001110"))
+	for i := 10; i < len(lfsr); i++ {
+		lfsr[i] = lfsr[i-10] ^ lfsr[i-7]
+	}
+	p, err = LinearComplexity(lfsr, 500)
+	assert.NoError(t, err)
+	assert.Less(t, p, DefaultAlpha)
+}
+
+func Test_RandomExcursions(t *testing.T) {
+	bits := bitString("0110110101")
+	pValues, err := randomExcursions(bits, 0)
+	assert.NoError(t, err)
+	// The standard rounds the statistic to 4.333033, it is 13/3
+	assert.InDelta(t, 0.502529, pValues[4], 1e-4)
+
+	pValues, err = randomExcursionsVariant(bits, 0)
+	assert.NoError(t, err)
+	assert.InDelta(t, 0.683091, pValues[9], 1e-6)
+
+	_, err = RandomExcursions(bits)
+	assert.ErrorIs(t, err, ErrNotApplicable)
+	_, err = RandomExcursionsVariant(bits)
+	assert.ErrorIs(t, err, ErrNotApplicable)
+}
+
+func Test_Run(t *testing.T) {
+	results := Run(testBits(1000000), DefaultParams())
+	assert.Len(t, results, 1+1+2+1+1+1+1+148+1+1+1+8+18+2+1)
+
+	failed := 0
+	for _, res := range results {
+		if res.Err == nil && res.PValue < DefaultAlpha {
+			failed++
+		}
+	}
+	// 1% of the p-values fall below alpha by chance
+	assert.LessOrEqual(t, failed, 8)
+}
+
+func Test_Assess(t *testing.T) {
+	sequences := make([][]Result, 100)
+	for i := range sequences {
+		p := (float64(i) + 0.5) / 100
+		sequences[i] = []Result{
+			{Name: "Uniform", PValue: p},
+			{Name: "Low", PValue: p / 10},
+			{Name: "Universal", Err: ErrNotApplicable},
+		}
+	}
+
+	s := Assess(sequences, DefaultAlpha)
+	assert.Len(t, s.Rows, 2)
+	assert.Equal(t, [10]int{10, 10, 10, 10, 10, 10, 10, 10, 10, 10}, s.Rows[0].Counts)
+	assert.InDelta(t, 1, s.Rows[0].Uniformity, 1e-9)
+	assert.Equal(t, 99, s.Rows[0].Passed)
+	assert.Equal(t, 97, s.Rows[0].MinPassed)
+	assert.True(t, s.Rows[0].Pass())
+	assert.False(t, s.Rows[1].Pass())
+	assert.Equal(t, 1, s.Failed())
+	assert.Len(t, s.Skipped, 1)
+
+	var buf bytes.Buffer
+	assert.NoError(t, s.WriteReport(&buf, "test"))
+	report := buf.String()
+	assert.Contains(t, report, "   generator is <test>")
+	assert.Contains(t, report, " 10  10  10  10  10  10  10  10  10  10  1.000000     99/100     Uniform")
+	assert.Contains(t, report, "100   0   0   0   0   0   0   0   0   0  0.000000 *   90/100  *  Low")
+	assert.Contains(t, report, "Universal was not run")
+	assert.Equal(t, 1, strings.Count(report, "RESULTS FOR THE UNIFORMITY"))
+}
+
+func referenceBerlekampMassey(s []byte) int {
+	n := len(s)
+	c := make([]byte, n+1)
+	b := make([]byte, n+1)
+	c[0], b[0] = 1, 1
+	l, m := 0, -1
+	for i := range n {
+		d := s[i]
+		for j := 1; j <= l; j++ {
+			d ^= c[j] & s[i-j]
+		}
+		if d == 0 {
+			continue
+		}
+		t := append([]byte(nil), c...)
+		for j := 0; j+i-m <= n; j++ {
+			c[j+i-m] ^= b[j]
+		}
+		if 2*l <= i {
+			l, m, b = i+1-l, i, t
+		}
+	}
+	return l
+}
@@ -0,0 +1,177 @@
+package nist
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"strings"
+
+	"github.com/fasttrack-solutions/random/internal/stats"
+)
+
+// DefaultAlpha is the significance level of the tests recommended by NIST SP 800-22
+const DefaultAlpha = 0.01
+
+const (
+	// minUniformitySequences is the least number of sequences the uniformity of the p-values is assessed on
+	minUniformitySequences = 55
+	// uniformityAlpha is the significance level of the uniformity of the p-values
+	uniformityAlpha = 0.0001
+)
+
+// Row is the p-values of one test across the sequences, a row of the report
+type Row struct {
+	Name string
+	// Counts is the number of p-values in each tenth of [0, 1]
+	Counts [10]int
+	// Uniformity is the p-value of a chi-square test of the uniformity of the p-values, NaN for too few sequences
+	Uniformity float64
+	// Passed is the number of Sequences with a p-value of at least alpha
+	Passed    int
+	Sequences int
+	// MinPassed is the least Passed of a random generator, 3 standard deviations below the expected proportion
+	MinPassed int
+}
+
+// Pass reports whether the proportion of passing sequences and the uniformity of the p-values are acceptable
+func (r Row) Pass() bool {
+	return r.Passed >= r.MinPassed && (math.IsNaN(r.Uniformity) || r.Uniformity >= uniformityAlpha)
+}
+
+// Summary assesses the results of the sequences
+type Summary struct {
+	Alpha     float64
+	Sequences int
+	Rows      []Row
+	// Skipped are the tests that applied to none of the sequences, with the reason
+	Skipped []Result
+}
+
+// Failed is the number of rows that do not pass
+func (s Summary) Failed() int {
+	failed := 0
+	for _, r := range s.Rows {
+		if !r.Pass() {
+			failed++
+		}
+	}
+	return failed
+}
+
+// Assess summarizes the results of Run on every sequence, all of the same length
+func Assess(results [][]Result, alpha float64) Summary {
+	s := Summary{Alpha: alpha, Sequences: len(results)}
+	if len(results) == 0 {
+		return s
+	}
+
+	for i, first := range results[0] {
+		row := Row{Name: first.Name}
+		var reason error
+		for _, sequence := range results {
+			res := sequence[i]
+			if res.Err != nil {
+				reason = res.Err
+				continue
+			}
+
+			row.Sequences++
+			row.Counts[min(int(res.PValue*10), 9)]++
+			if res.PValue >= alpha {
+				row.Passed++
+			}
+		}
+		if row.Sequences == 0 {
+			// Tests with a row per template or state are skipped once
+			if len(s.Skipped) == 0 || s.Skipped[len(s.Skipped)-1].Name != row.Name {
+				s.Skipped = append(s.Skipped, Result{Name: row.Name, Err: reason})
+			}
+			continue
+		}
+
+		row.Uniformity = math.NaN()
+		if row.Sequences >= minUniformitySequences {
+			expected := float64(row.Sequences) / 10
+			chi2 := 0.0
+			for _, c := range row.Counts {
+				chi2 += (float64(c) - expected) * (float64(c) - expected) / expected
+			}
+			row.Uniformity = stats.Igamc(9.0/2, chi2/2)
+		}
+		row.MinPassed = minPassed(row.Sequences, alpha)
+		s.Rows = append(s.Rows, row)
+	}
+	return s
+}
+
+// minPassed is the least number of passing sequences of a random generator
+func minPassed(sequences int, alpha float64) int {
+	p := 1 - alpha
+	n := float64(sequences)
+	return max(int(math.Ceil((p-3*math.Sqrt(p*alpha/n))*n)), 0)
+}
+
+// WriteReport writes the summary in the layout of the finalAnalysisReport.txt of the NIST STS,
+// generator names the source of the sequences
+func (s Summary) WriteReport(w io.Writer, generator string) error {
+	const line = "------------------------------------------------------------------------------"
+	const dashes = "- - - - - - - - - - - - - - - - - - - - - - - - - - - - - - - - - - - - - - - -"
+
+	var b strings.Builder
+	fmt.Fprintln(&b, line)
+	fmt.Fprintln(&b, "RESULTS FOR THE UNIFORMITY OF P-VALUES AND THE PROPORTION OF PASSING SEQUENCES")
+	fmt.Fprintln(&b, line)
+	fmt.Fprintf(&b, "   generator is <%s>\n", generator)
+	fmt.Fprintln(&b, line)
+	fmt.Fprintln(&b, " C1  C2  C3  C4  C5  C6  C7  C8  C9 C10  P-VALUE  PROPORTION  STATISTICAL TEST")
+	fmt.Fprintln(&b, line)
+
+	excursionSequences := 0
+	for _, r := range s.Rows {
+		for _, c := range r.Counts {
+			fmt.Fprintf(&b, "%3d ", c)
+		}
+		if math.IsNaN(r.Uniformity) {
+			fmt.Fprint(&b, "   ----    ")
+		} else {
+			fmt.Fprintf(&b, "%9.6f %s", r.Uniformity, mark(r.Uniformity < uniformityAlpha))
+		}
+		fmt.Fprintf(&b, " %4d/%-4d %s  %s\n", r.Passed, r.Sequences, mark(r.Passed < r.MinPassed), r.Name)
+
+		if strings.HasPrefix(r.Name, "RandomExcursions") {
+			excursionSequences = r.Sequences
+		}
+	}
+
+	fmt.Fprintln(&b)
+	fmt.Fprintln(&b, dashes)
+	fmt.Fprintln(&b, "The minimum pass rate for each statistical test with the exception of the")
+	fmt.Fprintf(&b, "random excursion (variant) test is approximately = %v for a\n", minPassed(s.Sequences, s.Alpha))
+	fmt.Fprintf(&b, "sample size = %v binary sequences.\n", s.Sequences)
+	if excursionSequences > 0 {
+		fmt.Fprintln(&b)
+		fmt.Fprintln(&b, "The minimum pass rate for the random excursion (variant) test")
+		fmt.Fprintf(&b, "is approximately = %v for a sample size = %v binary sequences.\n", minPassed(excursionSequences, s.Alpha), excursionSequences)
+	}
+	if s.Sequences < minUniformitySequences {
+		fmt.Fprintln(&b)
+		fmt.Fprintf(&b, "The uniformity of the p-values (----) requires at least %v sequences.\n", minUniformitySequences)
+	}
+	for _, skipped := range s.Skipped {
+		fmt.Fprintln(&b)
+		fmt.Fprintf(&b, "%s was not run: %v\n", skipped.Name, skipped.Err)
+	}
+	fmt.Fprintln(&b)
+	fmt.Fprintf(&b, "%v of %v rows failed at alpha = %v, failing rows are marked with *.\n", s.Failed(), len(s.Rows), s.Alpha)
+	fmt.Fprintln(&b, dashes)
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+func mark(failed bool) string {
+	if failed {
+		return "*"
+	}
+	return " "
+}
@@ -0,0 +1,673 @@
+package nist
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/bits"
+
+	"github.com/fasttrack-solutions/random/internal/stats"
+)
+
+// ErrNotApplicable is returned when a sequence does not meet the input size
+// requirements of a test, or holds too few cycles for the random excursion tests
+var ErrNotApplicable = errors.New("test does not apply to the sequence")
+
+// Frequency is the monobit test: the proportion of ones
+func Frequency(bits []byte) float64 {
+	s := 0
+	for _, b := range bits {
+		s += 2*int(b) - 1
+	}
+	sObs := math.Abs(float64(s)) / math.Sqrt(float64(len(bits)))
+	return math.Erfc(sObs / math.Sqrt2)
+}
+
+// BlockFrequency is the proportion of ones in blocks of m bits
+func BlockFrequency(bits []byte, m int) (float64, error) {
+	n := len(bits) / m
+	if m < 1 || n < 1 {
+		return 0, fmt.Errorf("%w: fewer bits than the block length %v", ErrNotApplicable, m)
+	}
+
+	chi2 := 0.0
+	for i := range n {
+		ones := 0
+		for _, b := range bits[i*m : (i+1)*m] {
+			ones += int(b)
+		}
+		d := float64(ones)/float64(m) - 0.5
+		chi2 += d * d
+	}
+	chi2 *= 4 * float64(m)
+	return stats.Igamc(float64(n)/2, chi2/2), nil
+}
+
+// CumulativeSums is the maximal excursion of the random walk of the bits, from the first bit or, reversed, from the last
+func CumulativeSums(bits []byte, reverse bool) float64 {
+	n := len(bits)
+	s, z := 0, 0
+	for i := range bits {
+		b := bits[i]
+		if reverse {
+			b = bits[n-1-i]
+		}
+		s += 2*int(b) - 1
+		z = max(z, abs(s))
+	}
+	if z == 0 {
+		return 0
+	}
+
+	sqrtN := math.Sqrt(float64(n))
+	zf := float64(z)
+	// The bounds are truncated toward zero like the reference implementation
+	sum1 := 0.0
+	for k := (-n/z + 1) / 4; k <= (n/z-1)/4; k++ {
+		sum1 += normal(float64(4*k+1)*zf/sqrtN) - normal(float64(4*k-1)*zf/sqrtN)
+	}
+	sum2 := 0.0
+	for k := (-n/z - 3) / 4; k <= (n/z-1)/4; k++ {
+		sum2 += normal(float64(4*k+3)*zf/sqrtN) - normal(float64(4*k+1)*zf/sqrtN)
+	}
+	return 1 - sum1 + sum2
+}
+
+// Runs is the number of runs of identical bits. It returns 0 without counting
+// them when the proportion of ones already fails the frequency test.
+func Runs(bits []byte) float64 {
+	n := float64(len(bits))
+	ones := 0
+	for _, b := range bits {
+		ones += int(b)
+	}
+	pi := float64(ones) / n
+	if math.Abs(pi-0.5) >= 2/math.Sqrt(n) {
+		return 0
+	}
+
+	v := 1
+	for k := 1; k < len(bits); k++ {
+		if bits[k] != bits[k-1] {
+			v++
+		}
+	}
+	return math.Erfc(math.Abs(float64(v)-2*n*pi*(1-pi)) / (2 * math.Sqrt(2*n) * pi * (1 - pi)))
+}
+
+// longestRunClasses are the block length, the classes of the longest runs and
+// their probabilities of the longest run of ones test, by sequence length
+var longestRunClasses = []struct {
+	minBits       int
+	blockLength   int
+	shortest      int
+	probabilities []float64
+}{
+	{750000, 10000, 10, []float64{0.0882, 0.2092, 0.2483, 0.1933, 0.1208, 0.0675, 0.0727}},
+	{6272, 128, 4, []float64{0.1174035788, 0.242955959, 0.249363483, 0.17517706, 0.102701071, 0.112398847}},
+	{128, 8, 1, []float64{0.21484375, 0.3671875, 0.23046875, 0.1875}},
+}
+
+// LongestRunOfOnes is the longest run of ones in blocks, of 8, 128 or 10,000 bits by the length of the sequence
+func LongestRunOfOnes(bits []byte) (float64, error) {
+	for _, c := range longestRunClasses {
+		if len(bits) < c.minBits {
+			continue
+		}
+
+		k := len(c.probabilities) - 1
+		blocks := len(bits) / c.blockLength
+		counts := make([]int, k+1)
+		for i := range blocks {
+			longest, run := 0, 0
+			for _, b := range bits[i*c.blockLength : (i+1)*c.blockLength] {
+				if b == 1 {
+					run++
+					longest = max(longest, run)
+				} else {
+					run = 0
+				}
+			}
+			counts[min(max(longest-c.shortest, 0), k)]++
+		}
+
+		chi2 := 0.0
+		for i, p := range c.probabilities {
+			expected := float64(blocks) * p
+			d := float64(counts[i]) - expected
+			chi2 += d * d / expected
+		}
+		return stats.Igamc(float64(k)/2, chi2/2), nil
+	}
+	return 0, fmt.Errorf("%w: fewer than 128 bits", ErrNotApplicable)
+}
+
+// Rank is the rank of disjoint 32 by 32 matrices over GF(2)
+func Rank(bits []byte) (float64, error) {
+	const size = 32
+	blocks := len(bits) / (size * size)
+	if blocks < 38 {
+		return 0, fmt.Errorf("%w: fewer than 38 matrices of %v bits", ErrNotApplicable, size*size)
+	}
+
+	full, fullMinusOne := 0, 0
+	for i := range blocks {
+		var rows [size]uint32
+		for r := range rows {
+			for _, b := range bits[i*size*size+r*size : i*size*size+(r+1)*size] {
+				rows[r] = rows[r]<<1 | uint32(b)
+			}
+		}
+		switch rank(rows) {
+		case size:
+			full++
+		case size - 1:
+			fullMinusOne++
+		}
+	}
+
+	pFull := rankProbability(size, size)
+	pFullMinusOne := rankProbability(size, size-1)
+	pRest := 1 - pFull - pFullMinusOne
+	n := float64(blocks)
+	chi2 := sq(float64(full)-pFull*n)/(pFull*n) +
+		sq(float64(fullMinusOne)-pFullMinusOne*n)/(pFullMinusOne*n) +
+		sq(float64(blocks-full-fullMinusOne)-pRest*n)/(pRest*n)
+	return math.Exp(-chi2 / 2), nil
+}
+
+// rank is the rank over GF(2) of a matrix of 32 bit rows
+func rank(rows [32]uint32) int {
+	r := 0
+	for col := 31; col >= 0 && r < len(rows); col-- {
+		bit := uint32(1) << col
+		pivot := -1
+		for i := r; i < len(rows); i++ {
+			if rows[i]&bit != 0 {
+				pivot = i
+				break
+			}
+		}
+		if pivot < 0 {
+			continue
+		}
+
+		rows[r], rows[pivot] = rows[pivot], rows[r]
+		for i := range rows {
+			if i != r && rows[i]&bit != 0 {
+				rows[i] ^= rows[r]
+			}
+		}
+		r++
+	}
+	return r
+}
+
+// rankProbability is the probability of a random m by m matrix over GF(2) to have rank r
+func rankProbability(m int, r int) float64 {
+	p := math.Pow(2, float64(r*(2*m-r)-m*m))
+	for i := range r {
+		q := 1 - math.Pow(2, float64(i-m))
+		p *= q * q / (1 - math.Pow(2, float64(i-r)))
+	}
+	return p
+}
+
+// DiscreteFourierTransform is the spectral test: the number of peaks of the spectrum below the 95% threshold
+func DiscreteFourierTransform(bits []byte) float64 {
+	n := float64(len(bits))
+	x := make([]float64, len(bits))
+	for i, b := range bits {
+		x[i] = 2*float64(b) - 1
+	}
+
+	threshold := math.Sqrt(math.Log(1/0.05) * n)
+	below := 0
+	for _, m := range dftModuli(x) {
+		if m < threshold {
+			below++
+		}
+	}
+
+	expected := 0.95 * n / 2
+	d := (float64(below) - expected) / math.Sqrt(n*0.95*0.05/4)
+	return math.Erfc(math.Abs(d) / math.Sqrt2)
+}
+
+// NonOverlappingTemplateMatchings counts the occurrences of every aperiodic
+// template of m bits in blocks of the sequence, restarting after a match. It
+// returns a p-value per template, in the order of AperiodicTemplates.
+func NonOverlappingTemplateMatchings(bits []byte, m int, blocks int) ([]float64, error) {
+	blockLength := len(bits) / blocks
+	if m < 2 || m > 21 || blocks < 1 || blockLength < m {
+		return nil, fmt.Errorf("%w: blocks shorter than the template", ErrNotApplicable)
+	}
+
+	templates := AperiodicTemplates(m)
+	// index maps a window of m bits to its template plus one, 0 when it is periodic
+	index := make([]int, 1<<m)
+	for i, t := range templates {
+		index[t] = i + 1
+	}
+
+	// Every window matches one template at most, so all templates are counted in a single pass
+	counts := make([][]int, len(templates))
+	for i := range counts {
+		counts[i] = make([]int, blocks)
+	}
+	next := make([]int, len(templates))
+	mask := 1<<m - 1
+	for j := range blocks {
+		clear(next)
+		window := 0
+		for k, b := range bits[j*blockLength : (j+1)*blockLength] {
+			window = (window<<1 | int(b)) & mask
+			start := k - m + 1
+			if start < 0 {
+				continue
+			}
+			if t := index[window] - 1; t >= 0 && start >= next[t] {
+				counts[t][j]++
+				next[t] = start + m
+			}
+		}
+	}
+
+	mean := float64(blockLength-m+1) / math.Pow(2, float64(m))
+	variance := float64(blockLength) * (1/math.Pow(2, float64(m)) - float64(2*m-1)/math.Pow(2, float64(2*m)))
+	pValues := make([]float64, len(templates))
+	for i := range templates {
+		chi2 := 0.0
+		for _, w := range counts[i] {
+			chi2 += sq(float64(w)-mean) / variance
+		}
+		pValues[i] = stats.Igamc(float64(blocks)/2, chi2/2)
+	}
+	return pValues, nil
+}
+
+// AperiodicTemplates returns the templates of m bits that can not overlap a shifted copy of themselves, in ascending order
+func AperiodicTemplates(m int) []int {
+	var templates []int
+	for t := 0; t < 1<<m; t++ {
+		aperiodic := true
+		for shift := 1; shift < m && aperiodic; shift++ {
+			// The first m-shift bits equal the last m-shift bits
+			if t>>shift == t&(1<<(m-shift)-1) {
+				aperiodic = false
+			}
+		}
+		if aperiodic {
+			templates = append(templates, t)
+		}
+	}
+	return templates
+}
+
+// overlappingProbabilities are the probabilities of 0 to 4 and 5 or more
+// occurrences of nine ones in a block of 1032 bits
+var overlappingProbabilities = []float64{0.364091, 0.185659, 0.139381, 0.100571, 0.070432, 0.139865}
+
+// OverlappingTemplateMatchings counts the occurrences of nine ones in blocks of 1032 bits, including overlapping ones
+func OverlappingTemplateMatchings(bits []byte) (float64, error) {
+	const m, blockLength = 9, 1032
+	blocks := len(bits) / blockLength
+	if blocks < 1 {
+		return 0, fmt.Errorf("%w: fewer than %v bits", ErrNotApplicable, blockLength)
+	}
+
+	k := len(overlappingProbabilities) - 1
+	counts := make([]int, k+1)
+	for i := range blocks {
+		matches, run := 0, 0
+		for _, b := range bits[i*blockLength : (i+1)*blockLength] {
+			if b == 1 {
+				run++
+				if run >= m {
+					matches++
+				}
+			} else {
+				run = 0
+			}
+		}
+		counts[min(matches, k)]++
+	}
+
+	chi2 := 0.0
+	for i, p := range overlappingProbabilities {
+		expected := float64(blocks) * p
+		chi2 += sq(float64(counts[i])-expected) / expected
+	}
+	return stats.Igamc(float64(k)/2, chi2/2), nil
+}
+
+// universalBlockLengths are the minimum sequence lengths of Maurer's test from a block length of 6 bits
+var universalBlockLengths = []int{387840, 904960, 2068480, 4654080, 10342400, 22753280, 49643520, 107560960, 231669760, 496435200, 1059061760}
+
+// universalExpected and universalVariance are the expected value and variance of the statistic by block length
+var (
+	universalExpected = []float64{0, 0.7326495, 1.5374383, 2.4016068, 3.3112247, 4.2534266, 5.2177052, 6.1962507, 7.1836656, 8.1764248, 9.1723243, 10.170032, 11.168765, 12.168070, 13.167693, 14.167488, 15.167379}
+	universalVariance = []float64{0, 0.690, 1.338, 1.901, 2.358, 2.705, 2.954, 3.125, 3.238, 3.311, 3.356, 3.384, 3.401, 3.410, 3.416, 3.419, 3.421}
+)
+
+// Universal is Maurer's universal statistical test: the distance between repeated patterns, how well the sequence compresses
+func Universal(bits []byte) (float64, error) {
+	l := 0
+	for i, minBits := range universalBlockLengths {
+		if len(bits) >= minBits {
+			l = 6 + i
+		}
+	}
+	if l == 0 {
+		return 0, fmt.Errorf("%w: fewer than %v bits", ErrNotApplicable, universalBlockLengths[0])
+	}
+
+	q := 10 * (1 << l)
+	k := len(bits)/l - q
+	last := make([]int, 1<<l)
+	sum := 0.0
+	for i := 1; i <= q+k; i++ {
+		pattern := 0
+		for _, b := range bits[(i-1)*l : i*l] {
+			pattern = pattern<<1 | int(b)
+		}
+		if i > q {
+			sum += math.Log2(float64(i - last[pattern]))
+		}
+		last[pattern] = i
+	}
+
+	fn := sum / float64(k)
+	c := 0.7 - 0.8/float64(l) + (4+32/float64(l))*math.Pow(float64(k), -3/float64(l))/15
+	sigma := c * math.Sqrt(universalVariance[l]/float64(k))
+	return math.Erfc(math.Abs(fn-universalExpected[l]) / (math.Sqrt2 * sigma)), nil
+}
+
+// ApproximateEntropy compares the frequencies of overlapping patterns of m and m+1 bits
+func ApproximateEntropy(bits []byte, m int) float64 {
+	n := float64(len(bits))
+	apEn := phi(bits, m) - phi(bits, m+1)
+	chi2 := 2 * n * (math.Ln2 - apEn)
+	return stats.Igamc(math.Pow(2, float64(m-1)), chi2/2)
+}
+
+func phi(bits []byte, m int) float64 {
+	if m == 0 {
+		return 0
+	}
+	n := float64(len(bits))
+	sum := 0.0
+	for _, c := range patternCounts(bits, m) {
+		if c > 0 {
+			p := float64(c) / n
+			sum += p * math.Log(p)
+		}
+	}
+	return sum
+}
+
+// Serial compares the frequencies of overlapping patterns of m, m-1 and m-2 bits, it returns two p-values
+func Serial(bits []byte, m int) (float64, float64) {
+	psi := [3]float64{}
+	for i := range psi {
+		psi[i] = psiSquared(bits, m-i)
+	}
+	del1 := psi[0] - psi[1]
+	del2 := psi[0] - 2*psi[1] + psi[2]
+	return stats.Igamc(math.Pow(2, float64(m-2)), del1/2), stats.Igamc(math.Pow(2, float64(m-3)), del2/2)
+}
+
+func psiSquared(bits []byte, m int) float64 {
+	if m <= 0 {
+		return 0
+	}
+	n := float64(len(bits))
+	sum := 0.0
+	for _, c := range patternCounts(bits, m) {
+		sum += float64(c) * float64(c)
+	}
+	return math.Pow(2, float64(m))/n*sum - n
+}
+
+// patternCounts counts the overlapping patterns of m bits, wrapping around the end of the sequence
+func patternCounts(bits []byte, m int) []int {
+	n := len(bits)
+	counts := make([]int, 1<<m)
+	mask := 1<<m - 1
+	window := 0
+	for i := 0; i < m-1; i++ {
+		window = window<<1 | int(bits[i%n])
+	}
+	for i := range n {
+		j := i + m - 1
+		if j >= n {
+			j -= n
+		}
+		window = (window<<1 | int(bits[j])) & mask
+		counts[window]++
+	}
+	return counts
+}
+
+// LinearComplexity is the length of the shortest linear feedback shift register generating blocks of m bits
+func LinearComplexity(bits []byte, m int) (float64, error) {
+	blocks := len(bits) / m
+	if m < 1 || blocks < 1 {
+		return 0, fmt.Errorf("%w: fewer bits than the block length %v", ErrNotApplicable, m)
+	}
+
+	probabilities := []float64{0.010417, 0.03125, 0.125, 0.5, 0.25, 0.0625, 0.020833}
+	sign := 1.0
+	if m%2 == 1 {
+		sign = -1
+	}
+	mf := float64(m)
+	mean := mf/2 + (9-sign)/36 - (mf/3+2.0/9)/math.Pow(2, mf)
+
+	counts := make([]int, len(probabilities))
+	for i := range blocks {
+		t := sign*(float64(berlekampMassey(bits[i*m:(i+1)*m]))-mean) + 2.0/9
+		switch {
+		case t <= -2.5:
+			counts[0]++
+		case t <= -1.5:
+			counts[1]++
+		case t <= -0.5:
+			counts[2]++
+		case t <= 0.5:
+			counts[3]++
+		case t <= 1.5:
+			counts[4]++
+		case t <= 2.5:
+			counts[5]++
+		default:
+			counts[6]++
+		}
+	}
+
+	chi2 := 0.0
+	for i, p := range probabilities {
+		expected := float64(blocks) * p
+		chi2 += sq(float64(counts[i])-expected) / expected
+	}
+	return stats.Igamc(float64(len(probabilities)-1)/2, chi2/2), nil
+}
+
+// berlekampMassey is the linear complexity of s over GF(2). The polynomials are
+// packed in words, with s reversed so the discrepancy is a dot product of words.
+func berlekampMassey(s []byte) int {
+	n := len(s)
+	words := n/64 + 1
+	// Bit k of reversed is s[n-1-k], the bits past n are 0 for the terms before s[0]
+	reversed := make([]uint64, 2*words+1)
+	for k := range n {
+		reversed[k/64] |= uint64(s[n-1-k]) << (k % 64)
+	}
+
+	c := make([]uint64, words)
+	b := make([]uint64, words)
+	t := make([]uint64, words)
+	c[0], b[0] = 1, 1
+	l, m := 0, -1
+	for i := range n {
+		// d is the sum of c[j] s[i-j], the degree of c is at most l
+		d := uint64(0)
+		for w := 0; w <= l/64; w++ {
+			d ^= c[w] & bitsAt(reversed, n-1-i+64*w)
+		}
+		if bits.OnesCount64(d)%2 == 0 {
+			continue
+		}
+
+		copy(t, c)
+		xorShifted(c, b, i-m)
+		if 2*l <= i {
+			l = i + 1 - l
+			m = i
+			copy(b, t)
+		}
+	}
+	return l
+}
+
+// bitsAt returns the 64 bits of words from bit offset on
+func bitsAt(words []uint64, offset int) uint64 {
+	q, r := offset/64, uint(offset%64)
+	v := words[q] >> r
+	if r > 0 && q+1 < len(words) {
+		v |= words[q+1] << (64 - r)
+	}
+	return v
+}
+
+// xorShifted adds src shifted left by shift bits to dst, dropping the bits past its end
+func xorShifted(dst []uint64, src []uint64, shift int) {
+	q, r := shift/64, uint(shift%64)
+	for i := len(dst) - 1; i >= q; i-- {
+		v := src[i-q] << r
+		if r > 0 && i-q-1 >= 0 {
+			v |= src[i-q-1] >> (64 - r)
+		}
+		dst[i] ^= v
+	}
+}
+
+// excursionStates are the states of the random excursions test and variantStates those of its variant
+var (
+	excursionStates = []int{-4, -3, -2, -1, 1, 2, 3, 4}
+	variantStates   = []int{-9, -8, -7, -6, -5, -4, -3, -2, -1, 1, 2, 3, 4, 5, 6, 7, 8, 9}
+)
+
+// RandomExcursions counts the visits of the random walk of the bits to the states -4 to 4 in every cycle
+// between two returns to zero. It returns a p-value per state, in the order of excursionStates.
+func RandomExcursions(bits []byte) ([]float64, error) {
+	return randomExcursions(bits, minCycles(len(bits)))
+}
+
+func randomExcursions(bits []byte, minimum int) ([]float64, error) {
+	// visits[k][x] is the number of cycles that visit state x-4 k times, 5 or more in the last row
+	var visits [6][9]int
+	var cycle [9]int
+	cycles := 0
+	s := 0
+	endCycle := func() {
+		for x := range cycle {
+			visits[min(cycle[x], 5)][x]++
+		}
+		cycle = [9]int{}
+		cycles++
+	}
+	for _, b := range bits {
+		s += 2*int(b) - 1
+		if s == 0 {
+			endCycle()
+		} else if s >= -4 && s <= 4 {
+			cycle[s+4]++
+		}
+	}
+	if s != 0 {
+		endCycle()
+	}
+	if cycles < minimum {
+		return nil, fmt.Errorf("%w: %v cycles, %v are required", ErrNotApplicable, cycles, minimum)
+	}
+
+	pValues := make([]float64, len(excursionStates))
+	for i, x := range excursionStates {
+		chi2 := 0.0
+		for k := range 6 {
+			expected := float64(cycles) * excursionProbability(x, k)
+			chi2 += sq(float64(visits[k][x+4])-expected) / expected
+		}
+		pValues[i] = stats.Igamc(2.5, chi2/2)
+	}
+	return pValues, nil
+}
+
+// excursionProbability is the probability that a cycle visits state x k times, 5 or more for k = 5
+func excursionProbability(x int, k int) float64 {
+	a := 1 / (2 * math.Abs(float64(x)))
+	switch {
+	case k == 0:
+		return 1 - a
+	case k < 5:
+		return a * a * math.Pow(1-a, float64(k-1))
+	default:
+		return a * math.Pow(1-a, 4)
+	}
+}
+
+// RandomExcursionsVariant counts the visits of the random walk of the bits to the states -9 to 9 in total.
+// It returns a p-value per state, in the order of variantStates.
+func RandomExcursionsVariant(bits []byte) ([]float64, error) {
+	return randomExcursionsVariant(bits, minCycles(len(bits)))
+}
+
+func randomExcursionsVariant(bits []byte, minimum int) ([]float64, error) {
+	var visits [19]int
+	cycles := 0
+	s := 0
+	for _, b := range bits {
+		s += 2*int(b) - 1
+		if s == 0 {
+			cycles++
+		} else if s >= -9 && s <= 9 {
+			visits[s+9]++
+		}
+	}
+	if s != 0 {
+		cycles++
+	}
+	if cycles < minimum {
+		return nil, fmt.Errorf("%w: %v cycles, %v are required", ErrNotApplicable, cycles, minimum)
+	}
+
+	j := float64(cycles)
+	pValues := make([]float64, len(variantStates))
+	for i, x := range variantStates {
+		d := math.Abs(float64(visits[x+9]) - j)
+		pValues[i] = math.Erfc(d / math.Sqrt(2*j*(4*math.Abs(float64(x))-2)))
+	}
+	return pValues, nil
+}
+
+// minCycles is the least number of cycles of the random excursion tests, max(0.005 sqrt(n), 500)
+func minCycles(n int) int {
+	return max(int(0.005*math.Sqrt(float64(n))), 500)
+}
+
+// normal is the cumulative distribution function of the standard normal distribution
+func normal(x float64) float64 {
+	return 0.5 * math.Erfc(-x/math.Sqrt2)
+}
+
+func sq(x float64) float64 {
+	return x * x
+}
+
+func abs(x int) int {
+	if x < 0 {
+		return -x
+	}
+	return x
+}
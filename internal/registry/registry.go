@@ -0,0 +1,146 @@
+package registry
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/fasttrack-solutions/random/internal/storage"
+)
+
+const (
+	// ModeOff does not track drawn sequences.
+	ModeOff = "off"
+	// ModeReject rejects a second draw of the same namespace and sequence.
+	ModeReject = "reject"
+	// ModeReplay returns the originally recorded result for a second draw,
+	// flagged as replayed.
+	ModeReplay = "replay"
+)
+
+// keyPrefix is the storage prefix of the draws, followed by "<namespace>/<sequence>"
+const keyPrefix = "draw/"
+
+var (
+	// ErrAlreadyDrawn is returned in reject mode for a sequence that was drawn before
+	ErrAlreadyDrawn = errors.New("sequence was already drawn")
+	// ErrProbabilitiesChanged is returned in replay mode when a sequence is drawn again with other probabilities
+	ErrProbabilitiesChanged = errors.New("sequence was already drawn with different probabilities")
+)
+
+// Draw is the recorded outcome of a deterministic draw, KeyID and Epoch identify the seed it was drawn with
+type Draw struct {
+	Namespace     string    `json:"namespace"`
+	Sequence      int64     `json:"sequence"`
+	Probabilities []float64 `json:"probabilities"`
+	Number        int64     `json:"number"`
+	KeyID         string    `json:"keyId,omitempty"`
+	Epoch         int64     `json:"epoch,omitempty"`
+	DrawnAt       time.Time `json:"drawnAt"`
+}
+
+// ValidateMode checks that the mode is one of the known registry modes
+func ValidateMode(mode string) error {
+	if mode != ModeOff && mode != ModeReject && mode != ModeReplay {
+		return fmt.Errorf("invalid single-use mode %q; valid modes are %q, %q and %q", mode, ModeOff, ModeReject, ModeReplay)
+	}
+	return nil
+}
+
+// Registry remembers which (namespace, sequence) pairs have been drawn
+// to enforce single-use of client chosen sequences.
+type Registry struct {
+	mode  string
+	store storage.Store
+}
+
+// New creates a registry keeping the draws in store, the store is not used when mode is off
+func New(mode string, store storage.Store) (*Registry, error) {
+	err := ValidateMode(mode)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Registry{
+		mode:  mode,
+		store: store,
+	}, nil
+}
+
+// Enabled reports whether drawn sequences are tracked
+func (r *Registry) Enabled() bool {
+	return r.mode != ModeOff
+}
+
+// Record registers the draw and returns the result to respond with.
+// The returned bool is true when the result is a replay of an earlier draw.
+func (r *Registry) Record(ctx context.Context, draw Draw) (Draw, bool, error) {
+	if !r.Enabled() {
+		return draw, false, nil
+	}
+
+	recorded := draw
+	fresh := true
+	err := r.store.Update(ctx, func(tx storage.Tx) error {
+		recorded, fresh = draw, true
+		key := key(draw.Namespace, draw.Sequence)
+
+		v, errGet := tx.Get(key)
+		if errGet == nil {
+			fresh = false
+			recorded = Draw{}
+			return json.Unmarshal(v, &recorded)
+		} else if !errors.Is(errGet, storage.ErrNotFound) {
+			return errGet
+		}
+
+		v, errMarshal := json.Marshal(draw)
+		if errMarshal != nil {
+			return errMarshal
+		}
+		return tx.Put(key, v, 0)
+	})
+	if err != nil {
+		return Draw{}, false, fmt.Errorf("failed to record draw: %w", err)
+	} else if fresh {
+		return recorded, false, nil
+	}
+
+	if r.mode == ModeReject {
+		return Draw{}, false, ErrAlreadyDrawn
+	} else if !slices.Equal(recorded.Probabilities, draw.Probabilities) {
+		return Draw{}, false, ErrProbabilitiesChanged
+	}
+
+	return recorded, true, nil
+}
+
+// DrawnWith reports whether the sequence of the namespace was recorded as drawn
+// with the key, a request naming that key replays the draw
+func (r *Registry) DrawnWith(ctx context.Context, namespace string, sequence int64, keyID string) (bool, error) {
+	if !r.Enabled() {
+		return false, nil
+	}
+
+	var recorded Draw
+	err := r.store.View(ctx, func(tx storage.Tx) error {
+		v, errGet := tx.Get(key(namespace, sequence))
+		if errGet != nil {
+			return errGet
+		}
+		return json.Unmarshal(v, &recorded)
+	})
+	if errors.Is(err, storage.ErrNotFound) {
+		return false, nil
+	} else if err != nil {
+		return false, fmt.Errorf("failed to look up draw: %w", err)
+	}
+	return recorded.KeyID == keyID, nil
+}
+
+func key(namespace string, sequence int64) string {
+	return fmt.Sprintf("%s%s/%020d", keyPrefix, namespace, sequence)
+}
@@ -0,0 +1,117 @@
+package registry
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/fasttrack-solutions/random/internal/storage"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Registry(t *testing.T) {
+	ctx := context.Background()
+	draw := Draw{
+		Namespace:     "campaign",
+		Sequence:      7,
+		Probabilities: []float64{0.5, 0.5},
+		Number:        1,
+		KeyID:         "2025-01",
+		Epoch:         1,
+		DrawnAt:       time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
+	}
+	// Drawn again after a seed rotation
+	again := draw
+	again.Number = 0
+	again.KeyID, again.Epoch = "2025-02", 2
+	again.DrawnAt = draw.DrawnAt.Add(time.Minute)
+
+	// off
+	r, err := New(ModeOff, storage.NewMemoryStore())
+	assert.Nil(t, err)
+	for i := 0; i < 2; i++ {
+		recorded, replayed, errRecord := r.Record(ctx, draw)
+		assert.Nil(t, errRecord)
+		assert.False(t, replayed)
+		assert.Equal(t, draw, recorded)
+	}
+
+	// reject
+	r, err = New(ModeReject, storage.NewMemoryStore())
+	assert.Nil(t, err)
+	_, replayed, err := r.Record(ctx, draw)
+	assert.Nil(t, err)
+	assert.False(t, replayed)
+	_, _, err = r.Record(ctx, again)
+	assert.ErrorIs(t, err, ErrAlreadyDrawn)
+
+	// replay
+	r, err = New(ModeReplay, storage.NewMemoryStore())
+	assert.Nil(t, err)
+	_, _, err = r.Record(ctx, draw)
+	assert.Nil(t, err)
+	recorded, replayed, err := r.Record(ctx, again)
+	assert.Nil(t, err)
+	assert.True(t, replayed)
+	assert.Equal(t, draw, recorded)
+
+	// Only the recorded key replays the sequence
+	drawnWith, err := r.DrawnWith(ctx, "campaign", 7, "2025-01")
+	assert.Nil(t, err)
+	assert.True(t, drawnWith)
+	drawnWith, err = r.DrawnWith(ctx, "campaign", 7, "2025-02")
+	assert.Nil(t, err)
+	assert.False(t, drawnWith)
+	drawnWith, err = r.DrawnWith(ctx, "campaign", 8, "2025-01")
+	assert.Nil(t, err)
+	assert.False(t, drawnWith)
+
+	again.Probabilities = []float64{0.1, 0.9}
+	_, _, err = r.Record(ctx, again)
+	assert.ErrorIs(t, err, ErrProbabilitiesChanged)
+
+	_, err = New("other", storage.NewMemoryStore())
+	assert.NotNil(t, err)
+}
+
+func Test_Registry_Persistent(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "state.db")
+	draw := Draw{
+		Namespace:     "campaign",
+		Sequence:      3,
+		Probabilities: []float64{0.2, 0.8},
+		Number:        1,
+		DrawnAt:       time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
+	}
+
+	store, err := storage.OpenBolt(path)
+	assert.Nil(t, err)
+	r, err := New(ModeReplay, store)
+	assert.Nil(t, err)
+	_, replayed, err := r.Record(ctx, draw)
+	assert.Nil(t, err)
+	assert.False(t, replayed)
+	assert.Nil(t, store.Close())
+
+	// The recorded draw survives a restart
+	store, err = storage.OpenBolt(path)
+	assert.Nil(t, err)
+	defer store.Close()
+	r, err = New(ModeReplay, store)
+	assert.Nil(t, err)
+
+	again := draw
+	again.Number = 0
+	recorded, replayed, err := r.Record(ctx, again)
+	assert.Nil(t, err)
+	assert.True(t, replayed)
+	assert.Equal(t, draw, recorded)
+
+	// Same sequence in another namespace is independent
+	again.Namespace = "other"
+	_, replayed, err = r.Record(ctx, again)
+	assert.Nil(t, err)
+	assert.False(t, replayed)
+}
@@ -0,0 +1,125 @@
+package sequence
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+	"regexp"
+	"strings"
+
+	"github.com/fasttrack-solutions/random/internal/storage"
+)
+
+const (
+	// ModeClient lets callers choose the sequence number of a deterministic draw.
+	ModeClient = "client"
+	// ModeServer makes the server allocate sequence numbers; caller-supplied
+	// sequences are only accepted from replay clients.
+	ModeServer = "server"
+)
+
+// keyPrefix is the storage prefix of the counters, followed by the namespace
+const keyPrefix = "sequence/"
+
+var namespacePattern = regexp.MustCompile(`^[A-Za-z0-9._-]{1,64}$`)
+
+// ValidateMode checks that the mode is one of the known sequence modes
+func ValidateMode(mode string) error {
+	if mode != ModeClient && mode != ModeServer {
+		return fmt.Errorf("invalid sequence mode %q; valid modes are %q and %q", mode, ModeClient, ModeServer)
+	}
+	return nil
+}
+
+// ValidateNamespace checks that a namespace is 1-64 characters of [A-Za-z0-9._-]
+func ValidateNamespace(namespace string) error {
+	if len(namespace) == 0 {
+		return errors.New("namespace must not be empty")
+	} else if !namespacePattern.MatchString(namespace) {
+		return errors.New("namespace must be 1-64 characters of a-z, A-Z, 0-9, '.', '_' or '-'")
+	}
+	return nil
+}
+
+// Allocator hands out the next sequence number of a namespace (campaign).
+// The counter is incremented and stored in the same transaction, so a number
+// is never handed out twice, also not across restarts or instances sharing a store.
+type Allocator struct {
+	store storage.Store
+}
+
+// NewAllocator creates an allocator keeping its counters in store
+func NewAllocator(store storage.Store) *Allocator {
+	return &Allocator{
+		store: store,
+	}
+}
+
+// Next returns a sequence number of namespace that has not been returned before
+func (a *Allocator) Next(ctx context.Context, namespace string) (int64, error) {
+	err := ValidateNamespace(namespace)
+	if err != nil {
+		return 0, err
+	}
+
+	var next int64
+	err = a.store.Update(ctx, func(tx storage.Tx) error {
+		key := keyPrefix + namespace
+
+		next = 0
+		v, errGet := tx.Get(key)
+		if errGet == nil && len(v) == 8 {
+			next = int64(binary.BigEndian.Uint64(v)) // #nosec G115 -- only non-negative values are stored
+		} else if errGet != nil && !errors.Is(errGet, storage.ErrNotFound) {
+			return errGet
+		}
+		if next == math.MaxInt64 {
+			return fmt.Errorf("sequence of namespace %s is exhausted", namespace)
+		}
+
+		var buf [8]byte
+		binary.BigEndian.PutUint64(buf[:], uint64(next+1))
+		return tx.Put(key, buf[:], 0)
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return next, nil
+}
+
+// ReplayClients holds the tokens of clients that may still choose the sequence
+// number in server mode, i.e. to replay earlier draws for an audit.
+type ReplayClients struct {
+	tokens [][]byte
+}
+
+// NewReplayClients creates the set from a comma separated list of tokens
+func NewReplayClients(tokens string) *ReplayClients {
+	rc := &ReplayClients{}
+	for _, token := range strings.Split(tokens, ",") {
+		token = strings.TrimSpace(token)
+		if len(token) > 0 {
+			rc.tokens = append(rc.tokens, []byte(token))
+		}
+	}
+	return rc
+}
+
+// Allowed reports whether the token belongs to a replay client
+func (rc *ReplayClients) Allowed(token string) bool {
+	if len(token) == 0 {
+		return false
+	}
+
+	allowed := false
+	for _, t := range rc.tokens {
+		if subtle.ConstantTimeCompare(t, []byte(token)) == 1 {
+			allowed = true
+		}
+	}
+	return allowed
+}
@@ -0,0 +1,90 @@
+package sequence
+
+import (
+	"context"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/fasttrack-solutions/random/internal/storage"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_ValidateNamespace(t *testing.T) {
+	assert.Nil(t, ValidateNamespace("campaign-42_v1.0"))
+	assert.EqualError(t, ValidateNamespace(""), "namespace must not be empty")
+	assert.NotNil(t, ValidateNamespace("campaign 42"))
+	assert.NotNil(t, ValidateNamespace("campaign/42"))
+}
+
+func Test_ValidateMode(t *testing.T) {
+	assert.Nil(t, ValidateMode(ModeClient))
+	assert.Nil(t, ValidateMode(ModeServer))
+	assert.NotNil(t, ValidateMode("other"))
+}
+
+func Test_Allocator(t *testing.T) {
+	ctx := context.Background()
+	allocator := NewAllocator(storage.NewMemoryStore())
+
+	for i := int64(0); i < 3; i++ {
+		seq, err := allocator.Next(ctx, "a")
+		assert.Nil(t, err)
+		assert.Equal(t, i, seq)
+	}
+
+	seq, err := allocator.Next(ctx, "b")
+	assert.Nil(t, err)
+	assert.Equal(t, int64(0), seq)
+
+	_, err = allocator.Next(ctx, "")
+	assert.EqualError(t, err, "namespace must not be empty")
+}
+
+func Test_Allocator_Persistent(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "state.db")
+
+	store, err := storage.OpenBolt(path)
+	assert.Nil(t, err)
+	allocator := NewAllocator(store)
+
+	// Concurrent callers never receive the same sequence
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	seen := map[int64]bool{}
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			seq, errNext := allocator.Next(ctx, "campaign")
+			assert.Nil(t, errNext)
+			mu.Lock()
+			seen[seq] = true
+			mu.Unlock()
+		}()
+	}
+	wg.Wait()
+	assert.Len(t, seen, 20)
+	assert.Nil(t, store.Close())
+
+	// The counter survives a restart
+	store, err = storage.OpenBolt(path)
+	assert.Nil(t, err)
+	defer store.Close()
+
+	seq, err := NewAllocator(store).Next(ctx, "campaign")
+	assert.Nil(t, err)
+	assert.Equal(t, int64(20), seq)
+}
+
+func Test_ReplayClients(t *testing.T) {
+	replayClients := NewReplayClients("alpha, beta,,")
+
+	assert.True(t, replayClients.Allowed("alpha"))
+	assert.True(t, replayClients.Allowed("beta"))
+	assert.False(t, replayClients.Allowed("gamma"))
+	assert.False(t, replayClients.Allowed(""))
+
+	assert.False(t, NewReplayClients("").Allowed(""))
+}
@@ -0,0 +1,127 @@
+// Package shamir implements Shamir's secret sharing over GF(256). A secret is
+// split into n shares of which any k recover it, while fewer than k reveal
+// nothing about it.
+package shamir
+
+import (
+	"crypto/rand"
+	"errors"
+	"fmt"
+)
+
+var (
+	// ErrTooFewShares is returned when combining fewer than two shares
+	ErrTooFewShares = errors.New("at least two shares are required")
+	// ErrDuplicateShare is returned when two shares have the same x coordinate
+	ErrDuplicateShare = errors.New("duplicate share")
+)
+
+// Split splits the secret into n shares with a threshold of k. Every share
+// is the x coordinate followed by one byte per byte of the secret.
+func Split(secret []byte, n int, k int) ([][]byte, error) {
+	if len(secret) == 0 {
+		return nil, errors.New("secret must not be empty")
+	} else if k < 2 {
+		return nil, errors.New("threshold must be at least 2")
+	} else if n < k {
+		return nil, errors.New("number of shares must be at least the threshold")
+	} else if n > 255 {
+		return nil, errors.New("number of shares must be at most 255")
+	}
+
+	shares := make([][]byte, n)
+	for i := range shares {
+		shares[i] = make([]byte, 1+len(secret))
+		shares[i][0] = byte(i + 1)
+	}
+
+	// One random polynomial of degree k-1 per byte, its constant term is the byte
+	coefficients := make([]byte, k)
+	for b, s := range secret {
+		_, err := rand.Read(coefficients[1:])
+		if err != nil {
+			return nil, err
+		}
+		coefficients[0] = s
+
+		for _, share := range shares {
+			share[1+b] = evaluate(coefficients, share[0])
+		}
+	}
+	clear(coefficients)
+	return shares, nil
+}
+
+// Combine recovers the secret from shares created by Split. Any k shares
+// recover it; fewer shares return a value unrelated to the secret, which
+// callers detect by checking the result.
+func Combine(shares [][]byte) ([]byte, error) {
+	if len(shares) < 2 {
+		return nil, ErrTooFewShares
+	}
+
+	size := len(shares[0])
+	if size < 2 {
+		return nil, errors.New("share is too short")
+	}
+	xs := make([]byte, len(shares))
+	for i, share := range shares {
+		if len(share) != size {
+			return nil, errors.New("shares must have the same length")
+		} else if share[0] == 0 {
+			return nil, errors.New("share has an invalid x coordinate")
+		}
+		for j := range i {
+			if xs[j] == share[0] {
+				return nil, fmt.Errorf("%w %v", ErrDuplicateShare, share[0])
+			}
+		}
+		xs[i] = share[0]
+	}
+
+	// Lagrange interpolation at x = 0
+	secret := make([]byte, size-1)
+	for i, share := range shares {
+		basis := byte(1)
+		for j, x := range xs {
+			if i != j {
+				basis = mul(basis, div(x, x^xs[i]))
+			}
+		}
+		for b := range secret {
+			secret[b] ^= mul(share[1+b], basis)
+		}
+	}
+	return secret, nil
+}
+
+// evaluate evaluates the polynomial at x with Horner's method
+func evaluate(coefficients []byte, x byte) byte {
+	y := byte(0)
+	for i := len(coefficients) - 1; i >= 0; i-- {
+		y = mul(y, x) ^ coefficients[i]
+	}
+	return y
+}
+
+// mul multiplies in GF(256) with the AES polynomial x^8 + x^4 + x^3 + x + 1,
+// without branches or tables that depend on the secret
+func mul(a byte, b byte) byte {
+	var p byte
+	for range 8 {
+		p ^= a & -(b & 1)
+		carry := -(a >> 7)
+		a = a<<1 ^ 0x1b&carry
+		b >>= 1
+	}
+	return p
+}
+
+// div divides a by b, which must not be zero. The inverse of b is b^254.
+func div(a byte, b byte) byte {
+	inverse := b
+	for range 6 {
+		inverse = mul(mul(inverse, inverse), b)
+	}
+	return mul(a, mul(inverse, inverse))
+}
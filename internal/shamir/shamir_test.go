@@ -0,0 +1,66 @@
+package shamir
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_mul(t *testing.T) {
+	// Examples of FIPS 197
+	assert.Equal(t, byte(0xc1), mul(0x57, 0x83))
+	assert.Equal(t, byte(0xfe), mul(0x57, 0x13))
+	// 0x53 and 0xca are inverses
+	assert.Equal(t, byte(0x01), mul(0x53, 0xca))
+	assert.Equal(t, byte(0xca), div(1, 0x53))
+
+	for a := 1; a < 256; a++ {
+		assert.Equal(t, byte(1), mul(byte(a), div(1, byte(a))))
+	}
+}
+
+func Test_Split_Combine(t *testing.T) {
+	secret := []byte("0123456789abcdef0123456789abcdef")
+	shares, err := Split(secret, 5, 3)
+	assert.Nil(t, err)
+	assert.Len(t, shares, 5)
+
+	// Every subset of three shares recovers the secret
+	for i := range shares {
+		for j := i + 1; j < len(shares); j++ {
+			for k := j + 1; k < len(shares); k++ {
+				combined, errCombine := Combine([][]byte{shares[i], shares[j], shares[k]})
+				assert.Nil(t, errCombine)
+				assert.Equal(t, secret, combined)
+			}
+		}
+	}
+
+	combined, err := Combine(shares)
+	assert.Nil(t, err)
+	assert.Equal(t, secret, combined)
+
+	// Two shares are not enough
+	combined, err = Combine(shares[:2])
+	assert.Nil(t, err)
+	assert.False(t, bytes.Equal(secret, combined))
+
+	_, err = Combine(shares[:1])
+	assert.ErrorIs(t, err, ErrTooFewShares)
+	_, err = Combine([][]byte{shares[0], shares[0], shares[1]})
+	assert.ErrorIs(t, err, ErrDuplicateShare)
+	_, err = Combine([][]byte{shares[0], shares[1][:10]})
+	assert.EqualError(t, err, "shares must have the same length")
+}
+
+func Test_Split_Invalid(t *testing.T) {
+	_, err := Split(nil, 3, 2)
+	assert.EqualError(t, err, "secret must not be empty")
+	_, err = Split([]byte("secret"), 3, 1)
+	assert.EqualError(t, err, "threshold must be at least 2")
+	_, err = Split([]byte("secret"), 2, 3)
+	assert.EqualError(t, err, "number of shares must be at least the threshold")
+	_, err = Split([]byte("secret"), 256, 3)
+	assert.EqualError(t, err, "number of shares must be at most 255")
+}
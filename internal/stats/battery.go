@@ -0,0 +1,506 @@
+// Package stats runs statistical tests on the outcomes of a generator: a
+// battery accumulates the outcomes as they are drawn, in constant memory apart
+// from the sample kept for the Kolmogorov-Smirnov test, and reports a p-value
+// per test.
+package stats
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"slices"
+)
+
+// DefaultAlpha is the significance level below which a p-value fails a test
+const DefaultAlpha = 0.01
+
+const (
+	// floatBins is the number of equal bins of the chi-square test of floats
+	floatBins = 100
+	// maxCategories is the most categories of the chi-square test, wider integer ranges are bucketed
+	maxCategories = 1000
+	// pokerCategories is the most categories a poker hand is drawn from
+	pokerCategories = 10
+	// handSize is the number of consecutive outcomes of a poker hand
+	handSize = 5
+	// maxGap is the longest gap counted on its own, longer gaps are counted together
+	maxGap = 1024
+	// maxKSSamples is the number of outcomes kept for the Kolmogorov-Smirnov test
+	maxKSSamples = 1 << 20
+	// minExpected is the least expected count of a chi-square cell, smaller cells are merged
+	minExpected = 5
+)
+
+// Battery accumulates outcomes and runs the chi-square, Kolmogorov-Smirnov,
+// runs, serial correlation, gap and poker tests on them. Every outcome falls in
+// a category of known probability: a bin of floats, a bucket of integers or a
+// prize index.
+type Battery struct {
+	continuous    bool
+	category      func(v float64) (int, bool)
+	probabilities []float64
+	// bounds are the lowest and highest outcome of every category, the highest is excluded for floats
+	bounds [][2]float64
+	// center is the expected mean, the serial correlation sums are kept around it to keep their precision
+	center float64
+	// split is the first category of the high half of the runs and gap tests
+	split int
+	// poker maps the categories onto at most pokerCategories groups
+	poker            []int
+	pokerProbability []float64
+
+	n          int64
+	outOfRange int64
+	counts     []int64
+	samples    []float64
+
+	// runs test
+	lows    int64
+	runs    int64
+	prevLow bool
+
+	// serial correlation test
+	first  float64
+	prev   float64
+	sum    float64
+	sumSq  float64
+	sumLag float64
+
+	// gap test
+	gapStarted bool
+	gap        int64
+	gaps       []int64
+
+	// poker test
+	hand     [handSize]int
+	handLen  int
+	distinct [handSize + 1]int64
+}
+
+// NewUniformFloat64 creates a battery for floats uniform in [0, 1]
+func NewUniformFloat64() *Battery {
+	probabilities := make([]float64, floatBins)
+	bounds := make([][2]float64, floatBins)
+	for i := range probabilities {
+		probabilities[i] = 1.0 / floatBins
+		bounds[i] = [2]float64{float64(i) / floatBins, float64(i+1) / floatBins}
+	}
+
+	b := newBattery(probabilities, bounds, 0.5, func(v float64) (int, bool) {
+		if v < 0 || v > 1 || math.IsNaN(v) {
+			return 0, false
+		}
+		// 1 itself is drawn when a float64 rounds up, count it in the last bin
+		return min(int(v*floatBins), floatBins-1), true
+	})
+	b.continuous = true
+	b.samples = make([]float64, 0, 1024)
+	return b
+}
+
+// NewUniformInt64 creates a battery for integers uniform from minimum to maximum, both included
+func NewUniformInt64(minimum int64, maximum int64) (*Battery, error) {
+	if maximum < minimum {
+		return nil, errors.New("max must be at least min")
+	}
+
+	size := uint64(maximum-minimum) + 1 // #nosec G115 -- maximum is at least minimum
+	buckets := min(size, maxCategories)
+	probabilities := make([]float64, buckets)
+	bounds := make([][2]float64, buckets)
+	for i := range probabilities {
+		// Bucket i holds the values v-min of which v*buckets/size is i
+		lo := ceilDiv(uint64(i)*size, buckets)   // #nosec G115 -- i is at least 0
+		hi := ceilDiv(uint64(i+1)*size, buckets) // #nosec G115 -- i is at least 0
+		probabilities[i] = float64(hi-lo) / float64(size)
+		bounds[i] = [2]float64{float64(minimum) + float64(lo), float64(minimum) + float64(hi-1)}
+	}
+
+	center := float64(minimum) + float64(maximum-minimum)/2
+	return newBattery(probabilities, bounds, center, func(v float64) (int, bool) {
+		if v < float64(minimum) || v > float64(maximum) || v != math.Trunc(v) {
+			return 0, false
+		}
+		return int(uint64(v-float64(minimum)) * buckets / size), true // #nosec G115 -- checked above
+	}), nil
+}
+
+// NewDiscrete creates a battery for prize indexes drawn with probabilities
+func NewDiscrete(probabilities []float64) (*Battery, error) {
+	if len(probabilities) == 0 {
+		return nil, errors.New("probabilities must not be empty")
+	}
+	sum := 0.0
+	center := 0.0
+	bounds := make([][2]float64, len(probabilities))
+	for i, p := range probabilities {
+		if p < 0 || p > 1 || math.IsNaN(p) {
+			return nil, fmt.Errorf("invalid probability %v", p)
+		}
+		sum += p
+		center += float64(i) * p
+		bounds[i] = [2]float64{float64(i), float64(i)}
+	}
+	if math.Abs(sum-1) > 1e-9 {
+		return nil, fmt.Errorf("sum of probabilities %v; must be 1", sum)
+	}
+
+	return newBattery(slices.Clone(probabilities), bounds, center, func(v float64) (int, bool) {
+		if v < 0 || v >= float64(len(probabilities)) || v != math.Trunc(v) {
+			return 0, false
+		}
+		return int(v), true
+	}), nil
+}
+
+func newBattery(probabilities []float64, bounds [][2]float64, center float64, category func(v float64) (int, bool)) *Battery {
+	b := &Battery{
+		category:      category,
+		probabilities: probabilities,
+		bounds:        bounds,
+		center:        center,
+		counts:        make([]int64, len(probabilities)),
+		gaps:          make([]int64, maxGap+1),
+	}
+
+	// Split the categories where the cumulative probability is closest to one half
+	cumulative := 0.0
+	best := math.Inf(1)
+	for i := 0; i < len(probabilities)-1; i++ {
+		cumulative += probabilities[i]
+		if d := math.Abs(cumulative - 0.5); d < best {
+			best = d
+			b.split = i + 1
+		}
+	}
+
+	groups := min(len(probabilities), pokerCategories)
+	b.poker = make([]int, len(probabilities))
+	b.pokerProbability = make([]float64, groups)
+	for i, p := range probabilities {
+		b.poker[i] = i * groups / len(probabilities)
+		b.pokerProbability[b.poker[i]] += p
+	}
+	return b
+}
+
+// Add accumulates the next outcome
+func (b *Battery) Add(v float64) {
+	if b.n == 0 {
+		b.first = v - b.center
+	} else {
+		b.sumLag += b.prev * (v - b.center)
+	}
+	b.n++
+	b.prev = v - b.center
+	b.sum += b.prev
+	b.sumSq += b.prev * b.prev
+
+	if b.continuous && len(b.samples) < maxKSSamples {
+		b.samples = append(b.samples, v)
+	}
+
+	c, ok := b.category(v)
+	if !ok {
+		b.outOfRange++
+		return
+	}
+	b.counts[c]++
+
+	low := c < b.split
+	if low {
+		b.lows++
+	}
+	if b.runs == 0 || low != b.prevLow {
+		b.runs++
+	}
+	b.prevLow = low
+
+	if low {
+		if b.gapStarted {
+			b.gaps[min(b.gap, maxGap)]++
+		}
+		b.gapStarted = true
+		b.gap = 0
+	} else {
+		b.gap++
+	}
+
+	b.hand[b.handLen] = b.poker[c]
+	b.handLen++
+	if b.handLen == handSize {
+		distinct := 0
+		for i, g := range b.hand {
+			if !slices.Contains(b.hand[:i], g) {
+				distinct++
+			}
+		}
+		b.distinct[distinct]++
+		b.handLen = 0
+	}
+}
+
+// Samples is the number of outcomes added
+func (b *Battery) Samples() int64 {
+	return b.n
+}
+
+// Report runs the tests on the outcomes added so far, a test fails below alpha
+func (b *Battery) Report(alpha float64) Report {
+	r := Report{
+		Samples:   b.n,
+		Alpha:     alpha,
+		Results:   []Result{b.chiSquare()},
+		Histogram: b.histogram(),
+	}
+	if b.continuous {
+		r.Results = append(r.Results, b.kolmogorovSmirnov())
+	}
+	r.Results = append(r.Results, b.runsTest(), b.serialCorrelation(), b.gapTest(), b.pokerTest())
+
+	for i := range r.Results {
+		res := &r.Results[i]
+		res.Pass = res.Skipped || res.PValue >= alpha
+	}
+	return r
+}
+
+func (b *Battery) chiSquare() Result {
+	res := Result{Name: "chi-square"}
+	if b.outOfRange > 0 {
+		res.Note = fmt.Sprintf("%v outcomes outside of the expected range", b.outOfRange)
+		return res
+	}
+
+	expected := make([]float64, len(b.probabilities))
+	for i, p := range b.probabilities {
+		expected[i] = p * float64(b.n)
+	}
+	res.Statistic, res.DF, res.PValue, res.Skipped = chiSquare(b.counts, expected)
+	if len(b.counts) < len(b.probabilities) || len(b.probabilities) == maxCategories {
+		res.Note = fmt.Sprintf("%v buckets", len(b.probabilities))
+	}
+	return b.skip(res)
+}
+
+func (b *Battery) kolmogorovSmirnov() Result {
+	res := Result{Name: "kolmogorov-smirnov"}
+	if len(b.samples) == 0 {
+		res.Skipped = true
+		return b.skip(res)
+	}
+
+	sorted := slices.Clone(b.samples)
+	slices.Sort(sorted)
+	n := float64(len(sorted))
+	d := 0.0
+	for i, x := range sorted {
+		d = math.Max(d, math.Max(float64(i+1)/n-x, x-float64(i)/n))
+	}
+
+	res.Statistic = d
+	res.PValue = KolmogorovPValue((math.Sqrt(n) + 0.12 + 0.11/math.Sqrt(n)) * d)
+	if int64(len(sorted)) < b.n {
+		res.Note = fmt.Sprintf("first %v outcomes", len(sorted))
+	}
+	return res
+}
+
+// runsTest counts the runs of outcomes in the low and the high half, the Wald-Wolfowitz test
+func (b *Battery) runsTest() Result {
+	res := Result{Name: "runs"}
+	n := float64(b.n - b.outOfRange)
+	n1 := float64(b.lows)
+	n2 := n - n1
+	if n1 == 0 || n2 == 0 || n < 2 {
+		res.Skipped = true
+		return b.skip(res)
+	}
+
+	mean := 2*n1*n2/n + 1
+	variance := 2 * n1 * n2 * (2*n1*n2 - n) / (n * n * (n - 1))
+	if variance <= 0 {
+		res.Skipped = true
+		return b.skip(res)
+	}
+	res.Statistic = (float64(b.runs) - mean) / math.Sqrt(variance)
+	res.PValue = NormalPValue(res.Statistic)
+	return res
+}
+
+// serialCorrelation is Knuth's serial correlation coefficient of consecutive outcomes, with the last one followed by the first
+func (b *Battery) serialCorrelation() Result {
+	res := Result{Name: "serial correlation"}
+	n := float64(b.n)
+	if b.n < 4 {
+		res.Skipped = true
+		return b.skip(res)
+	}
+
+	numerator := n*(b.sumLag+b.prev*b.first) - b.sum*b.sum
+	denominator := n*b.sumSq - b.sum*b.sum
+	if denominator <= 0 {
+		res.Skipped = true
+		res.Note = "all outcomes are equal"
+		return res
+	}
+
+	c := numerator / denominator
+	mean := -1 / (n - 1)
+	sd := math.Sqrt(n*(n-3)/(n+1)) / (n - 1)
+	res.Statistic = c
+	res.PValue = NormalPValue((c - mean) / sd)
+	return res
+}
+
+// gapTest counts the outcomes between two outcomes of the low half, their lengths are geometric
+func (b *Battery) gapTest() Result {
+	res := Result{Name: "gap"}
+	total := int64(0)
+	for _, g := range b.gaps {
+		total += g
+	}
+	p := 0.0
+	for _, q := range b.probabilities[:b.split] {
+		p += q
+	}
+	if total == 0 || p == 0 || p == 1 {
+		res.Skipped = true
+		return b.skip(res)
+	}
+
+	// Count the gaps of length 0 to t-1 apart and the longer ones together, t is as
+	// long as the expected count of the longer gaps is at least minExpected
+	t := 0
+	for t < maxGap && float64(total)*math.Pow(1-p, float64(t+1)) >= minExpected {
+		t++
+	}
+	observed := make([]int64, t+1)
+	expected := make([]float64, t+1)
+	for r := 0; r < t; r++ {
+		observed[r] = b.gaps[r]
+		expected[r] = float64(total) * p * math.Pow(1-p, float64(r))
+	}
+	for r := t; r <= maxGap; r++ {
+		observed[t] += b.gaps[r]
+	}
+	expected[t] = float64(total) * math.Pow(1-p, float64(t))
+
+	res.Statistic, res.DF, res.PValue, res.Skipped = chiSquare(observed, expected)
+	return b.skip(res)
+}
+
+// pokerTest counts the distinct categories in hands of consecutive outcomes
+func (b *Battery) pokerTest() Result {
+	res := Result{Name: "poker"}
+	hands := int64(0)
+	for _, d := range b.distinct {
+		hands += d
+	}
+	if hands == 0 {
+		res.Skipped = true
+		return b.skip(res)
+	}
+
+	probabilities := distinctProbabilities(b.pokerProbability, handSize)
+	expected := make([]float64, handSize)
+	for i := range expected {
+		expected[i] = probabilities[i+1] * float64(hands)
+	}
+	res.Statistic, res.DF, res.PValue, res.Skipped = chiSquare(b.distinct[1:], expected)
+	return b.skip(res)
+}
+
+// skip explains a skipped test
+func (b *Battery) skip(res Result) Result {
+	if res.Skipped && len(res.Note) == 0 {
+		res.Note = "too few outcomes"
+	}
+	return res
+}
+
+// distinctProbabilities is the probability of r distinct categories in k draws, for r from 0 to k
+func distinctProbabilities(probabilities []float64, k int) []float64 {
+	// f[j][r] sums p^t/t! over the ways to draw j times from the categories so far with r of them drawn
+	f := make([][]float64, k+1)
+	for j := range f {
+		f[j] = make([]float64, k+1)
+	}
+	f[0][0] = 1
+	for _, p := range probabilities {
+		next := make([][]float64, k+1)
+		for j := range next {
+			next[j] = slices.Clone(f[j])
+		}
+		for j := 0; j <= k; j++ {
+			for r := 0; r < k; r++ {
+				if f[j][r] == 0 {
+					continue
+				}
+				term := 1.0
+				for t := 1; j+t <= k; t++ {
+					term *= p / float64(t)
+					next[j+t][r+1] += f[j][r] * term
+				}
+			}
+		}
+		f = next
+	}
+
+	factorial := 1.0
+	for i := 2; i <= k; i++ {
+		factorial *= float64(i)
+	}
+	result := make([]float64, k+1)
+	for r := range result {
+		result[r] = f[k][r] * factorial
+	}
+	return result
+}
+
+// chiSquare compares observed and expected counts, merging neighbouring cells
+// until every cell expects at least minExpected. It skips the test when fewer
+// than two cells remain.
+func chiSquare(observed []int64, expected []float64) (stat float64, df int, p float64, skipped bool) {
+	// An outcome that can not happen fails the test, merging would hide it
+	for i := range observed {
+		if expected[i] == 0 && observed[i] > 0 {
+			return math.Inf(1), len(observed) - 1, 0, false
+		}
+	}
+
+	var cellsObserved []float64
+	var cellsExpected []float64
+	o, e := 0.0, 0.0
+	for i := range observed {
+		o += float64(observed[i])
+		e += expected[i]
+		if e >= minExpected {
+			cellsObserved = append(cellsObserved, o)
+			cellsExpected = append(cellsExpected, e)
+			o, e = 0, 0
+		}
+	}
+	if o > 0 || e > 0 {
+		if len(cellsObserved) == 0 {
+			cellsObserved = append(cellsObserved, 0)
+			cellsExpected = append(cellsExpected, 0)
+		}
+		cellsObserved[len(cellsObserved)-1] += o
+		cellsExpected[len(cellsExpected)-1] += e
+	}
+	if len(cellsObserved) < 2 {
+		return 0, 0, 0, true
+	}
+
+	for i := range cellsObserved {
+		d := cellsObserved[i] - cellsExpected[i]
+		stat += d * d / cellsExpected[i]
+	}
+	df = len(cellsObserved) - 1
+	return stat, df, ChiSquarePValue(stat, df), false
+}
+
+func ceilDiv(a uint64, b uint64) uint64 {
+	return (a + b - 1) / b
+}
@@ -0,0 +1,169 @@
+package stats
+
+import (
+	"bytes"
+	"math"
+	"math/rand/v2"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_UniformFloat64(t *testing.T) {
+	rnd := rand.New(rand.NewPCG(1, 2))
+	b := NewUniformFloat64()
+	for range 100000 {
+		b.Add(rnd.Float64())
+	}
+
+	r := b.Report(0.001)
+	assert.Equal(t, int64(100000), r.Samples)
+	assert.Len(t, r.Results, 6)
+	for _, res := range r.Results {
+		assert.True(t, res.Pass, res.Name)
+		assert.False(t, res.Skipped, res.Name)
+	}
+	assert.Equal(t, 0, r.Failed())
+}
+
+func Test_UniformInt64(t *testing.T) {
+	rnd := rand.New(rand.NewPCG(3, 4))
+	b, err := NewUniformInt64(1, 6)
+	assert.NoError(t, err)
+	for range 100000 {
+		b.Add(float64(1 + rnd.IntN(6)))
+	}
+	assert.Equal(t, 0, b.Report(0.001).Failed())
+
+	// Wide ranges are bucketed
+	b, err = NewUniformInt64(0, math.MaxInt32-1)
+	assert.NoError(t, err)
+	assert.Len(t, b.probabilities, maxCategories)
+	for range 100000 {
+		b.Add(float64(rnd.Int32N(math.MaxInt32)))
+	}
+	assert.Equal(t, 0, b.Report(0.001).Failed())
+
+	_, err = NewUniformInt64(2, 1)
+	assert.Error(t, err)
+}
+
+func Test_Discrete(t *testing.T) {
+	probabilities := []float64{0.01, 0.4, 0.59}
+	rnd := rand.New(rand.NewPCG(5, 6))
+	b, err := NewDiscrete(probabilities)
+	assert.NoError(t, err)
+	for range 100000 {
+		x := rnd.Float64()
+		switch {
+		case x < 0.01:
+			b.Add(0)
+		case x < 0.41:
+			b.Add(1)
+		default:
+			b.Add(2)
+		}
+	}
+	assert.Equal(t, 0, b.Report(0.001).Failed())
+
+	_, err = NewDiscrete([]float64{0.5, 0.4})
+	assert.Error(t, err)
+	_, err = NewDiscrete(nil)
+	assert.Error(t, err)
+}
+
+func Test_Battery_Failures(t *testing.T) {
+	results := func(b *Battery) map[string]Result {
+		m := map[string]Result{}
+		for _, res := range b.Report(DefaultAlpha).Results {
+			m[res.Name] = res
+		}
+		return m
+	}
+
+	// Skewed: every float in the lower half
+	rnd := rand.New(rand.NewPCG(7, 8))
+	b := NewUniformFloat64()
+	for range 10000 {
+		b.Add(rnd.Float64() / 2)
+	}
+	r := results(b)
+	assert.False(t, r["chi-square"].Pass)
+	assert.False(t, r["kolmogorov-smirnov"].Pass)
+
+	// Alternating halves: uniform counts, but far too many runs and a negative correlation
+	b = NewUniformFloat64()
+	for i := range 10000 {
+		b.Add(float64(i%2)/2 + rnd.Float64()/2)
+	}
+	r = results(b)
+	assert.True(t, r["chi-square"].Pass)
+	assert.False(t, r["runs"].Pass)
+	assert.False(t, r["serial correlation"].Pass)
+	assert.False(t, r["gap"].Pass)
+
+	// A counter: uniform counts, but every hand of 5 is alike
+	b, err := NewUniformInt64(0, 9)
+	assert.NoError(t, err)
+	for i := range 10000 {
+		b.Add(float64(i % 10))
+	}
+	r = results(b)
+	assert.True(t, r["chi-square"].Pass)
+	assert.False(t, r["poker"].Pass)
+
+	// An index of probability 0
+	b, err = NewDiscrete([]float64{0, 1})
+	assert.NoError(t, err)
+	b.Add(0)
+	for range 100 {
+		b.Add(1)
+	}
+	assert.False(t, results(b)["chi-square"].Pass)
+
+	// Outcomes outside of the range
+	b, err = NewUniformInt64(1, 6)
+	assert.NoError(t, err)
+	b.Add(7)
+	assert.False(t, results(b)["chi-square"].Pass)
+}
+
+func Test_Battery_TooFewOutcomes(t *testing.T) {
+	r := NewUniformFloat64().Report(DefaultAlpha)
+	assert.Equal(t, 0, r.Failed())
+	for _, res := range r.Results {
+		assert.True(t, res.Skipped, res.Name)
+	}
+}
+
+func Test_distinctProbabilities(t *testing.T) {
+	// Knuth's poker test with 10 equally likely digits
+	uniform := make([]float64, 10)
+	for i := range uniform {
+		uniform[i] = 0.1
+	}
+	p := distinctProbabilities(uniform, 5)
+	assert.InDelta(t, 0.0001, p[1], 1e-12)
+	assert.InDelta(t, 0.0135, p[2], 1e-12)
+	assert.InDelta(t, 0.18, p[3], 1e-12)
+	assert.InDelta(t, 0.504, p[4], 1e-12)
+	assert.InDelta(t, 0.3024, p[5], 1e-12)
+}
+
+func Test_Report_WriteText(t *testing.T) {
+	r := Report{
+		Samples: 10,
+		Alpha:   0.01,
+		Results: []Result{
+			{Name: "chi-square", Statistic: 1.5, DF: 2, PValue: 0.47, Pass: true},
+			{Name: "runs", Statistic: 3, PValue: 0.0027},
+			{Name: "gap", Skipped: true, Pass: true, Note: "too few outcomes"},
+		},
+	}
+
+	var buf bytes.Buffer
+	assert.NoError(t, r.WriteText(&buf))
+	assert.Contains(t, buf.String(), "FAIL")
+	assert.Contains(t, buf.String(), "skipped  too few outcomes")
+	assert.Contains(t, buf.String(), "3 tests on 10 outcomes, 1 failed at alpha 0.01")
+}
@@ -0,0 +1,135 @@
+package stats
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"strings"
+	"text/tabwriter"
+)
+
+// Histogram is the count of the outcomes per category of the chi-square test: a
+// bin of floats, a bucket of integers or a prize index
+type Histogram struct {
+	// Continuous is set for floats, a bin holds its outcomes from Low up to High
+	// excluded. Otherwise it holds Low to High included.
+	Continuous bool  `json:"continuous,omitempty"`
+	Bins       []Bin `json:"bins"`
+	// OutOfRange is the number of outcomes outside of every bin
+	OutOfRange int64 `json:"outOfRange,omitempty"`
+}
+
+// Bin compares the outcomes observed in a category with those expected
+type Bin struct {
+	Low         float64 `json:"low"`
+	High        float64 `json:"high"`
+	Probability float64 `json:"probability"`
+	Expected    float64 `json:"expected"`
+	Observed    int64   `json:"observed"`
+	// Deviation is the difference of the observed and expected counts relative to
+	// the expected count, and Z the difference in standard deviations. Both are 0
+	// when no outcome is expected.
+	Deviation float64 `json:"deviation"`
+	Z         float64 `json:"z"`
+}
+
+// newBin compares observed outcomes with those expected of n outcomes
+func newBin(low float64, high float64, probability float64, observed int64, n int64) Bin {
+	bin := Bin{Low: low, High: high, Probability: probability, Expected: probability * float64(n), Observed: observed}
+	if bin.Expected > 0 {
+		d := float64(observed) - bin.Expected
+		bin.Deviation = d / bin.Expected
+		if probability < 1 {
+			// The count of a category is binomial
+			bin.Z = d / math.Sqrt(bin.Expected*(1-probability))
+		}
+	}
+	return bin
+}
+
+// histogram compares the counts of the categories with their probabilities
+func (b *Battery) histogram() Histogram {
+	h := Histogram{Continuous: b.continuous, Bins: make([]Bin, len(b.probabilities)), OutOfRange: b.outOfRange}
+	n := b.n - b.outOfRange
+	for i, p := range b.probabilities {
+		h.Bins[i] = newBin(b.bounds[i][0], b.bounds[i][1], p, b.counts[i], n)
+	}
+	return h
+}
+
+// Merge merges neighbouring bins into at most rows bins of as many categories
+func (h Histogram) Merge(rows int) Histogram {
+	if rows < 1 || len(h.Bins) <= rows {
+		return h
+	}
+
+	n := int64(0)
+	for _, bin := range h.Bins {
+		n += bin.Observed
+	}
+	size := (len(h.Bins) + rows - 1) / rows
+	merged := Histogram{Continuous: h.Continuous, OutOfRange: h.OutOfRange}
+	for i := 0; i < len(h.Bins); i += size {
+		group := h.Bins[i:min(i+size, len(h.Bins))]
+		probability, observed := 0.0, int64(0)
+		for _, bin := range group {
+			probability += bin.Probability
+			observed += bin.Observed
+		}
+		merged.Bins = append(merged.Bins, newBin(group[0].Low, group[len(group)-1].High, probability, observed, n))
+	}
+	return merged
+}
+
+// label names the outcomes of a bin
+func (h Histogram) label(bin Bin) string {
+	if h.Continuous {
+		return fmt.Sprintf("[%.4g, %.4g)", bin.Low, bin.High)
+	} else if bin.Low == bin.High {
+		return fmt.Sprintf("%.0f", bin.Low)
+	}
+	return fmt.Sprintf("%.0f-%.0f", bin.Low, bin.High)
+}
+
+// WriteText writes a row per bin with its counts and a bar of width characters
+// at most. The bar is the observed count, the | in it marks the expected count.
+func (h Histogram) WriteText(w io.Writer, width int) error {
+	top := 0.0
+	for _, bin := range h.Bins {
+		top = max(top, bin.Expected, float64(bin.Observed))
+	}
+	scale := 0.0
+	if top > 0 {
+		scale = float64(width) / top
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', tabwriter.AlignRight)
+	fmt.Fprintln(tw, "outcome\texpected\tobserved\tdeviation\tz\t")
+	bar := make([]byte, width+1)
+	for _, bin := range h.Bins {
+		observed := int(math.Round(float64(bin.Observed) * scale))
+		if bin.Observed > 0 {
+			// A rare outcome that was drawn still shows
+			observed = max(observed, 1)
+		}
+		expected := int(math.Round(bin.Expected * scale))
+		for i := range bar {
+			switch {
+			case i == expected:
+				bar[i] = '|'
+			case i < observed:
+				bar[i] = '#'
+			default:
+				bar[i] = ' '
+			}
+		}
+		fmt.Fprintf(tw, "%s\t%.1f\t%v\t%+.2f%%\t%+.2f\t  %s\n", h.label(bin), bin.Expected, bin.Observed,
+			100*bin.Deviation, bin.Z, strings.TrimRight(string(bar), " "))
+	}
+	err := tw.Flush()
+	if err != nil || h.OutOfRange == 0 {
+		return err
+	}
+	_, err = fmt.Fprintf(w, "%v outcomes outside of every bin\n", h.OutOfRange)
+	return err
+}
@@ -0,0 +1,66 @@
+package stats
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Histogram(t *testing.T) {
+	b, err := NewDiscrete([]float64{0.25, 0.75, 0})
+	assert.NoError(t, err)
+	for _, v := range []float64{0, 1, 1, 0, 1, 1, 1, 1} {
+		b.Add(v)
+	}
+
+	h := b.Report(DefaultAlpha).Histogram
+	assert.False(t, h.Continuous)
+	assert.Len(t, h.Bins, 3)
+	assert.Equal(t, Bin{Low: 0, High: 0, Probability: 0.25, Expected: 2, Observed: 2}, h.Bins[0])
+	assert.Equal(t, int64(6), h.Bins[1].Observed)
+	assert.InDelta(t, 0, h.Bins[1].Deviation, 1e-12)
+	assert.Equal(t, Bin{Low: 2, High: 2}, h.Bins[2])
+
+	var buf bytes.Buffer
+	assert.NoError(t, h.WriteText(&buf, 12))
+	assert.Contains(t, buf.String(), "outcome  expected  observed  deviation      z\n")
+	assert.Contains(t, buf.String(), "      0       2.0         2     +0.00%  +0.00  ####|\n")
+	assert.Contains(t, buf.String(), "      1       6.0         6     +0.00%  +0.00  ############|\n")
+	assert.Contains(t, buf.String(), "      2       0.0         0     +0.00%  +0.00  |\n")
+}
+
+func Test_Histogram_Merge(t *testing.T) {
+	b := NewUniformFloat64()
+	for i := range 1000 {
+		b.Add(float64(i%100) / 100)
+	}
+	b.Add(2)
+
+	h := b.Report(DefaultAlpha).Histogram
+	assert.True(t, h.Continuous)
+	assert.Len(t, h.Bins, floatBins)
+	assert.Equal(t, int64(1), h.OutOfRange)
+
+	merged := h.Merge(30)
+	assert.Len(t, merged.Bins, 25)
+	assert.Equal(t, 0.0, merged.Bins[0].Low)
+	assert.InDelta(t, 0.04, merged.Bins[0].High, 1e-12)
+	assert.Equal(t, int64(40), merged.Bins[0].Observed)
+	assert.InDelta(t, 40, merged.Bins[0].Expected, 1e-9)
+	assert.Equal(t, h, h.Merge(100))
+
+	var buf bytes.Buffer
+	assert.NoError(t, merged.WriteText(&buf, 40))
+	assert.Contains(t, buf.String(), "[0.96, 1)")
+	assert.Contains(t, buf.String(), "1 outcomes outside of every bin\n")
+
+	// Integer buckets are labelled with their range
+	b, err := NewUniformInt64(1, 6)
+	assert.NoError(t, err)
+	b.Add(3)
+	buf.Reset()
+	assert.NoError(t, b.Report(DefaultAlpha).Histogram.Merge(2).WriteText(&buf, 10))
+	assert.Contains(t, buf.String(), "1-3")
+	assert.Contains(t, buf.String(), "4-6")
+}
@@ -0,0 +1,119 @@
+package stats
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"slices"
+	"sync"
+	"time"
+)
+
+// latencyGrowth is the ratio of the bounds of consecutive buckets of a latency
+// histogram, the percentiles are within 1% of the exact ones
+const latencyGrowth = 1.01
+
+// latencyPercentiles are the percentiles of the latencies that are reported
+var latencyPercentiles = []float64{50, 90, 99, 99.9, 100}
+
+// Latency is a histogram of the durations of calls, safe for concurrent use. The
+// durations fall in buckets 1% apart, so its size does not grow with the calls.
+type Latency struct {
+	mu      sync.Mutex
+	buckets map[int]int64
+	n       int64
+	total   time.Duration
+	min     time.Duration
+	max     time.Duration
+}
+
+// NewLatency creates an empty latency histogram
+func NewLatency() *Latency {
+	return &Latency{buckets: make(map[int]int64)}
+}
+
+// Add adds the duration of a call
+func (l *Latency) Add(d time.Duration) {
+	d = max(d, 0)
+	bucket := 0
+	if d > 1 {
+		bucket = int(math.Ceil(math.Log(float64(d)) / math.Log(latencyGrowth)))
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.buckets[bucket]++
+	if l.n == 0 || d < l.min {
+		l.min = d
+	}
+	l.max = max(l.max, d)
+	l.n++
+	l.total += d
+}
+
+// Report summarizes the durations added so far
+func (l *Latency) Report() LatencyReport {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	r := LatencyReport{Count: l.n}
+	if l.n == 0 {
+		return r
+	}
+	r.MinMs = milliseconds(l.min)
+	r.MeanMs = milliseconds(l.total) / float64(l.n)
+	r.MaxMs = milliseconds(l.max)
+
+	buckets := make([]int, 0, len(l.buckets))
+	for b := range l.buckets {
+		buckets = append(buckets, b)
+	}
+	slices.Sort(buckets)
+	for _, pct := range latencyPercentiles {
+		// Nearest rank, the upper bound of its bucket within the range of the durations
+		rank := max(int64(math.Ceil(pct/100*float64(l.n))), 1)
+		seen := int64(0)
+		for _, b := range buckets {
+			seen += l.buckets[b]
+			if seen >= rank {
+				bound := time.Duration(math.Pow(latencyGrowth, float64(b)))
+				r.Percentiles = append(r.Percentiles, Percentile{Percentile: pct, Value: milliseconds(min(max(bound, l.min), l.max))})
+				break
+			}
+		}
+	}
+	return r
+}
+
+// milliseconds converts a duration to fractional milliseconds
+func milliseconds(d time.Duration) float64 {
+	return float64(d) / float64(time.Millisecond)
+}
+
+// LatencyReport summarizes the durations of calls, in milliseconds
+type LatencyReport struct {
+	Count       int64        `json:"count"`
+	MinMs       float64      `json:"minMs"`
+	MeanMs      float64      `json:"meanMs"`
+	MaxMs       float64      `json:"maxMs"`
+	Percentiles []Percentile `json:"percentiles"`
+}
+
+// WriteText writes the report on one line
+func (r LatencyReport) WriteText(w io.Writer) error {
+	_, err := fmt.Fprintf(w, "latency of %v calls: min %.3fms, mean %.3fms", r.Count, r.MinMs, r.MeanMs)
+	if err != nil {
+		return err
+	}
+	for _, p := range r.Percentiles {
+		if p.Percentile == 100 {
+			continue
+		}
+		_, err = fmt.Fprintf(w, ", p%g %.3fms", p.Percentile, p.Value)
+		if err != nil {
+			return err
+		}
+	}
+	_, err = fmt.Fprintf(w, ", max %.3fms\n", r.MaxMs)
+	return err
+}
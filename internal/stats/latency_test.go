@@ -0,0 +1,53 @@
+package stats
+
+import (
+	"bytes"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Latency(t *testing.T) {
+	l := NewLatency()
+	assert.Equal(t, LatencyReport{}, l.Report())
+
+	var wg sync.WaitGroup
+	for w := range 4 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := w + 1; i <= 1000; i += 4 {
+				l.Add(time.Duration(i) * time.Millisecond)
+			}
+		}()
+	}
+	wg.Wait()
+
+	r := l.Report()
+	assert.Equal(t, int64(1000), r.Count)
+	assert.Equal(t, 1.0, r.MinMs)
+	assert.InDelta(t, 500.5, r.MeanMs, 1e-9)
+	assert.Equal(t, 1000.0, r.MaxMs)
+	assert.Len(t, r.Percentiles, len(latencyPercentiles))
+	for i, want := range []float64{500, 900, 990, 999, 1000} {
+		assert.Equal(t, latencyPercentiles[i], r.Percentiles[i].Percentile)
+		assert.InEpsilon(t, want, r.Percentiles[i].Value, 0.01)
+	}
+
+	var buf bytes.Buffer
+	assert.NoError(t, r.WriteText(&buf))
+	assert.Contains(t, buf.String(), "latency of 1000 calls: min 1.000ms, mean 500.500ms, p50 ")
+	assert.Contains(t, buf.String(), ", max 1000.000ms\n")
+}
+
+func Test_Latency_Zero(t *testing.T) {
+	l := NewLatency()
+	l.Add(0)
+	l.Add(-time.Second)
+	r := l.Report()
+	assert.Equal(t, int64(2), r.Count)
+	assert.Equal(t, 0.0, r.MaxMs)
+	assert.Equal(t, 0.0, r.Percentiles[0].Value)
+}
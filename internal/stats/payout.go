@@ -0,0 +1,274 @@
+package stats
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"slices"
+	"text/tabwriter"
+)
+
+const (
+	// DefaultConfidence is the confidence level of the intervals of a payout report
+	DefaultConfidence = 0.95
+	// DefaultCampaignSize is the number of plays of a campaign the exposure is measured over
+	DefaultCampaignSize = 1000
+)
+
+// exposurePercentiles are the percentiles of the maximum exposure of a campaign that are reported
+var exposurePercentiles = []float64{50, 90, 95, 99, 99.9, 100}
+
+// Payout accumulates the payouts of a prize table: every play costs cost and
+// pays the value of the index drawn. The exposure of a campaign is the highest
+// amount the operator is down at any play of it, the running total of the
+// payouts less the costs.
+type Payout struct {
+	probabilities []float64
+	values        []float64
+	cost          float64
+	campaignSize  int64
+
+	n        int64
+	hits     int64
+	mean     float64
+	m2       float64
+	total    float64
+	maxValue float64
+
+	// the campaign in progress and the exposures of the ones completed
+	campaignPlays int64
+	campaignNet   float64
+	campaignPeak  float64
+	exposures     []float64
+}
+
+// NewPayout creates the payout accumulator of a prize table, the value of
+// index i is paid with probability probabilities[i]
+func NewPayout(probabilities []float64, values []float64, cost float64, campaignSize int64) (*Payout, error) {
+	if len(values) != len(probabilities) {
+		return nil, fmt.Errorf("%v values for %v probabilities; one value per index is required", len(values), len(probabilities))
+	} else if cost < 0 || math.IsNaN(cost) || math.IsInf(cost, 0) {
+		return nil, errors.New("cost must be at least 0")
+	} else if campaignSize < 1 {
+		return nil, errors.New("campaign size must be at least 1")
+	}
+	for _, v := range values {
+		if v < 0 || math.IsNaN(v) || math.IsInf(v, 0) {
+			return nil, fmt.Errorf("invalid value %v", v)
+		}
+	}
+
+	return &Payout{
+		probabilities: slices.Clone(probabilities),
+		values:        slices.Clone(values),
+		cost:          cost,
+		campaignSize:  campaignSize,
+	}, nil
+}
+
+// Add accumulates the play that drew index
+func (p *Payout) Add(index int64) error {
+	if index < 0 || index >= int64(len(p.values)) {
+		return fmt.Errorf("index %v out of range of %v values", index, len(p.values))
+	}
+	v := p.values[index]
+
+	// Welford's update keeps the variance precise over billions of plays
+	p.n++
+	d := v - p.mean
+	p.mean += d / float64(p.n)
+	p.m2 += d * (v - p.mean)
+	p.total += v
+	if v > 0 {
+		p.hits++
+	}
+	p.maxValue = max(p.maxValue, v)
+
+	p.campaignPlays++
+	p.campaignNet += v - p.cost
+	p.campaignPeak = max(p.campaignPeak, p.campaignNet)
+	if p.campaignPlays == p.campaignSize {
+		p.exposures = append(p.exposures, p.campaignPeak)
+		p.campaignPlays, p.campaignNet, p.campaignPeak = 0, 0, 0
+	}
+	return nil
+}
+
+// Plays is the number of plays added
+func (p *Payout) Plays() int64 {
+	return p.n
+}
+
+// Report compares the plays added so far with the prize table, the intervals are at confidence
+func (p *Payout) Report(confidence float64) PayoutReport {
+	z := math.Sqrt2 * math.Erfinv(confidence)
+	r := PayoutReport{
+		Plays:        p.n,
+		Cost:         p.cost,
+		Confidence:   confidence,
+		TotalPayout:  p.total,
+		MaxPayout:    p.maxValue,
+		CampaignSize: p.campaignSize,
+	}
+
+	for i, prob := range p.probabilities {
+		v := p.values[i]
+		r.Expected.MeanPayout += prob * v
+		r.Expected.Variance += prob * v * v
+		if v > 0 {
+			r.Expected.HitFrequency += prob
+		}
+	}
+	r.Expected.Variance = max(r.Expected.Variance-r.Expected.MeanPayout*r.Expected.MeanPayout, 0)
+	r.Expected.StdDev = math.Sqrt(r.Expected.Variance)
+
+	if p.n > 0 {
+		n := float64(p.n)
+		r.Observed.MeanPayout = p.mean
+		r.Observed.HitFrequency = float64(p.hits) / n
+		if p.n > 1 {
+			r.Observed.Variance = p.m2 / (n - 1)
+		}
+		r.Observed.StdDev = math.Sqrt(r.Observed.Variance)
+
+		margin := z * r.Observed.StdDev / math.Sqrt(n)
+		r.MeanPayoutInterval = [2]float64{p.mean - margin, p.mean + margin}
+		r.HitFrequencyInterval = wilson(p.hits, p.n, z)
+
+		// The range the mean of n plays falls in by the prize table, and how far outside of it the plays are
+		margin = z * r.Expected.StdDev / math.Sqrt(n)
+		r.ExpectedMeanPayoutRange = [2]float64{r.Expected.MeanPayout - margin, r.Expected.MeanPayout + margin}
+		r.PValue = 1
+		if r.Expected.StdDev > 0 {
+			r.PValue = NormalPValue((p.mean - r.Expected.MeanPayout) / (r.Expected.StdDev / math.Sqrt(n)))
+		} else if p.mean != r.Expected.MeanPayout {
+			r.PValue = 0
+		}
+	}
+
+	if p.cost > 0 {
+		r.Expected.RTP = r.Expected.MeanPayout / p.cost
+		r.Expected.VolatilityIndex = z * r.Expected.StdDev / p.cost
+		r.Observed.RTP = r.Observed.MeanPayout / p.cost
+		r.Observed.VolatilityIndex = z * r.Observed.StdDev / p.cost
+		r.RTPInterval = [2]float64{r.MeanPayoutInterval[0] / p.cost, r.MeanPayoutInterval[1] / p.cost}
+		r.ExpectedRTPRange = [2]float64{r.ExpectedMeanPayoutRange[0] / p.cost, r.ExpectedMeanPayoutRange[1] / p.cost}
+	}
+
+	exposures := p.exposures
+	if len(exposures) == 0 && p.campaignPlays > 0 {
+		// Fewer plays than a campaign, the plays are the campaign
+		exposures = []float64{p.campaignPeak}
+	}
+	r.Campaigns = len(exposures)
+	if len(exposures) > 0 {
+		sorted := slices.Clone(exposures)
+		slices.Sort(sorted)
+		for _, pct := range exposurePercentiles {
+			// Nearest rank
+			rank := max(int(math.Ceil(pct/100*float64(len(sorted)))), 1)
+			r.Exposure = append(r.Exposure, Percentile{Percentile: pct, Value: sorted[rank-1]})
+		}
+	}
+	return r
+}
+
+// wilson is the Wilson score interval of a proportion of hits in n at z
+func wilson(hits int64, n int64, z float64) [2]float64 {
+	nf := float64(n)
+	phat := float64(hits) / nf
+	denominator := 1 + z*z/nf
+	center := (phat + z*z/(2*nf)) / denominator
+	margin := z / denominator * math.Sqrt(phat*(1-phat)/nf+z*z/(4*nf*nf))
+	return [2]float64{max(center-margin, 0), min(center+margin, 1)}
+}
+
+// PayoutMoments are the figures of the payout of a play, by the prize table or by the plays
+type PayoutMoments struct {
+	MeanPayout float64 `json:"meanPayout"`
+	// RTP is the return to player, the mean payout per unit of cost, 0 without a cost
+	RTP          float64 `json:"rtp"`
+	HitFrequency float64 `json:"hitFrequency"`
+	Variance     float64 `json:"variance"`
+	StdDev       float64 `json:"stdDev"`
+	// VolatilityIndex is the standard deviation per unit of cost times the z-score of the confidence level
+	VolatilityIndex float64 `json:"volatilityIndex"`
+}
+
+// Percentile is a percentile of a distribution
+type Percentile struct {
+	Percentile float64 `json:"percentile"`
+	Value      float64 `json:"value"`
+}
+
+// PayoutReport compares the payouts of the plays with those of the prize table
+type PayoutReport struct {
+	Plays      int64   `json:"plays"`
+	Cost       float64 `json:"cost"`
+	Confidence float64 `json:"confidence"`
+
+	Expected PayoutMoments `json:"expected"`
+	Observed PayoutMoments `json:"observed"`
+
+	// MeanPayoutInterval and RTPInterval are the confidence intervals of the figures of the plays
+	MeanPayoutInterval   [2]float64 `json:"meanPayoutInterval"`
+	RTPInterval          [2]float64 `json:"rtpInterval"`
+	HitFrequencyInterval [2]float64 `json:"hitFrequencyInterval"`
+	// ExpectedMeanPayoutRange and ExpectedRTPRange are where the figures of as many plays fall by the prize table
+	ExpectedMeanPayoutRange [2]float64 `json:"expectedMeanPayoutRange"`
+	ExpectedRTPRange        [2]float64 `json:"expectedRtpRange"`
+	// PValue is the two-sided probability of a mean payout at least as far from the expected one
+	PValue float64 `json:"pValue"`
+
+	TotalPayout float64 `json:"totalPayout"`
+	MaxPayout   float64 `json:"maxPayout"`
+
+	// Exposure holds the percentiles of the highest amount the operator is down over a campaign
+	CampaignSize int64        `json:"campaignSize"`
+	Campaigns    int          `json:"campaigns"`
+	Exposure     []Percentile `json:"exposure"`
+}
+
+// WriteText writes the report as tables of the figures and of the exposure
+func (r PayoutReport) WriteText(w io.Writer) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	pct := fmt.Sprintf("%g%%", 100*r.Confidence)
+	fmt.Fprintf(tw, "payout\texpected\tobserved\t%s interval\t%s expected range\n", pct, pct)
+	if r.Cost > 0 {
+		fmt.Fprintf(tw, "RTP\t%.4f%%\t%.4f%%\t%.4f%% - %.4f%%\t%.4f%% - %.4f%%\n", 100*r.Expected.RTP, 100*r.Observed.RTP,
+			100*r.RTPInterval[0], 100*r.RTPInterval[1], 100*r.ExpectedRTPRange[0], 100*r.ExpectedRTPRange[1])
+	}
+	fmt.Fprintf(tw, "mean payout\t%.6g\t%.6g\t%.6g - %.6g\t%.6g - %.6g\n", r.Expected.MeanPayout, r.Observed.MeanPayout,
+		r.MeanPayoutInterval[0], r.MeanPayoutInterval[1], r.ExpectedMeanPayoutRange[0], r.ExpectedMeanPayoutRange[1])
+	fmt.Fprintf(tw, "hit frequency\t%.4f%%\t%.4f%%\t%.4f%% - %.4f%%\t\n", 100*r.Expected.HitFrequency, 100*r.Observed.HitFrequency,
+		100*r.HitFrequencyInterval[0], 100*r.HitFrequencyInterval[1])
+	fmt.Fprintf(tw, "variance\t%.6g\t%.6g\t\t\n", r.Expected.Variance, r.Observed.Variance)
+	fmt.Fprintf(tw, "standard deviation\t%.6g\t%.6g\t\t\n", r.Expected.StdDev, r.Observed.StdDev)
+	if r.Cost > 0 {
+		fmt.Fprintf(tw, "volatility index\t%.4f\t%.4f\t\t\n", r.Expected.VolatilityIndex, r.Observed.VolatilityIndex)
+	}
+	err := tw.Flush()
+	if err != nil {
+		return err
+	}
+
+	_, err = fmt.Fprintf(w, "%v plays at a cost of %v paid %.6g, the highest payout was %.6g, p-value of the mean payout %.6f\n",
+		r.Plays, r.Cost, r.TotalPayout, r.MaxPayout, r.PValue)
+	if err != nil || len(r.Exposure) == 0 {
+		return err
+	}
+
+	_, err = fmt.Fprintf(w, "maximum exposure of %v campaigns of %v plays:\n", r.Campaigns, r.CampaignSize)
+	if err != nil {
+		return err
+	}
+	for _, e := range r.Exposure {
+		label := fmt.Sprintf("p%g", e.Percentile)
+		if e.Percentile == 100 {
+			label = "max"
+		}
+		fmt.Fprintf(tw, "  %s\t%.6g\n", label, e.Value)
+	}
+	return tw.Flush()
+}
@@ -0,0 +1,84 @@
+package stats
+
+import (
+	"bytes"
+	"math"
+	"math/rand/v2"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Payout(t *testing.T) {
+	p, err := NewPayout([]float64{0.5, 0.3, 0.2}, []float64{0, 1, 4}, 1, 2)
+	assert.NoError(t, err)
+	for _, index := range []int64{2, 0, 1, 1} {
+		assert.NoError(t, p.Add(index))
+	}
+	assert.Error(t, p.Add(3))
+	assert.Equal(t, int64(4), p.Plays())
+
+	r := p.Report(DefaultConfidence)
+	assert.InDelta(t, 1.1, r.Expected.MeanPayout, 1e-12)
+	assert.InDelta(t, 1.1, r.Expected.RTP, 1e-12)
+	assert.InDelta(t, 0.5, r.Expected.HitFrequency, 1e-12)
+	assert.InDelta(t, 2.29, r.Expected.Variance, 1e-12)
+	assert.InDelta(t, 1.959964*math.Sqrt(2.29), r.Expected.VolatilityIndex, 1e-5)
+
+	assert.Equal(t, 6.0, r.TotalPayout)
+	assert.Equal(t, 4.0, r.MaxPayout)
+	assert.InDelta(t, 1.5, r.Observed.MeanPayout, 1e-12)
+	assert.InDelta(t, 0.75, r.Observed.HitFrequency, 1e-12)
+	// Sample variance of 4, 0, 1, 1
+	assert.InDelta(t, 3.0, r.Observed.Variance, 1e-12)
+
+	// The first campaign is up 4 - 1 after its first play, the second never goes above 0
+	assert.Equal(t, 2, r.Campaigns)
+	assert.Equal(t, []Percentile{{50, 0}, {90, 3}, {95, 3}, {99, 3}, {99.9, 3}, {100, 3}}, r.Exposure)
+
+	var buf bytes.Buffer
+	assert.NoError(t, r.WriteText(&buf))
+	assert.Contains(t, buf.String(), "RTP                 110.0000%  150.0000%")
+	assert.Contains(t, buf.String(), "maximum exposure of 2 campaigns of 2 plays:\n")
+	assert.Contains(t, buf.String(), "  max    3\n")
+}
+
+func Test_Payout_Simulated(t *testing.T) {
+	probabilities := []float64{0.9, 0.09, 0.009, 0.001}
+	values := []float64{0, 5, 20, 200}
+	p, err := NewPayout(probabilities, values, 1, DefaultCampaignSize)
+	assert.NoError(t, err)
+
+	rnd := rand.New(rand.NewPCG(5, 6))
+	for range 200000 {
+		x := rnd.Float64()
+		index := int64(0)
+		for cumulative := probabilities[0]; x >= cumulative && index < 3; cumulative += probabilities[index] {
+			index++
+		}
+		assert.NoError(t, p.Add(index))
+	}
+
+	r := p.Report(0.99)
+	assert.InDelta(t, 0.83, r.Expected.RTP, 1e-12)
+	assert.Greater(t, r.PValue, 0.01)
+	assert.True(t, r.RTPInterval[0] < r.Expected.RTP && r.Expected.RTP < r.RTPInterval[1])
+	assert.True(t, r.HitFrequencyInterval[0] < 0.1 && 0.1 < r.HitFrequencyInterval[1])
+	assert.True(t, r.ExpectedRTPRange[0] < r.Observed.RTP && r.Observed.RTP < r.ExpectedRTPRange[1])
+	assert.Equal(t, 200, r.Campaigns)
+	assert.Len(t, r.Exposure, len(exposurePercentiles))
+	for i := 1; i < len(r.Exposure); i++ {
+		assert.LessOrEqual(t, r.Exposure[i-1].Value, r.Exposure[i].Value)
+	}
+}
+
+func Test_Payout_Errors(t *testing.T) {
+	_, err := NewPayout([]float64{0.5, 0.5}, []float64{1}, 1, 1)
+	assert.Error(t, err)
+	_, err = NewPayout([]float64{0.5, 0.5}, []float64{1, -1}, 1, 1)
+	assert.Error(t, err)
+	_, err = NewPayout([]float64{0.5, 0.5}, []float64{1, 1}, -1, 1)
+	assert.Error(t, err)
+	_, err = NewPayout([]float64{0.5, 0.5}, []float64{1, 1}, 1, 0)
+	assert.Error(t, err)
+}
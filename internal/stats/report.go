@@ -0,0 +1,69 @@
+package stats
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"text/tabwriter"
+)
+
+// Result is the outcome of one test
+type Result struct {
+	Name      string  `json:"name"`
+	Statistic float64 `json:"statistic"`
+	// DF is the degrees of freedom of a chi-square statistic, 0 for the other tests
+	DF     int     `json:"df,omitempty"`
+	PValue float64 `json:"pValue"`
+	Pass   bool    `json:"pass"`
+	// Skipped is set when the test does not apply to the outcomes, i.e. too few of them
+	Skipped bool   `json:"skipped,omitempty"`
+	Note    string `json:"note,omitempty"`
+}
+
+// Report is the outcome of a battery of tests
+type Report struct {
+	Samples int64    `json:"samples"`
+	Alpha   float64  `json:"alpha"`
+	Results []Result `json:"results"`
+	// Histogram is the count of the outcomes per category of the chi-square test
+	Histogram Histogram `json:"histogram"`
+}
+
+// Failed is the number of tests with a p-value below alpha
+func (r Report) Failed() int {
+	failed := 0
+	for _, res := range r.Results {
+		if !res.Pass {
+			failed++
+		}
+	}
+	return failed
+}
+
+// WriteText writes the report as a table followed by a summary line
+func (r Report) WriteText(w io.Writer) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "test\tstatistic\tdf\tp-value\tresult")
+	for _, res := range r.Results {
+		df := ""
+		if res.DF > 0 {
+			df = strconv.Itoa(res.DF)
+		}
+
+		switch {
+		case res.Skipped:
+			fmt.Fprintf(tw, "%s\t\t\t\tskipped\t%s\n", res.Name, res.Note)
+		case res.Pass:
+			fmt.Fprintf(tw, "%s\t%.6g\t%s\t%.6f\tpass\t%s\n", res.Name, res.Statistic, df, res.PValue, res.Note)
+		default:
+			fmt.Fprintf(tw, "%s\t%.6g\t%s\t%.6f\tFAIL\t%s\n", res.Name, res.Statistic, df, res.PValue, res.Note)
+		}
+	}
+	err := tw.Flush()
+	if err != nil {
+		return err
+	}
+
+	_, err = fmt.Fprintf(w, "%v tests on %v outcomes, %v failed at alpha %v\n", len(r.Results), r.Samples, r.Failed(), r.Alpha)
+	return err
+}
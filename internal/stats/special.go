@@ -0,0 +1,115 @@
+package stats
+
+import (
+	"math"
+)
+
+// Igamc is the regularized upper incomplete gamma function Q(a, x), it turns
+// chi-square statistics into p-values
+func Igamc(a float64, x float64) float64 {
+	if x < 0 || a <= 0 || math.IsNaN(x) || math.IsNaN(a) {
+		return math.NaN()
+	} else if x == 0 {
+		return 1
+	} else if math.IsInf(x, 1) {
+		return 0
+	}
+
+	if x < a+1 {
+		return 1 - gammaSeries(a, x)
+	}
+	return gammaContinuedFraction(a, x)
+}
+
+// Igam is the regularized lower incomplete gamma function P(a, x) = 1 - Q(a, x)
+func Igam(a float64, x float64) float64 {
+	return 1 - Igamc(a, x)
+}
+
+// ChiSquarePValue is the probability of a chi-square statistic at least stat with df degrees of freedom
+func ChiSquarePValue(stat float64, df int) float64 {
+	if df < 1 {
+		return math.NaN()
+	}
+	return Igamc(float64(df)/2, stat/2)
+}
+
+// NormalPValue is the two-sided probability of a standard normal statistic at least |z|
+func NormalPValue(z float64) float64 {
+	return math.Erfc(math.Abs(z) / math.Sqrt2)
+}
+
+// KolmogorovPValue is the probability of the Kolmogorov distribution at least lambda
+func KolmogorovPValue(lambda float64) float64 {
+	// The series converges slowly below 0.2, where the probability is 1 to 15 digits
+	if lambda < 0.2 {
+		return 1
+	}
+
+	sum := 0.0
+	sign := 1.0
+	for j := 1; j <= 100; j++ {
+		term := sign * math.Exp(-2*float64(j*j)*lambda*lambda)
+		sum += term
+		if math.Abs(term) <= 1e-16*math.Abs(sum) {
+			break
+		}
+		sign = -sign
+	}
+	return math.Max(0, math.Min(1, 2*sum))
+}
+
+const (
+	gammaEpsilon    = 1e-15
+	gammaIterations = 100000
+	gammaTiny       = 1e-300
+)
+
+// gammaFactor is x^a e^-x / Gamma(a), the factor shared by the series and the continued fraction
+func gammaFactor(a float64, x float64) float64 {
+	lgamma, _ := math.Lgamma(a)
+	return math.Exp(a*math.Log(x) - x - lgamma)
+}
+
+// gammaSeries is P(a, x) by its series, which converges for x < a+1
+func gammaSeries(a float64, x float64) float64 {
+	ap := a
+	del := 1 / a
+	sum := del
+	for range gammaIterations {
+		ap++
+		del *= x / ap
+		sum += del
+		if math.Abs(del) < math.Abs(sum)*gammaEpsilon {
+			break
+		}
+	}
+	return sum * gammaFactor(a, x)
+}
+
+// gammaContinuedFraction is Q(a, x) by its continued fraction with Lentz's method, which converges for x >= a+1
+func gammaContinuedFraction(a float64, x float64) float64 {
+	b := x + 1 - a
+	c := 1 / gammaTiny
+	d := 1 / b
+	h := d
+	for i := 1; i <= gammaIterations; i++ {
+		an := -float64(i) * (float64(i) - a)
+		b += 2
+		d = an*d + b
+		if math.Abs(d) < gammaTiny {
+			d = gammaTiny
+		}
+		c = b + an/c
+		if math.Abs(c) < gammaTiny {
+			c = gammaTiny
+		}
+		d = 1 / d
+		del := d * c
+		h *= del
+		if math.Abs(del-1) < gammaEpsilon {
+			break
+		}
+	}
+	return h * gammaFactor(a, x)
+}
@@ -0,0 +1,50 @@
+package stats
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Igamc(t *testing.T) {
+	assert.InDelta(t, math.Exp(-1), Igamc(1, 1), 1e-12)
+	assert.InDelta(t, math.Exp(-10), Igamc(1, 10), 1e-15)
+	assert.Equal(t, 1.0, Igamc(3, 0))
+	assert.True(t, math.IsNaN(Igamc(0, 1)))
+
+	assert.InDelta(t, math.Erfc(math.Sqrt(2)), Igamc(0.5, 2), 1e-12)
+	assert.InDelta(t, 1-math.Erfc(math.Sqrt(2)), Igam(0.5, 2), 1e-12)
+
+	// Q(n, x) is the probability of fewer than n events of a Poisson process with mean x
+	poisson := func(n int, x float64) float64 {
+		sum, term := 0.0, math.Exp(-x)
+		for k := range n {
+			sum += term
+			term *= x / float64(k+1)
+		}
+		return sum
+	}
+	for _, x := range []float64{3, 40, 60, 120} {
+		assert.InDelta(t, poisson(50, x), Igamc(50, x), 1e-12, x)
+	}
+}
+
+func Test_ChiSquarePValue(t *testing.T) {
+	assert.InDelta(t, 0.05, ChiSquarePValue(3.841459, 1), 1e-6)
+	assert.InDelta(t, 0.01, ChiSquarePValue(23.209251, 10), 1e-6)
+	assert.InDelta(t, 0.5, ChiSquarePValue(99.334129, 100), 1e-6)
+	assert.True(t, math.IsNaN(ChiSquarePValue(1, 0)))
+}
+
+func Test_NormalPValue(t *testing.T) {
+	assert.InDelta(t, 0.05, NormalPValue(1.959964), 1e-6)
+	assert.InDelta(t, 0.05, NormalPValue(-1.959964), 1e-6)
+	assert.Equal(t, 1.0, NormalPValue(0))
+}
+
+func Test_KolmogorovPValue(t *testing.T) {
+	assert.InDelta(t, 0.05, KolmogorovPValue(1.358099), 1e-5)
+	assert.InDelta(t, 0.01, KolmogorovPValue(1.627624), 1e-5)
+	assert.Equal(t, 1.0, KolmogorovPValue(0.1))
+}
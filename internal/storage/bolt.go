@@ -0,0 +1,214 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"fmt"
+	"slices"
+	"time"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+var (
+	kvBucket  = []byte("kv")
+	ttlBucket = []byte("ttl")
+)
+
+// maxSweep caps the number of expired keys removed per update
+const maxSweep = 128
+
+// BoltStore keeps the state in an embedded bolt database file.
+//
+// Values are stored in the kv bucket prefixed with their big endian expiry in
+// unix nanoseconds (0 never expires). The ttl bucket indexes expiring keys by
+// expiry followed by the key, so every update can remove a few expired keys.
+type BoltStore struct {
+	db  *bolt.DB
+	now func() time.Time
+}
+
+// OpenBolt opens (or creates) the database at path
+func OpenBolt(path string) (*BoltStore, error) {
+	db, err := bolt.Open(path, 0600, &bolt.Options{Timeout: 5 * time.Second})
+	if err != nil {
+		return nil, fmt.Errorf("failed to open store %s: %w", path, err)
+	}
+
+	err = db.Update(func(tx *bolt.Tx) error {
+		for _, name := range [][]byte{kvBucket, ttlBucket} {
+			_, errCreate := tx.CreateBucketIfNotExists(name)
+			if errCreate != nil {
+				return errCreate
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		_ = db.Close()
+		return nil, fmt.Errorf("failed to initialise store %s: %w", path, err)
+	}
+
+	return &BoltStore{
+		db:  db,
+		now: time.Now,
+	}, nil
+}
+
+func (s *BoltStore) Update(ctx context.Context, fn func(tx Tx) error) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	return s.db.Update(func(btx *bolt.Tx) error {
+		tx := newBoltTx(btx, s.now())
+		err := fn(tx)
+		if err != nil {
+			return err
+		}
+		return tx.sweep()
+	})
+}
+
+func (s *BoltStore) View(ctx context.Context, fn func(tx Tx) error) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	return s.db.View(func(btx *bolt.Tx) error {
+		return fn(newBoltTx(btx, s.now()))
+	})
+}
+
+func (s *BoltStore) Close() error {
+	return s.db.Close()
+}
+
+type boltTx struct {
+	tx  *bolt.Tx
+	kv  *bolt.Bucket
+	ttl *bolt.Bucket
+	now time.Time
+}
+
+func newBoltTx(tx *bolt.Tx, now time.Time) *boltTx {
+	return &boltTx{
+		tx:  tx,
+		kv:  tx.Bucket(kvBucket),
+		ttl: tx.Bucket(ttlBucket),
+		now: now,
+	}
+}
+
+// decode splits a stored value in its expiry and value, ok is false for expired values
+func (tx *boltTx) decode(stored []byte) (expiresAt int64, value []byte, ok bool) {
+	if len(stored) < 8 {
+		return 0, nil, false
+	}
+
+	expiresAt = int64(binary.BigEndian.Uint64(stored[:8])) // #nosec G115 -- written from an int64
+	if expiresAt != 0 && expiresAt <= tx.now.UnixNano() {
+		return expiresAt, nil, false
+	}
+	return expiresAt, stored[8:], true
+}
+
+func ttlKey(expiresAt int64, key string) []byte {
+	k := make([]byte, 8, 8+len(key))
+	binary.BigEndian.PutUint64(k, uint64(expiresAt)) // #nosec G115 -- expiries are positive
+	return append(k, key...)
+}
+
+func (tx *boltTx) Get(key string) ([]byte, error) {
+	_, value, ok := tx.decode(tx.kv.Get([]byte(key)))
+	if !ok {
+		return nil, ErrNotFound
+	}
+	// bolt values are only valid during the transaction
+	return slices.Clone(value), nil
+}
+
+func (tx *boltTx) Put(key string, value []byte, ttl time.Duration) error {
+	if !tx.tx.Writable() {
+		return ErrReadOnly
+	}
+
+	err := tx.Delete(key)
+	if err != nil {
+		return err
+	}
+
+	var expiresAt int64
+	if ttl > 0 {
+		expiresAt = tx.now.Add(ttl).UnixNano()
+		err = tx.ttl.Put(ttlKey(expiresAt, key), nil)
+		if err != nil {
+			return err
+		}
+	}
+
+	stored := make([]byte, 8, 8+len(value))
+	binary.BigEndian.PutUint64(stored, uint64(expiresAt)) // #nosec G115 -- expiries are positive
+	return tx.kv.Put([]byte(key), append(stored, value...))
+}
+
+func (tx *boltTx) Delete(key string) error {
+	if !tx.tx.Writable() {
+		return ErrReadOnly
+	}
+
+	stored := tx.kv.Get([]byte(key))
+	if stored == nil {
+		return nil
+	}
+
+	expiresAt, _, _ := tx.decode(stored)
+	if expiresAt != 0 {
+		err := tx.ttl.Delete(ttlKey(expiresAt, key))
+		if err != nil {
+			return err
+		}
+	}
+	return tx.kv.Delete([]byte(key))
+}
+
+func (tx *boltTx) Scan(prefix string, fn func(key string, value []byte) error) error {
+	p := []byte(prefix)
+	c := tx.kv.Cursor()
+	for k, v := c.Seek(p); k != nil && bytes.HasPrefix(k, p); k, v = c.Next() {
+		_, value, ok := tx.decode(v)
+		if !ok {
+			continue
+		}
+		err := fn(string(k), slices.Clone(value))
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// sweep removes up to maxSweep expired keys
+func (tx *boltTx) sweep() error {
+	var expired [][]byte
+	c := tx.ttl.Cursor()
+	for k, _ := c.First(); k != nil && len(expired) < maxSweep; k, _ = c.Next() {
+		if int64(binary.BigEndian.Uint64(k[:8])) > tx.now.UnixNano() { // #nosec G115 -- written from an int64
+			break
+		}
+		expired = append(expired, slices.Clone(k))
+	}
+
+	for _, k := range expired {
+		err := tx.ttl.Delete(k)
+		if err != nil {
+			return err
+		}
+		err = tx.kv.Delete(k[8:])
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
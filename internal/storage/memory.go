@@ -0,0 +1,167 @@
+package storage
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+type memoryEntry struct {
+	value     []byte
+	expiresAt time.Time
+}
+
+func (e memoryEntry) expired(now time.Time) bool {
+	return !e.expiresAt.IsZero() && !now.Before(e.expiresAt)
+}
+
+// sweepInterval is the number of updates between removals of expired keys
+const sweepInterval = 1024
+
+// MemoryStore keeps the state in memory, it is lost on restart.
+// Transactions are serialised by a single lock.
+type MemoryStore struct {
+	mu      sync.RWMutex
+	entries map[string]memoryEntry
+	updates int
+	now     func() time.Time
+}
+
+// NewMemoryStore creates an empty in-memory store
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		entries: map[string]memoryEntry{},
+		now:     time.Now,
+	}
+}
+
+func (s *MemoryStore) Update(ctx context.Context, fn func(tx Tx) error) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	tx := &memoryTx{store: s, writable: true, now: s.now(), writes: map[string]*memoryEntry{}}
+	err := fn(tx)
+	if err != nil {
+		return err
+	}
+
+	for key, entry := range tx.writes {
+		if entry == nil {
+			delete(s.entries, key)
+		} else {
+			s.entries[key] = *entry
+		}
+	}
+
+	s.updates++
+	if s.updates%sweepInterval == 0 {
+		for key, entry := range s.entries {
+			if entry.expired(tx.now) {
+				delete(s.entries, key)
+			}
+		}
+	}
+	return nil
+}
+
+func (s *MemoryStore) View(ctx context.Context, fn func(tx Tx) error) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return fn(&memoryTx{store: s, now: s.now()})
+}
+
+func (s *MemoryStore) Close() error {
+	return nil
+}
+
+// memoryTx buffers writes so a failing transaction leaves the store untouched.
+// A nil entry in writes marks a deleted key.
+type memoryTx struct {
+	store    *MemoryStore
+	writable bool
+	now      time.Time
+	writes   map[string]*memoryEntry
+}
+
+func (tx *memoryTx) lookup(key string) (memoryEntry, bool) {
+	if entry, ok := tx.writes[key]; ok {
+		if entry == nil {
+			return memoryEntry{}, false
+		}
+		return *entry, true
+	}
+
+	entry, ok := tx.store.entries[key]
+	if !ok || entry.expired(tx.now) {
+		return memoryEntry{}, false
+	}
+	return entry, true
+}
+
+func (tx *memoryTx) Get(key string) ([]byte, error) {
+	entry, ok := tx.lookup(key)
+	if !ok {
+		return nil, ErrNotFound
+	}
+	return slices.Clone(entry.value), nil
+}
+
+func (tx *memoryTx) Put(key string, value []byte, ttl time.Duration) error {
+	if !tx.writable {
+		return ErrReadOnly
+	}
+
+	entry := &memoryEntry{value: slices.Clone(value)}
+	if ttl > 0 {
+		entry.expiresAt = tx.now.Add(ttl)
+	}
+	tx.writes[key] = entry
+	return nil
+}
+
+func (tx *memoryTx) Delete(key string) error {
+	if !tx.writable {
+		return ErrReadOnly
+	}
+
+	tx.writes[key] = nil
+	return nil
+}
+
+func (tx *memoryTx) Scan(prefix string, fn func(key string, value []byte) error) error {
+	var keys []string
+	for key := range tx.store.entries {
+		if strings.HasPrefix(key, prefix) {
+			keys = append(keys, key)
+		}
+	}
+	for key := range tx.writes {
+		if _, ok := tx.store.entries[key]; !ok && strings.HasPrefix(key, prefix) {
+			keys = append(keys, key)
+		}
+	}
+	slices.Sort(keys)
+
+	for _, key := range keys {
+		entry, ok := tx.lookup(key)
+		if !ok {
+			continue
+		}
+		err := fn(key, slices.Clone(entry.value))
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
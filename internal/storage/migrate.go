@@ -0,0 +1,95 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strconv"
+)
+
+// schemaVersionKey holds the version of the last applied migration
+const schemaVersionKey = "meta/schema-version"
+
+// Migration changes the stored data from Version-1 to Version.
+type Migration struct {
+	Version int
+	Name    string
+	Up      func(tx Tx) error
+}
+
+// Migrations is the ordered list of migrations of the service state.
+// Append new migrations with the next version, never change applied ones.
+var Migrations = []Migration{}
+
+// SchemaVersion returns the version of the last applied migration, 0 for a new store
+func SchemaVersion(ctx context.Context, store Store) (int, error) {
+	version := 0
+	err := store.View(ctx, func(tx Tx) error {
+		var err error
+		version, err = readSchemaVersion(tx)
+		return err
+	})
+	return version, err
+}
+
+// Migrate applies the migrations that are newer than the schema version of the store.
+// Every migration runs in its own transaction together with the version update,
+// so concurrently starting instances apply each migration exactly once.
+func Migrate(ctx context.Context, store Store, migrations []Migration) error {
+	for i, m := range migrations {
+		if m.Version != i+1 {
+			return fmt.Errorf("migration %q has version %v, expected %v", m.Name, m.Version, i+1)
+		}
+	}
+
+	version, err := SchemaVersion(ctx, store)
+	if err != nil {
+		return err
+	} else if version > len(migrations) {
+		return fmt.Errorf("store schema version %v is newer than the %v known migrations", version, len(migrations))
+	}
+
+	for _, m := range migrations {
+		applied := false
+		errUpdate := store.Update(ctx, func(tx Tx) error {
+			applied = false
+			current, errRead := readSchemaVersion(tx)
+			if errRead != nil {
+				return errRead
+			} else if current >= m.Version {
+				return nil
+			}
+
+			errUp := m.Up(tx)
+			if errUp != nil {
+				return errUp
+			}
+
+			applied = true
+			return tx.Put(schemaVersionKey, []byte(strconv.Itoa(m.Version)), 0)
+		})
+		if errUpdate != nil {
+			return fmt.Errorf("migration %v (%s) failed: %w", m.Version, m.Name, errUpdate)
+		} else if applied {
+			slog.Info("applied store migration", "version", m.Version, "name", m.Name)
+		}
+	}
+
+	return nil
+}
+
+func readSchemaVersion(tx Tx) (int, error) {
+	v, err := tx.Get(schemaVersionKey)
+	if errors.Is(err, ErrNotFound) {
+		return 0, nil
+	} else if err != nil {
+		return 0, err
+	}
+
+	version, err := strconv.Atoi(string(v))
+	if err != nil {
+		return 0, fmt.Errorf("invalid schema version %q: %w", v, err)
+	}
+	return version, nil
+}
@@ -0,0 +1,205 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// maxRedisRetries is the number of times a conflicting transaction is retried
+const maxRedisRetries = 16
+
+// ErrConflict is returned when a transaction keeps conflicting with concurrent writers
+var ErrConflict = errors.New("transaction conflicted with concurrent updates")
+
+// RedisStore keeps the state in Redis or any server speaking the Redis protocol.
+//
+// Transactions are optimistic: every key read is WATCHed, writes are buffered
+// and sent in a single MULTI/EXEC when fn returns. When a watched key changed
+// in the meantime the transaction is run again. Scans are not isolated, keys
+// created by others during a transaction are not guaranteed to be seen.
+type RedisStore struct {
+	client *redis.Client
+}
+
+// OpenRedis connects to the server described by dsn, i.e. redis://:password@host:6379/0
+func OpenRedis(dsn string) (*RedisStore, error) {
+	opts, err := redis.ParseURL(dsn)
+	if err != nil {
+		return nil, fmt.Errorf("invalid redis store: %w", err)
+	}
+
+	client := redis.NewClient(opts)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	err = client.Ping(ctx).Err()
+	if err != nil {
+		_ = client.Close()
+		return nil, fmt.Errorf("failed to connect to redis store: %w", err)
+	}
+
+	return &RedisStore{
+		client: client,
+	}, nil
+}
+
+func (s *RedisStore) Update(ctx context.Context, fn func(tx Tx) error) error {
+	for attempt := 0; attempt < maxRedisRetries; attempt++ {
+		err := s.client.Watch(ctx, func(rtx *redis.Tx) error {
+			tx := &redisTx{ctx: ctx, rtx: rtx, writable: true, writes: map[string]*redisWrite{}}
+			errFn := fn(tx)
+			if errFn != nil {
+				return errFn
+			}
+			return tx.commit()
+		})
+		if errors.Is(err, redis.TxFailedErr) {
+			continue
+		}
+		return err
+	}
+
+	return ErrConflict
+}
+
+func (s *RedisStore) View(ctx context.Context, fn func(tx Tx) error) error {
+	return s.client.Watch(ctx, func(rtx *redis.Tx) error {
+		return fn(&redisTx{ctx: ctx, rtx: rtx})
+	})
+}
+
+func (s *RedisStore) Close() error {
+	return s.client.Close()
+}
+
+// redisWrite is a buffered write, a nil value deletes the key
+type redisWrite struct {
+	value []byte
+	ttl   time.Duration
+}
+
+type redisTx struct {
+	ctx      context.Context
+	rtx      *redis.Tx
+	writable bool
+	writes   map[string]*redisWrite
+}
+
+func (tx *redisTx) Get(key string) ([]byte, error) {
+	if w, ok := tx.writes[key]; ok {
+		if w.value == nil {
+			return nil, ErrNotFound
+		}
+		return slices.Clone(w.value), nil
+	}
+
+	if tx.writable {
+		err := tx.rtx.Watch(tx.ctx, key).Err()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	value, err := tx.rtx.Get(tx.ctx, key).Bytes()
+	if errors.Is(err, redis.Nil) {
+		return nil, ErrNotFound
+	} else if err != nil {
+		return nil, err
+	}
+	return value, nil
+}
+
+func (tx *redisTx) Put(key string, value []byte, ttl time.Duration) error {
+	if !tx.writable {
+		return ErrReadOnly
+	}
+
+	if value == nil {
+		value = []byte{}
+	}
+	tx.writes[key] = &redisWrite{value: slices.Clone(value), ttl: ttl}
+	return nil
+}
+
+func (tx *redisTx) Delete(key string) error {
+	if !tx.writable {
+		return ErrReadOnly
+	}
+
+	tx.writes[key] = &redisWrite{}
+	return nil
+}
+
+func (tx *redisTx) Scan(prefix string, fn func(key string, value []byte) error) error {
+	keys := map[string]bool{}
+	iter := tx.rtx.Scan(tx.ctx, 0, escapeGlob(prefix)+"*", 1000).Iterator()
+	for iter.Next(tx.ctx) {
+		keys[iter.Val()] = true
+	}
+	if err := iter.Err(); err != nil {
+		return err
+	}
+	for key := range tx.writes {
+		if strings.HasPrefix(key, prefix) {
+			keys[key] = true
+		}
+	}
+
+	sorted := make([]string, 0, len(keys))
+	for key := range keys {
+		sorted = append(sorted, key)
+	}
+	slices.Sort(sorted)
+
+	for _, key := range sorted {
+		value, err := tx.Get(key)
+		if errors.Is(err, ErrNotFound) {
+			continue
+		} else if err != nil {
+			return err
+		}
+
+		err = fn(key, value)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (tx *redisTx) commit() error {
+	if len(tx.writes) == 0 {
+		return nil
+	}
+
+	_, err := tx.rtx.TxPipelined(tx.ctx, func(pipe redis.Pipeliner) error {
+		for key, w := range tx.writes {
+			if w.value == nil {
+				pipe.Del(tx.ctx, key)
+			} else {
+				pipe.Set(tx.ctx, key, w.value, w.ttl)
+			}
+		}
+		return nil
+	})
+	return err
+}
+
+// escapeGlob escapes the characters that have a meaning in a Redis MATCH pattern
+func escapeGlob(s string) string {
+	var b strings.Builder
+	for _, r := range s {
+		if strings.ContainsRune(`*?[]\`, r) {
+			b.WriteRune('\\')
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
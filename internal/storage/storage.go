@@ -0,0 +1,72 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// ErrNotFound is returned by Tx.Get for keys that do not exist or have expired
+var ErrNotFound = errors.New("key not found")
+
+// Store is a transactional key-value store holding the state of the service.
+// Keys are flat strings, by convention prefixed with the owner, i.e. "sequence/<namespace>".
+type Store interface {
+	// Update runs fn in a read-write transaction. The changes are committed
+	// atomically when fn returns nil and discarded otherwise. fn can be called
+	// more than once when the backend retries a conflicting transaction.
+	Update(ctx context.Context, fn func(tx Tx) error) error
+	// View runs fn in a read-only transaction.
+	View(ctx context.Context, fn func(tx Tx) error) error
+	Close() error
+}
+
+// Tx is the set of operations available inside a transaction.
+type Tx interface {
+	// Get returns the value of key or ErrNotFound.
+	Get(key string) ([]byte, error)
+	// Put sets the value of key. A ttl larger than 0 expires the key after that duration.
+	Put(key string, value []byte, ttl time.Duration) error
+	// Delete removes key, removing a key that does not exist is not an error.
+	Delete(key string) error
+	// Scan calls fn for every key with prefix in ascending order, until fn returns an error.
+	Scan(prefix string, fn func(key string, value []byte) error) error
+}
+
+// ErrReadOnly is returned when writing inside a View transaction
+var ErrReadOnly = errors.New("transaction is read-only")
+
+// Persistent reports whether the store described by dsn keeps its state across restarts
+func Persistent(dsn string) bool {
+	return len(dsn) > 0 && dsn != "memory://"
+}
+
+// Open creates the store described by dsn:
+//
+//	memory:// or empty        in-memory, lost on restart
+//	file:///path/to/state.db  embedded bolt database, a bare path works as well
+//	redis://host:port/db      Redis (or any server speaking the Redis protocol)
+func Open(dsn string) (Store, error) {
+	if len(dsn) == 0 || dsn == "memory://" {
+		return NewMemoryStore(), nil
+	} else if !strings.Contains(dsn, "://") {
+		return OpenBolt(dsn)
+	}
+
+	u, err := url.Parse(dsn)
+	if err != nil {
+		return nil, fmt.Errorf("invalid store %q: %w", dsn, err)
+	}
+
+	switch u.Scheme {
+	case "file":
+		return OpenBolt(u.Path)
+	case "redis", "rediss":
+		return OpenRedis(dsn)
+	default:
+		return nil, fmt.Errorf("unsupported store scheme %q; use memory, file or redis", u.Scheme)
+	}
+}
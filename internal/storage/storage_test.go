@@ -0,0 +1,248 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/stretchr/testify/assert"
+)
+
+// backends opens every backend, Redis against an in-process stand-in.
+// advance moves the clock of the backend forward to expire keys.
+func backends(t *testing.T) map[string]struct {
+	store   Store
+	advance func(d time.Duration)
+} {
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	memory := NewMemoryStore()
+	memory.now = func() time.Time { return now }
+
+	bolt, err := OpenBolt(filepath.Join(t.TempDir(), "state.db"))
+	assert.Nil(t, err)
+	bolt.now = func() time.Time { return now }
+
+	mr := miniredis.RunT(t)
+	redis, err := Open("redis://" + mr.Addr() + "/0")
+	assert.Nil(t, err)
+
+	t.Cleanup(func() {
+		_ = bolt.Close()
+		_ = redis.Close()
+	})
+
+	return map[string]struct {
+		store   Store
+		advance func(d time.Duration)
+	}{
+		"memory": {memory, func(d time.Duration) { now = now.Add(d) }},
+		"bolt":   {bolt, func(d time.Duration) { now = now.Add(d) }},
+		"redis":  {redis, mr.FastForward},
+	}
+}
+
+func Test_Store(t *testing.T) {
+	ctx := context.Background()
+
+	for name, backend := range backends(t) {
+		t.Run(name, func(t *testing.T) {
+			store := backend.store
+
+			// Put, Get and Delete
+			err := store.Update(ctx, func(tx Tx) error {
+				assert.Nil(t, tx.Put("a/1", []byte("one"), 0))
+				assert.Nil(t, tx.Put("a/2", []byte("two"), 0))
+				assert.Nil(t, tx.Put("b/1", []byte("other"), 0))
+
+				v, errGet := tx.Get("a/1")
+				assert.Nil(t, errGet)
+				assert.Equal(t, []byte("one"), v)
+				return nil
+			})
+			assert.Nil(t, err)
+
+			err = store.View(ctx, func(tx Tx) error {
+				v, errGet := tx.Get("a/2")
+				assert.Nil(t, errGet)
+				assert.Equal(t, []byte("two"), v)
+
+				_, errGet = tx.Get("missing")
+				assert.ErrorIs(t, errGet, ErrNotFound)
+
+				assert.ErrorIs(t, tx.Put("a/3", nil, 0), ErrReadOnly)
+				return nil
+			})
+			assert.Nil(t, err)
+
+			// Scan in key order
+			err = store.View(ctx, func(tx Tx) error {
+				var keys []string
+				errScan := tx.Scan("a/", func(key string, value []byte) error {
+					keys = append(keys, key)
+					return nil
+				})
+				assert.Equal(t, []string{"a/1", "a/2"}, keys)
+				return errScan
+			})
+			assert.Nil(t, err)
+
+			// A failing transaction is rolled back
+			errFailed := errors.New("failed")
+			err = store.Update(ctx, func(tx Tx) error {
+				assert.Nil(t, tx.Put("a/1", []byte("changed"), 0))
+				assert.Nil(t, tx.Delete("a/2"))
+				return errFailed
+			})
+			assert.ErrorIs(t, err, errFailed)
+
+			err = store.View(ctx, func(tx Tx) error {
+				v, errGet := tx.Get("a/1")
+				assert.Nil(t, errGet)
+				assert.Equal(t, []byte("one"), v)
+				_, errGet = tx.Get("a/2")
+				assert.Nil(t, errGet)
+				return nil
+			})
+			assert.Nil(t, err)
+
+			// TTL
+			err = store.Update(ctx, func(tx Tx) error {
+				return tx.Put("ttl", []byte("soon gone"), time.Minute)
+			})
+			assert.Nil(t, err)
+
+			backend.advance(59 * time.Second)
+			err = store.View(ctx, func(tx Tx) error {
+				_, errGet := tx.Get("ttl")
+				return errGet
+			})
+			assert.Nil(t, err)
+
+			backend.advance(2 * time.Second)
+			err = store.View(ctx, func(tx Tx) error {
+				_, errGet := tx.Get("ttl")
+				return errGet
+			})
+			assert.ErrorIs(t, err, ErrNotFound)
+
+			// Delete
+			err = store.Update(ctx, func(tx Tx) error {
+				return tx.Delete("b/1")
+			})
+			assert.Nil(t, err)
+			err = store.View(ctx, func(tx Tx) error {
+				_, errGet := tx.Get("b/1")
+				return errGet
+			})
+			assert.ErrorIs(t, err, ErrNotFound)
+		})
+	}
+}
+
+func Test_Store_ConcurrentUpdates(t *testing.T) {
+	ctx := context.Background()
+
+	for name, backend := range backends(t) {
+		t.Run(name, func(t *testing.T) {
+			// Concurrent read-modify-write transactions must not lose updates
+			var wg sync.WaitGroup
+			for i := 0; i < 10; i++ {
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					err := backend.store.Update(ctx, func(tx Tx) error {
+						v, errGet := tx.Get("counter")
+						if errors.Is(errGet, ErrNotFound) {
+							v = nil
+						} else if errGet != nil {
+							return errGet
+						}
+						return tx.Put("counter", append(v, 'x'), 0)
+					})
+					assert.Nil(t, err)
+				}()
+			}
+			wg.Wait()
+
+			err := backend.store.View(ctx, func(tx Tx) error {
+				v, errGet := tx.Get("counter")
+				assert.Len(t, v, 10)
+				return errGet
+			})
+			assert.Nil(t, err)
+		})
+	}
+}
+
+func Test_Migrate(t *testing.T) {
+	ctx := context.Background()
+	store := NewMemoryStore()
+
+	applied := 0
+	migrations := []Migration{
+		{Version: 1, Name: "first", Up: func(tx Tx) error {
+			applied++
+			return tx.Put("m/1", []byte("1"), 0)
+		}},
+		{Version: 2, Name: "second", Up: func(tx Tx) error {
+			applied++
+			return tx.Put("m/2", []byte("2"), 0)
+		}},
+	}
+
+	assert.Nil(t, Migrate(ctx, store, migrations[:1]))
+	assert.Nil(t, Migrate(ctx, store, migrations))
+	assert.Nil(t, Migrate(ctx, store, migrations))
+	assert.Equal(t, 2, applied)
+
+	version, err := SchemaVersion(ctx, store)
+	assert.Nil(t, err)
+	assert.Equal(t, 2, version)
+
+	// Downgrades are refused
+	assert.NotNil(t, Migrate(ctx, store, migrations[:1]))
+
+	// Versions must be consecutive
+	assert.NotNil(t, Migrate(ctx, NewMemoryStore(), migrations[1:]))
+
+	// A failing migration leaves the version untouched
+	failing := append(migrations, Migration{Version: 3, Name: "failing", Up: func(tx Tx) error {
+		return errors.New("failed")
+	}})
+	assert.NotNil(t, Migrate(ctx, store, failing))
+	version, err = SchemaVersion(ctx, store)
+	assert.Nil(t, err)
+	assert.Equal(t, 2, version)
+}
+
+func Test_Open(t *testing.T) {
+	store, err := Open("")
+	assert.Nil(t, err)
+	assert.IsType(t, &MemoryStore{}, store)
+
+	store, err = Open(filepath.Join(t.TempDir(), "state.db"))
+	assert.Nil(t, err)
+	assert.IsType(t, &BoltStore{}, store)
+	assert.Nil(t, store.Close())
+
+	store, err = Open("file://" + filepath.Join(t.TempDir(), "state.db"))
+	assert.Nil(t, err)
+	assert.IsType(t, &BoltStore{}, store)
+	assert.Nil(t, store.Close())
+
+	_, err = Open("postgres://localhost")
+	assert.NotNil(t, err)
+}
+
+func Test_Persistent(t *testing.T) {
+	assert.False(t, Persistent(""))
+	assert.False(t, Persistent("memory://"))
+	assert.True(t, Persistent("state.db"))
+	assert.True(t, Persistent("file:///var/lib/random/state.db"))
+	assert.True(t, Persistent("redis://localhost:6379/0"))
+}
@@ -0,0 +1,334 @@
+// Package unseal splits a seed into Shamir shares for dual control and
+// collects the shares at startup, so no single operator ever holds the seed.
+package unseal
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/fasttrack-solutions/random"
+	"github.com/fasttrack-solutions/random/internal/shamir"
+)
+
+const sharePrefix = "rs1"
+
+var (
+	// ErrInvalidShare is returned for a share that can not be parsed or whose checksum does not match
+	ErrInvalidShare = errors.New("invalid share")
+	// ErrShareMismatch is returned for a share of another seed or threshold than the shares collected before
+	ErrShareMismatch = errors.New("share does not belong to the seed being collected")
+)
+
+// Share is one share of a seed, in text: rs1-<fingerprint>-<threshold>-<hex share>-<checksum>
+type Share struct {
+	// Fingerprint is the fingerprint of the seed the share belongs to
+	Fingerprint string
+	Threshold   int
+	// Data is the Shamir share: its x coordinate and one byte per byte of the seed
+	Data []byte
+}
+
+// String encodes the share with a checksum that catches copy mistakes
+func (s Share) String() string {
+	body := fmt.Sprintf("%s-%s-%v-%s", sharePrefix, s.Fingerprint, s.Threshold, hex.EncodeToString(s.Data))
+	return body + "-" + checksum(body)
+}
+
+// Index is the x coordinate of the share, from 1
+func (s Share) Index() int {
+	if len(s.Data) == 0 {
+		return 0
+	}
+	return int(s.Data[0])
+}
+
+// ParseShare decodes a share created by Split
+func ParseShare(text string) (Share, error) {
+	text = strings.TrimSpace(text)
+	parts := strings.Split(text, "-")
+	if len(parts) != 5 || parts[0] != sharePrefix {
+		return Share{}, fmt.Errorf("%w: expected %s-<fingerprint>-<threshold>-<share>-<checksum>", ErrInvalidShare, sharePrefix)
+	}
+
+	body := strings.Join(parts[:4], "-")
+	if checksum(body) != parts[4] {
+		return Share{}, fmt.Errorf("%w: checksum mismatch, the share was not copied correctly", ErrInvalidShare)
+	}
+
+	threshold, err := strconv.Atoi(parts[2])
+	if err != nil || threshold < 2 {
+		return Share{}, fmt.Errorf("%w: invalid threshold", ErrInvalidShare)
+	}
+	data, err := hex.DecodeString(parts[3])
+	if err != nil || len(data) != 33 || data[0] == 0 {
+		return Share{}, fmt.Errorf("%w: invalid share data", ErrInvalidShare)
+	}
+
+	return Share{
+		Fingerprint: parts[1],
+		Threshold:   threshold,
+		Data:        data,
+	}, nil
+}
+
+func checksum(body string) string {
+	sum := sha256.Sum256([]byte(body))
+	return hex.EncodeToString(sum[:4])
+}
+
+// Split validates the seed and splits it into n shares of which any k recover it
+func Split(seedHex string, n int, k int) ([]Share, error) {
+	err := random.ValidateSeed(seedHex)
+	if err != nil {
+		return nil, err
+	}
+	fingerprint, err := random.SeedFingerprint(seedHex)
+	if err != nil {
+		return nil, err
+	}
+	seed, err := hex.DecodeString(seedHex)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := shamir.Split(seed, n, k)
+	if err != nil {
+		return nil, err
+	}
+
+	shares := make([]Share, len(data))
+	for i := range data {
+		shares[i] = Share{Fingerprint: fingerprint, Threshold: k, Data: data[i]}
+	}
+	return shares, nil
+}
+
+// Combine recovers the seed from at least threshold shares of it, and checks it against their fingerprint
+func Combine(shares []Share) (string, error) {
+	if len(shares) == 0 {
+		return "", shamir.ErrTooFewShares
+	}
+
+	first := shares[0]
+	data := make([][]byte, len(shares))
+	for i, s := range shares {
+		if s.Fingerprint != first.Fingerprint || s.Threshold != first.Threshold {
+			return "", ErrShareMismatch
+		}
+		data[i] = s.Data
+	}
+	if len(shares) < first.Threshold {
+		return "", fmt.Errorf("%v of %v shares, more are required", len(shares), first.Threshold)
+	}
+
+	seed, err := shamir.Combine(data)
+	if err != nil {
+		return "", err
+	}
+	seedHex := hex.EncodeToString(seed)
+	clear(seed)
+
+	fingerprint, err := random.SeedFingerprint(seedHex)
+	if err != nil {
+		return "", err
+	} else if fingerprint != first.Fingerprint {
+		return "", errors.New("the combined seed does not match the fingerprint of the shares")
+	}
+	return seedHex, nil
+}
+
+// Status is the progress of a Collector
+type Status struct {
+	Fingerprint string `json:"fingerprint,omitempty"`
+	Threshold   int    `json:"threshold,omitempty"`
+	Received    int    `json:"received"`
+	Unsealed    bool   `json:"unsealed"`
+}
+
+// Collector gathers shares until their threshold is reached and the seed is recovered
+type Collector struct {
+	fingerprint string
+
+	mu     sync.Mutex
+	shares []Share
+	seed   string
+	done   chan struct{}
+}
+
+// NewCollector creates a collector. When fingerprint is set only shares of that seed are accepted.
+func NewCollector(fingerprint string) *Collector {
+	return &Collector{
+		fingerprint: fingerprint,
+		done:        make(chan struct{}),
+	}
+}
+
+// Add adds a share and combines the shares once the threshold is reached. When
+// they do not combine into the seed of their fingerprint the share is rejected,
+// the shares collected before it are kept.
+func (c *Collector) Add(s Share) (Status, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if len(c.seed) > 0 {
+		return c.status(), nil
+	} else if len(c.fingerprint) > 0 && s.Fingerprint != c.fingerprint {
+		return c.status(), ErrShareMismatch
+	} else if len(c.shares) > 0 && (s.Fingerprint != c.shares[0].Fingerprint || s.Threshold != c.shares[0].Threshold) {
+		return c.status(), ErrShareMismatch
+	}
+	for _, collected := range c.shares {
+		if collected.Index() == s.Index() {
+			return c.status(), fmt.Errorf("%w %v", shamir.ErrDuplicateShare, s.Index())
+		}
+	}
+
+	c.shares = append(c.shares, s)
+	if len(c.shares) < s.Threshold {
+		return c.status(), nil
+	}
+
+	seedHex, err := Combine(c.shares)
+	if err != nil {
+		// Anyone who knows the fingerprint can forge a share, starting over would
+		// let a forged share throw away the valid ones
+		c.shares = c.shares[:len(c.shares)-1]
+		return c.status(), fmt.Errorf("share %v rejected, the shares do not combine into the seed: %w", s.Index(), err)
+	}
+
+	c.seed = seedHex
+	close(c.done)
+	return c.status(), nil
+}
+
+// Status returns the progress of the collector
+func (c *Collector) Status() Status {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.status()
+}
+
+func (c *Collector) status() Status {
+	st := Status{
+		Fingerprint: c.fingerprint,
+		Received:    len(c.shares),
+		Unsealed:    len(c.seed) > 0,
+	}
+	if len(c.shares) > 0 {
+		st.Fingerprint = c.shares[0].Fingerprint
+		st.Threshold = c.shares[0].Threshold
+	}
+	return st
+}
+
+// Done is closed once the seed is recovered
+func (c *Collector) Done() <-chan struct{} {
+	return c.done
+}
+
+// Seed returns the recovered seed, empty until Done is closed
+func (c *Collector) Seed() string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.seed
+}
+
+// ServeHTTP serves the admin endpoint: GET /shares returns the status, POST /shares adds the share in the body
+func (c *Collector) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path != "/shares" {
+		http.NotFound(w, r)
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		writeStatus(w, http.StatusOK, c.Status())
+	case http.MethodPost:
+		body, err := io.ReadAll(io.LimitReader(r.Body, 1<<10))
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		share, err := ParseShare(string(body))
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		st, err := c.Add(share)
+		if errors.Is(err, ErrShareMismatch) || errors.Is(err, shamir.ErrDuplicateShare) {
+			http.Error(w, err.Error(), http.StatusConflict)
+			return
+		} else if err != nil {
+			http.Error(w, err.Error(), http.StatusUnprocessableEntity)
+			return
+		}
+
+		slog.Info("seed share received", "fingerprint", st.Fingerprint, "received", st.Received, "threshold", st.Threshold)
+		writeStatus(w, http.StatusOK, st)
+	default:
+		w.Header().Set("Allow", "GET, POST")
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+func writeStatus(w http.ResponseWriter, code int, st Status) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	_ = json.NewEncoder(w).Encode(st)
+}
+
+// Collect serves the admin endpoint on addr until enough shares are submitted
+// and returns the recovered seed. The endpoint is closed before returning. It
+// has no authentication, so addr must be a loopback address.
+func Collect(ctx context.Context, addr string, fingerprint string) (string, error) {
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return "", err
+	}
+	if tcpAddr, ok := listener.Addr().(*net.TCPAddr); !ok || !tcpAddr.IP.IsLoopback() {
+		_ = listener.Close()
+		return "", fmt.Errorf("the seed share endpoint must listen on a loopback address, i.e. 127.0.0.1:3403, not %q", addr)
+	}
+
+	collector := NewCollector(fingerprint)
+	server := &http.Server{
+		Handler:           collector,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	serveErr := make(chan error, 1)
+	go func() {
+		serveErr <- server.Serve(listener)
+	}()
+	slog.Info("waiting for seed shares", "addr", listener.Addr().String(), "fingerprint", fingerprint)
+
+	select {
+	case <-collector.Done():
+	case err = <-serveErr:
+		return "", err
+	case <-ctx.Done():
+		_ = server.Close()
+		return "", ctx.Err()
+	}
+
+	// Let the response to the last share finish
+	shutdownCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+	_ = server.Shutdown(shutdownCtx)
+
+	return collector.Seed(), nil
+}
@@ -0,0 +1,103 @@
+package unseal
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const testSeed = "0f6aa358754a2b1fac2206849282b6c68dd6e086f635df2796acea52ae920ddc"
+
+func Test_Split_Combine(t *testing.T) {
+	shares, err := Split(testSeed, 5, 3)
+	assert.Nil(t, err)
+	assert.Len(t, shares, 5)
+
+	parsed := make([]Share, len(shares))
+	for i, s := range shares {
+		assert.True(t, strings.HasPrefix(s.String(), "rs1-"))
+		parsed[i], err = ParseShare(s.String() + "\n")
+		assert.Nil(t, err)
+		assert.Equal(t, s, parsed[i])
+		assert.Equal(t, i+1, parsed[i].Index())
+	}
+
+	seedHex, err := Combine([]Share{parsed[4], parsed[0], parsed[2]})
+	assert.Nil(t, err)
+	assert.Equal(t, testSeed, seedHex)
+
+	_, err = Combine(parsed[:2])
+	assert.EqualError(t, err, "2 of 3 shares, more are required")
+
+	other, err := Split("c8bfdd8e79e4a001038ac5c0370f1eb7cd222f97333c9657e17483233729f282", 5, 3)
+	assert.Nil(t, err)
+	_, err = Combine([]Share{parsed[0], parsed[1], other[2]})
+	assert.ErrorIs(t, err, ErrShareMismatch)
+
+	// A typo is caught by the checksum
+	text := shares[0].String()
+	typo := text[:30] + string(text[30]^1) + text[31:]
+	_, err = ParseShare(typo)
+	assert.ErrorIs(t, err, ErrInvalidShare)
+
+	_, err = Split(strings.Repeat("ab", 32), 5, 3)
+	assert.NotNil(t, err)
+}
+
+func Test_Collector(t *testing.T) {
+	shares, err := Split(testSeed, 3, 2)
+	assert.Nil(t, err)
+	other, err := Split("c8bfdd8e79e4a001038ac5c0370f1eb7cd222f97333c9657e17483233729f282", 3, 2)
+	assert.Nil(t, err)
+
+	c := NewCollector(shares[0].Fingerprint)
+	server := httptest.NewServer(c)
+	defer server.Close()
+
+	post := func(body string) int {
+		resp, errPost := http.Post(server.URL+"/shares", "text/plain", strings.NewReader(body))
+		assert.Nil(t, errPost)
+		_ = resp.Body.Close()
+		return resp.StatusCode
+	}
+
+	assert.Equal(t, http.StatusBadRequest, post("not a share"))
+	assert.Equal(t, http.StatusConflict, post(other[0].String()))
+	assert.Equal(t, http.StatusOK, post(shares[1].String()))
+	assert.Equal(t, http.StatusConflict, post(shares[1].String()))
+	assert.Equal(t, Status{Fingerprint: shares[0].Fingerprint, Threshold: 2, Received: 1}, c.Status())
+
+	select {
+	case <-c.Done():
+		t.Fatal("unsealed before the threshold")
+	default:
+	}
+
+	// A forged share with the public fingerprint is rejected, the valid share is kept
+	forged := Share{Fingerprint: shares[0].Fingerprint, Threshold: 2, Data: append([]byte{3}, make([]byte, 32)...)}
+	assert.Equal(t, http.StatusUnprocessableEntity, post(forged.String()))
+	assert.Equal(t, Status{Fingerprint: shares[0].Fingerprint, Threshold: 2, Received: 1}, c.Status())
+
+	assert.Equal(t, http.StatusOK, post(shares[2].String()))
+	<-c.Done()
+	assert.Equal(t, testSeed, c.Seed())
+	assert.True(t, c.Status().Unsealed)
+}
+
+func Test_Collect(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err := Collect(ctx, "127.0.0.1:0", "")
+	assert.ErrorIs(t, err, context.Canceled)
+
+	_, err = Collect(context.Background(), "256.0.0.1:0", "")
+	assert.NotNil(t, err)
+
+	// The endpoint has no authentication, it never listens on other interfaces
+	_, err = Collect(context.Background(), ":0", "")
+	assert.ErrorContains(t, err, "loopback")
+}
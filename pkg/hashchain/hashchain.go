@@ -0,0 +1,248 @@
+// Package hashchain builds reverse hash chains for crash and multiplier games.
+// Every link is the SHA-256 of the link before it and the rounds consume the
+// chain from its end: round 1 uses the link before the terminating hash,
+// round 2 the link before that, and so on. Once the terminating hash is
+// published no seed can be changed, and hashing the seed of a round gives the
+// seed of the round before it.
+package hashchain
+
+import (
+	"bufio"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"sync"
+)
+
+// HashSize is the size of a link
+const HashSize = sha256.Size
+
+// fileMagic starts the binary encoding of a chain
+var fileMagic = []byte("RHCHAIN1")
+
+var (
+	// ErrExhausted is returned for a round beyond the length of the chain
+	ErrExhausted = errors.New("hash chain is exhausted")
+	// ErrInvalidSeed is returned when a round seed does not lead to the terminating hash
+	ErrInvalidSeed = errors.New("round seed does not lead to the terminating hash")
+)
+
+// Chain is a hash chain of which only every interval-th link is kept, the
+// links in between are recomputed from the checkpoint before them
+type Chain struct {
+	length      int64
+	interval    int64
+	checkpoints [][HashSize]byte
+	terminal    [HashSize]byte
+
+	// segment caches the links after the checkpoint used last, rounds are served in order
+	mu           sync.Mutex
+	segmentIndex int64
+	segment      [][HashSize]byte
+}
+
+// Generate builds a chain of length links from a seed drawn from crypto/rand
+func Generate(length int64, interval int64) (*Chain, error) {
+	seed := make([]byte, HashSize)
+	_, err := rand.Read(seed)
+	if err != nil {
+		return nil, err
+	}
+	return Build(seed, length, interval)
+}
+
+// Build builds a chain of length links from seed, keeping every interval-th link.
+// An interval beyond the length keeps the first link only.
+func Build(seed []byte, length int64, interval int64) (*Chain, error) {
+	if len(seed) != HashSize {
+		return nil, fmt.Errorf("seed must be %v bytes", HashSize)
+	} else if length < 1 {
+		return nil, errors.New("length must be at least 1")
+	} else if interval < 1 {
+		return nil, errors.New("checkpoint interval must be at least 1")
+	}
+	interval = min(interval, length)
+
+	c := &Chain{
+		length:       length,
+		interval:     interval,
+		checkpoints:  make([][HashSize]byte, 0, length/interval+1),
+		segmentIndex: -1,
+	}
+
+	link := [HashSize]byte(seed)
+	for i := int64(0); i < length; i++ {
+		if i%interval == 0 {
+			c.checkpoints = append(c.checkpoints, link)
+		}
+		link = sha256.Sum256(link[:])
+	}
+	c.terminal = link
+	return c, nil
+}
+
+// Length is the number of rounds of the chain
+func (c *Chain) Length() int64 {
+	return c.length
+}
+
+// Terminal returns the terminating hash, the link after the seed of round 1, to publish before the first round
+func (c *Chain) Terminal() []byte {
+	return append([]byte(nil), c.terminal[:]...)
+}
+
+// Round returns the seed of round, from 1 to Length
+func (c *Chain) Round(round int64) ([]byte, error) {
+	if round < 1 {
+		return nil, errors.New("round must be at least 1")
+	} else if round > c.length {
+		return nil, fmt.Errorf("%w after %v rounds", ErrExhausted, c.length)
+	}
+
+	i := c.length - round
+	segmentIndex := i / c.interval
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if segmentIndex != c.segmentIndex {
+		size := min(c.interval, c.length-segmentIndex*c.interval)
+		if cap(c.segment) < int(size) {
+			c.segment = make([][HashSize]byte, size)
+		}
+		c.segment = c.segment[:size]
+
+		link := c.checkpoints[segmentIndex]
+		for j := range c.segment {
+			c.segment[j] = link
+			link = sha256.Sum256(link[:])
+		}
+		c.segmentIndex = segmentIndex
+	}
+
+	link := c.segment[i-segmentIndex*c.interval]
+	return link[:], nil
+}
+
+// WriteTo writes the chain: its length, interval, terminating hash and checkpoints.
+// The checkpoints reveal the seeds of the rounds, keep the file as secret as a seed.
+func (c *Chain) WriteTo(w io.Writer) (int64, error) {
+	bw := bufio.NewWriter(w)
+	var n int64
+	write := func(b []byte) {
+		m, _ := bw.Write(b)
+		n += int64(m)
+	}
+
+	write(fileMagic)
+	write(binary.BigEndian.AppendUint64(nil, uint64(c.length)))   // #nosec G115 -- length is at least 1
+	write(binary.BigEndian.AppendUint64(nil, uint64(c.interval))) // #nosec G115 -- interval is at least 1
+	write(c.terminal[:])
+	for _, checkpoint := range c.checkpoints {
+		write(checkpoint[:])
+	}
+	return n, bw.Flush()
+}
+
+// Read reads a chain written by WriteTo and checks its terminating hash
+func Read(r io.Reader) (*Chain, error) {
+	br := bufio.NewReader(r)
+	header := make([]byte, len(fileMagic)+16+HashSize)
+	_, err := io.ReadFull(br, header)
+	if err != nil {
+		return nil, fmt.Errorf("invalid hash chain: %w", err)
+	} else if string(header[:len(fileMagic)]) != string(fileMagic) {
+		return nil, errors.New("invalid hash chain: not a hash chain file")
+	}
+
+	length := binary.BigEndian.Uint64(header[len(fileMagic):])
+	interval := binary.BigEndian.Uint64(header[len(fileMagic)+8:])
+	if length < 1 || length > math.MaxInt64 || interval < 1 {
+		return nil, errors.New("invalid hash chain: invalid length or interval")
+	}
+
+	c := &Chain{
+		length: int64(length), // #nosec G115 -- checked above
+		// Chains built before Build clamped the interval may have one beyond the length
+		interval:     int64(min(interval, length)), // #nosec G115 -- checked above
+		terminal:     [HashSize]byte(header[len(fileMagic)+16:]),
+		segmentIndex: -1,
+	}
+	// The checkpoints are read one by one, a corrupt header must not size a huge allocation
+	count := (c.length-1)/c.interval + 1
+	for range count {
+		var checkpoint [HashSize]byte
+		_, err = io.ReadFull(br, checkpoint[:])
+		if err != nil {
+			return nil, fmt.Errorf("invalid hash chain: %v checkpoints of %v: %w", len(c.checkpoints), count, err)
+		}
+		c.checkpoints = append(c.checkpoints, checkpoint)
+	}
+
+	// The last checkpoint leads to the terminating hash, this catches a corrupt file
+	link := c.checkpoints[count-1]
+	for i := (count - 1) * c.interval; i < c.length; i++ {
+		link = sha256.Sum256(link[:])
+	}
+	if link != c.terminal {
+		return nil, errors.New("invalid hash chain: checkpoints do not lead to the terminating hash")
+	}
+	return c, nil
+}
+
+// Verify checks that the seed of round leads to the terminating hash, by
+// hashing it round times. The rounds before it are proven along the way.
+func Verify(terminal []byte, round int64, seed []byte) error {
+	if round < 1 {
+		return errors.New("round must be at least 1")
+	} else if len(seed) != HashSize {
+		return fmt.Errorf("seed must be %v bytes", HashSize)
+	}
+
+	link := [HashSize]byte(seed)
+	for range round {
+		link = sha256.Sum256(link[:])
+	}
+	if subtle.ConstantTimeCompare(link[:], terminal) != 1 {
+		return ErrInvalidSeed
+	}
+	return nil
+}
+
+// VerifyNext checks that seed is the seed of the round after the one of previous
+func VerifyNext(previous []byte, seed []byte) error {
+	link := sha256.Sum256(seed)
+	if subtle.ConstantTimeCompare(link[:], previous) != 1 {
+		return ErrInvalidSeed
+	}
+	return nil
+}
+
+// CrashMultiplier derives the crash point of a round from its seed, in hundredths:
+// 100 is a crash at 1.00x. A player cashing out at any multiplier m wins with a
+// probability of (1 - houseEdge) / m, so the return to player is 1 - houseEdge.
+// The round crashes at 1.00x, below the first cash out at 1.01x, with a
+// probability of 1 - (1 - houseEdge) / 1.01.
+func CrashMultiplier(seed []byte, houseEdge float64) (int64, error) {
+	if len(seed) != HashSize {
+		return 0, fmt.Errorf("seed must be %v bytes", HashSize)
+	} else if houseEdge < 0 || houseEdge >= 1 || math.IsNaN(houseEdge) {
+		return 0, errors.New("house edge must be at least 0 and less than 1")
+	}
+
+	// 52 bits are exact in a float64, x is uniform in [0, 1)
+	x := float64(binary.BigEndian.Uint64(seed[:8])>>12) / (1 << 52)
+
+	multiplier := math.Floor(100 * (1 - houseEdge) / (1 - x))
+	if multiplier < 100 {
+		return 100, nil
+	} else if multiplier > math.MaxInt64/2 {
+		return math.MaxInt64 / 2, nil
+	}
+	return int64(multiplier), nil
+}
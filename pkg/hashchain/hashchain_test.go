@@ -0,0 +1,168 @@
+package hashchain
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/binary"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Chain(t *testing.T) {
+	seed := bytes.Repeat([]byte{7}, HashSize)
+	c, err := Build(seed, 1000, 64)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(1000), c.Length())
+
+	// Walk the chain forwards to compare
+	links := [][]byte{seed}
+	for i := 1; i <= 1000; i++ {
+		link := sha256.Sum256(links[i-1])
+		links = append(links, link[:])
+	}
+	assert.Equal(t, links[1000], c.Terminal())
+
+	previous := c.Terminal()
+	for round := int64(1); round <= 1000; round++ {
+		s, errRound := c.Round(round)
+		assert.Nil(t, errRound)
+		assert.Equal(t, links[1000-round], s)
+		assert.Nil(t, VerifyNext(previous, s))
+		previous = s
+	}
+
+	// Out of order
+	s, err := c.Round(3)
+	assert.Nil(t, err)
+	assert.Equal(t, links[997], s)
+	assert.Nil(t, Verify(c.Terminal(), 3, s))
+	assert.ErrorIs(t, Verify(c.Terminal(), 4, s), ErrInvalidSeed)
+
+	_, err = c.Round(1001)
+	assert.ErrorIs(t, err, ErrExhausted)
+	_, err = c.Round(0)
+	assert.NotNil(t, err)
+}
+
+func Test_WriteTo_Read(t *testing.T) {
+	c, err := Generate(1001, 100)
+	assert.Nil(t, err)
+
+	var buf bytes.Buffer
+	n, err := c.WriteTo(&buf)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(buf.Len()), n)
+	assert.Equal(t, 8+16+HashSize+11*HashSize, buf.Len())
+
+	read, err := Read(bytes.NewReader(buf.Bytes()))
+	assert.Nil(t, err)
+	assert.Equal(t, c.Terminal(), read.Terminal())
+	for _, round := range []int64{1, 500, 1001} {
+		expected, errRound := c.Round(round)
+		assert.Nil(t, errRound)
+		s, errRound := read.Round(round)
+		assert.Nil(t, errRound)
+		assert.Equal(t, expected, s)
+	}
+
+	corrupt := bytes.Clone(buf.Bytes())
+	corrupt[len(corrupt)-1] ^= 1
+	_, err = Read(bytes.NewReader(corrupt))
+	assert.EqualError(t, err, "invalid hash chain: checkpoints do not lead to the terminating hash")
+
+	_, err = Read(bytes.NewReader(buf.Bytes()[:100]))
+	assert.NotNil(t, err)
+
+	// A header claiming more checkpoints than follow fails without allocating them
+	huge := bytes.Clone(buf.Bytes())
+	binary.BigEndian.PutUint64(huge[len(fileMagic):], 1<<62)
+	binary.BigEndian.PutUint64(huge[len(fileMagic)+8:], 1)
+	_, err = Read(bytes.NewReader(huge))
+	assert.ErrorIs(t, err, io.EOF)
+}
+
+func Test_WriteTo_Read_ShortChain(t *testing.T) {
+	// A chain shorter than the checkpoint interval, as randomctl chain generate -length 500 writes
+	c, err := Build(bytes.Repeat([]byte{3}, HashSize), 10, 1000)
+	assert.Nil(t, err)
+
+	var buf bytes.Buffer
+	_, err = c.WriteTo(&buf)
+	assert.Nil(t, err)
+	assert.Equal(t, 8+16+HashSize+HashSize, buf.Len())
+
+	read, err := Read(bytes.NewReader(buf.Bytes()))
+	assert.Nil(t, err)
+	assert.Equal(t, c.Terminal(), read.Terminal())
+	for round := int64(1); round <= 10; round++ {
+		expected, errRound := c.Round(round)
+		assert.Nil(t, errRound)
+		s, errRound := read.Round(round)
+		assert.Nil(t, errRound)
+		assert.Equal(t, expected, s)
+		assert.Nil(t, Verify(read.Terminal(), round, s))
+	}
+
+	// Files written before the interval was clamped still load
+	binary.BigEndian.PutUint64(buf.Bytes()[len(fileMagic)+8:], 1000)
+	read, err = Read(bytes.NewReader(buf.Bytes()))
+	assert.Nil(t, err)
+	assert.Equal(t, c.Terminal(), read.Terminal())
+}
+
+func Test_CrashMultiplier(t *testing.T) {
+	seed := make([]byte, HashSize)
+	m, err := CrashMultiplier(seed, 0.01)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(100), m)
+
+	// x = 0.5 crashes at (1 - 0.01) / 0.5
+	seed[0] = 0x80
+	m, err = CrashMultiplier(seed, 0.01)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(198), m)
+	m, err = CrashMultiplier(seed, 0)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(200), m)
+
+	_, err = CrashMultiplier(seed, 1)
+	assert.NotNil(t, err)
+	_, err = CrashMultiplier(seed[:8], 0.01)
+	assert.NotNil(t, err)
+
+	// A round crashes at 1.00x while x is below 1 - (1 - house edge) / 1.01
+	instant := 1 - (1-0.04)/1.01
+	for _, tc := range []struct {
+		x    float64
+		want int64
+	}{
+		{instant - 1e-9, 100},
+		{instant + 1e-9, 101},
+	} {
+		binary.BigEndian.PutUint64(seed, uint64(tc.x*(1<<52))<<12)
+		m, err = CrashMultiplier(seed, 0.04)
+		assert.Nil(t, err)
+		assert.Equal(t, tc.want, m, "x = %v", tc.x)
+	}
+
+	// The return to player of cashing out at 2.00x is 1 - house edge
+	c, err := Build(bytes.Repeat([]byte{1}, HashSize), 200000, 1000)
+	assert.Nil(t, err)
+	wins, crashes := 0, 0
+	for round := int64(1); round <= c.Length(); round++ {
+		s, errRound := c.Round(round)
+		assert.Nil(t, errRound)
+		m, errRound = CrashMultiplier(s, 0.04)
+		assert.Nil(t, errRound)
+		if m >= 200 {
+			wins++
+		} else if m == 100 {
+			crashes++
+		}
+	}
+	rtp := 2 * float64(wins) / float64(c.Length())
+	assert.InDelta(t, 0.96, rtp, 0.01)
+	assert.InDelta(t, instant, float64(crashes)/float64(c.Length()), 0.002)
+}
@@ -22,9 +22,10 @@ const (
 )
 
 type GetRandomFloat64Request struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	IdempotencyKey string                 `protobuf:"bytes,1,opt,name=idempotency_key,json=idempotencyKey,proto3" json:"idempotency_key,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
 }
 
 func (x *GetRandomFloat64Request) Reset() {
@@ -57,9 +58,18 @@ func (*GetRandomFloat64Request) Descriptor() ([]byte, []int) {
 	return file_pkg_pb_service_proto_rawDescGZIP(), []int{0}
 }
 
+func (x *GetRandomFloat64Request) GetIdempotencyKey() string {
+	if x != nil {
+		return x.IdempotencyKey
+	}
+	return ""
+}
+
 type GetRandomFloat64Response struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Number        float64                `protobuf:"fixed64,1,opt,name=number,proto3" json:"number,omitempty"`
+	Replayed      bool                   `protobuf:"varint,2,opt,name=replayed,proto3" json:"replayed,omitempty"`
+	Receipt       *Receipt               `protobuf:"bytes,15,opt,name=receipt,proto3" json:"receipt,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -101,12 +111,27 @@ func (x *GetRandomFloat64Response) GetNumber() float64 {
 	return 0
 }
 
+func (x *GetRandomFloat64Response) GetReplayed() bool {
+	if x != nil {
+		return x.Replayed
+	}
+	return false
+}
+
+func (x *GetRandomFloat64Response) GetReceipt() *Receipt {
+	if x != nil {
+		return x.Receipt
+	}
+	return nil
+}
+
 type GetRandomInt64Request struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Min           int32                  `protobuf:"varint,1,opt,name=min,proto3" json:"min,omitempty"`
-	Max           int32                  `protobuf:"varint,2,opt,name=max,proto3" json:"max,omitempty"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	Min            int32                  `protobuf:"varint,1,opt,name=min,proto3" json:"min,omitempty"`
+	Max            int32                  `protobuf:"varint,2,opt,name=max,proto3" json:"max,omitempty"`
+	IdempotencyKey string                 `protobuf:"bytes,3,opt,name=idempotency_key,json=idempotencyKey,proto3" json:"idempotency_key,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
 }
 
 func (x *GetRandomInt64Request) Reset() {
@@ -153,9 +178,18 @@ func (x *GetRandomInt64Request) GetMax() int32 {
 	return 0
 }
 
+func (x *GetRandomInt64Request) GetIdempotencyKey() string {
+	if x != nil {
+		return x.IdempotencyKey
+	}
+	return ""
+}
+
 type GetRandomInt64Response struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Number        int64                  `protobuf:"varint,1,opt,name=number,proto3" json:"number,omitempty"`
+	Replayed      bool                   `protobuf:"varint,2,opt,name=replayed,proto3" json:"replayed,omitempty"`
+	Receipt       *Receipt               `protobuf:"bytes,15,opt,name=receipt,proto3" json:"receipt,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -197,10 +231,27 @@ func (x *GetRandomInt64Response) GetNumber() int64 {
 	return 0
 }
 
+func (x *GetRandomInt64Response) GetReplayed() bool {
+	if x != nil {
+		return x.Replayed
+	}
+	return false
+}
+
+func (x *GetRandomInt64Response) GetReceipt() *Receipt {
+	if x != nil {
+		return x.Receipt
+	}
+	return nil
+}
+
 type GetDeterministicRandomRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Sequence      int64                  `protobuf:"varint,1,opt,name=sequence,proto3" json:"sequence,omitempty"`
 	Probabilities []float64              `protobuf:"fixed64,2,rep,packed,name=probabilities,proto3" json:"probabilities,omitempty"`
+	Namespace     string                 `protobuf:"bytes,3,opt,name=namespace,proto3" json:"namespace,omitempty"`
+	KeyId         string                 `protobuf:"bytes,4,opt,name=key_id,json=keyId,proto3" json:"key_id,omitempty"`
+	Epoch         int64                  `protobuf:"varint,5,opt,name=epoch,proto3" json:"epoch,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -249,9 +300,35 @@ func (x *GetDeterministicRandomRequest) GetProbabilities() []float64 {
 	return nil
 }
 
+func (x *GetDeterministicRandomRequest) GetNamespace() string {
+	if x != nil {
+		return x.Namespace
+	}
+	return ""
+}
+
+func (x *GetDeterministicRandomRequest) GetKeyId() string {
+	if x != nil {
+		return x.KeyId
+	}
+	return ""
+}
+
+func (x *GetDeterministicRandomRequest) GetEpoch() int64 {
+	if x != nil {
+		return x.Epoch
+	}
+	return 0
+}
+
 type GetDeterministicRandomResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Number        int64                  `protobuf:"varint,1,opt,name=number,proto3" json:"number,omitempty"`
+	Replayed      bool                   `protobuf:"varint,2,opt,name=replayed,proto3" json:"replayed,omitempty"`
+	VrfProof      []byte                 `protobuf:"bytes,3,opt,name=vrf_proof,json=vrfProof,proto3" json:"vrf_proof,omitempty"`
+	KeyId         string                 `protobuf:"bytes,4,opt,name=key_id,json=keyId,proto3" json:"key_id,omitempty"`
+	Epoch         int64                  `protobuf:"varint,5,opt,name=epoch,proto3" json:"epoch,omitempty"`
+	Receipt       *Receipt               `protobuf:"bytes,15,opt,name=receipt,proto3" json:"receipt,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -293,28 +370,1361 @@ func (x *GetDeterministicRandomResponse) GetNumber() int64 {
 	return 0
 }
 
-var File_pkg_pb_service_proto protoreflect.FileDescriptor
+func (x *GetDeterministicRandomResponse) GetReplayed() bool {
+	if x != nil {
+		return x.Replayed
+	}
+	return false
+}
 
-const file_pkg_pb_service_proto_rawDesc = "" +
-	"\n" +
-	"\x14pkg/pb/service.proto\x12\x06random\"\x19\n" +
-	"\x17GetRandomFloat64Request\"2\n" +
-	"\x18GetRandomFloat64Response\x12\x16\n" +
-	"\x06number\x18\x01 \x01(\x01R\x06number\";\n" +
-	"\x15GetRandomInt64Request\x12\x10\n" +
-	"\x03min\x18\x01 \x01(\x05R\x03min\x12\x10\n" +
-	"\x03max\x18\x02 \x01(\x05R\x03max\"0\n" +
-	"\x16GetRandomInt64Response\x12\x16\n" +
-	"\x06number\x18\x01 \x01(\x03R\x06number\"a\n" +
-	"\x1dGetDeterministicRandomRequest\x12\x1a\n" +
-	"\bsequence\x18\x01 \x01(\x03R\bsequence\x12$\n" +
-	"\rprobabilities\x18\x02 \x03(\x01R\rprobabilities\"8\n" +
-	"\x1eGetDeterministicRandomResponse\x12\x16\n" +
-	"\x06number\x18\x01 \x01(\x03R\x06number2\x99\x02\n" +
-	"\x06Random\x12U\n" +
-	"\x10GetRandomFloat64\x12\x1f.random.GetRandomFloat64Request\x1a .random.GetRandomFloat64Response\x12O\n" +
-	"\x0eGetRandomInt64\x12\x1d.random.GetRandomInt64Request\x1a\x1e.random.GetRandomInt64Response\x12g\n" +
-	"\x16GetDeterministicRandom\x12%.random.GetDeterministicRandomRequest\x1a&.random.GetDeterministicRandomResponseB\x80\x01\n" +
+func (x *GetDeterministicRandomResponse) GetVrfProof() []byte {
+	if x != nil {
+		return x.VrfProof
+	}
+	return nil
+}
+
+func (x *GetDeterministicRandomResponse) GetKeyId() string {
+	if x != nil {
+		return x.KeyId
+	}
+	return ""
+}
+
+func (x *GetDeterministicRandomResponse) GetEpoch() int64 {
+	if x != nil {
+		return x.Epoch
+	}
+	return 0
+}
+
+func (x *GetDeterministicRandomResponse) GetReceipt() *Receipt {
+	if x != nil {
+		return x.Receipt
+	}
+	return nil
+}
+
+type DrawDeterministicRandomRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Namespace     string                 `protobuf:"bytes,1,opt,name=namespace,proto3" json:"namespace,omitempty"`
+	Probabilities []float64              `protobuf:"fixed64,2,rep,packed,name=probabilities,proto3" json:"probabilities,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DrawDeterministicRandomRequest) Reset() {
+	*x = DrawDeterministicRandomRequest{}
+	mi := &file_pkg_pb_service_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DrawDeterministicRandomRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DrawDeterministicRandomRequest) ProtoMessage() {}
+
+func (x *DrawDeterministicRandomRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_pb_service_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DrawDeterministicRandomRequest.ProtoReflect.Descriptor instead.
+func (*DrawDeterministicRandomRequest) Descriptor() ([]byte, []int) {
+	return file_pkg_pb_service_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *DrawDeterministicRandomRequest) GetNamespace() string {
+	if x != nil {
+		return x.Namespace
+	}
+	return ""
+}
+
+func (x *DrawDeterministicRandomRequest) GetProbabilities() []float64 {
+	if x != nil {
+		return x.Probabilities
+	}
+	return nil
+}
+
+type DrawDeterministicRandomResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Sequence      int64                  `protobuf:"varint,1,opt,name=sequence,proto3" json:"sequence,omitempty"`
+	Number        int64                  `protobuf:"varint,2,opt,name=number,proto3" json:"number,omitempty"`
+	VrfProof      []byte                 `protobuf:"bytes,3,opt,name=vrf_proof,json=vrfProof,proto3" json:"vrf_proof,omitempty"`
+	KeyId         string                 `protobuf:"bytes,4,opt,name=key_id,json=keyId,proto3" json:"key_id,omitempty"`
+	Epoch         int64                  `protobuf:"varint,5,opt,name=epoch,proto3" json:"epoch,omitempty"`
+	Receipt       *Receipt               `protobuf:"bytes,15,opt,name=receipt,proto3" json:"receipt,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DrawDeterministicRandomResponse) Reset() {
+	*x = DrawDeterministicRandomResponse{}
+	mi := &file_pkg_pb_service_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DrawDeterministicRandomResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DrawDeterministicRandomResponse) ProtoMessage() {}
+
+func (x *DrawDeterministicRandomResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_pb_service_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DrawDeterministicRandomResponse.ProtoReflect.Descriptor instead.
+func (*DrawDeterministicRandomResponse) Descriptor() ([]byte, []int) {
+	return file_pkg_pb_service_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *DrawDeterministicRandomResponse) GetSequence() int64 {
+	if x != nil {
+		return x.Sequence
+	}
+	return 0
+}
+
+func (x *DrawDeterministicRandomResponse) GetNumber() int64 {
+	if x != nil {
+		return x.Number
+	}
+	return 0
+}
+
+func (x *DrawDeterministicRandomResponse) GetVrfProof() []byte {
+	if x != nil {
+		return x.VrfProof
+	}
+	return nil
+}
+
+func (x *DrawDeterministicRandomResponse) GetKeyId() string {
+	if x != nil {
+		return x.KeyId
+	}
+	return ""
+}
+
+func (x *DrawDeterministicRandomResponse) GetEpoch() int64 {
+	if x != nil {
+		return x.Epoch
+	}
+	return 0
+}
+
+func (x *DrawDeterministicRandomResponse) GetReceipt() *Receipt {
+	if x != nil {
+		return x.Receipt
+	}
+	return nil
+}
+
+type Receipt struct {
+	state             protoimpl.MessageState `protogen:"open.v1"`
+	KeyId             string                 `protobuf:"bytes,1,opt,name=key_id,json=keyId,proto3" json:"key_id,omitempty"`
+	Rpc               string                 `protobuf:"bytes,2,opt,name=rpc,proto3" json:"rpc,omitempty"`
+	Request           []byte                 `protobuf:"bytes,3,opt,name=request,proto3" json:"request,omitempty"`
+	Response          []byte                 `protobuf:"bytes,4,opt,name=response,proto3" json:"response,omitempty"`
+	TimestampUnixNano int64                  `protobuf:"varint,5,opt,name=timestamp_unix_nano,json=timestampUnixNano,proto3" json:"timestamp_unix_nano,omitempty"`
+	Signature         []byte                 `protobuf:"bytes,6,opt,name=signature,proto3" json:"signature,omitempty"`
+	unknownFields     protoimpl.UnknownFields
+	sizeCache         protoimpl.SizeCache
+}
+
+func (x *Receipt) Reset() {
+	*x = Receipt{}
+	mi := &file_pkg_pb_service_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Receipt) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Receipt) ProtoMessage() {}
+
+func (x *Receipt) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_pb_service_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Receipt.ProtoReflect.Descriptor instead.
+func (*Receipt) Descriptor() ([]byte, []int) {
+	return file_pkg_pb_service_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *Receipt) GetKeyId() string {
+	if x != nil {
+		return x.KeyId
+	}
+	return ""
+}
+
+func (x *Receipt) GetRpc() string {
+	if x != nil {
+		return x.Rpc
+	}
+	return ""
+}
+
+func (x *Receipt) GetRequest() []byte {
+	if x != nil {
+		return x.Request
+	}
+	return nil
+}
+
+func (x *Receipt) GetResponse() []byte {
+	if x != nil {
+		return x.Response
+	}
+	return nil
+}
+
+func (x *Receipt) GetTimestampUnixNano() int64 {
+	if x != nil {
+		return x.TimestampUnixNano
+	}
+	return 0
+}
+
+func (x *Receipt) GetSignature() []byte {
+	if x != nil {
+		return x.Signature
+	}
+	return nil
+}
+
+type GetReceiptPublicKeyRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetReceiptPublicKeyRequest) Reset() {
+	*x = GetReceiptPublicKeyRequest{}
+	mi := &file_pkg_pb_service_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetReceiptPublicKeyRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetReceiptPublicKeyRequest) ProtoMessage() {}
+
+func (x *GetReceiptPublicKeyRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_pb_service_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetReceiptPublicKeyRequest.ProtoReflect.Descriptor instead.
+func (*GetReceiptPublicKeyRequest) Descriptor() ([]byte, []int) {
+	return file_pkg_pb_service_proto_rawDescGZIP(), []int{9}
+}
+
+type GetReceiptPublicKeyResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	KeyId         string                 `protobuf:"bytes,1,opt,name=key_id,json=keyId,proto3" json:"key_id,omitempty"`
+	PublicKey     []byte                 `protobuf:"bytes,2,opt,name=public_key,json=publicKey,proto3" json:"public_key,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetReceiptPublicKeyResponse) Reset() {
+	*x = GetReceiptPublicKeyResponse{}
+	mi := &file_pkg_pb_service_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetReceiptPublicKeyResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetReceiptPublicKeyResponse) ProtoMessage() {}
+
+func (x *GetReceiptPublicKeyResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_pb_service_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetReceiptPublicKeyResponse.ProtoReflect.Descriptor instead.
+func (*GetReceiptPublicKeyResponse) Descriptor() ([]byte, []int) {
+	return file_pkg_pb_service_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *GetReceiptPublicKeyResponse) GetKeyId() string {
+	if x != nil {
+		return x.KeyId
+	}
+	return ""
+}
+
+func (x *GetReceiptPublicKeyResponse) GetPublicKey() []byte {
+	if x != nil {
+		return x.PublicKey
+	}
+	return nil
+}
+
+type GetVRFPublicKeyRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	KeyId         string                 `protobuf:"bytes,1,opt,name=key_id,json=keyId,proto3" json:"key_id,omitempty"`
+	Namespace     string                 `protobuf:"bytes,2,opt,name=namespace,proto3" json:"namespace,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetVRFPublicKeyRequest) Reset() {
+	*x = GetVRFPublicKeyRequest{}
+	mi := &file_pkg_pb_service_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetVRFPublicKeyRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetVRFPublicKeyRequest) ProtoMessage() {}
+
+func (x *GetVRFPublicKeyRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_pb_service_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetVRFPublicKeyRequest.ProtoReflect.Descriptor instead.
+func (*GetVRFPublicKeyRequest) Descriptor() ([]byte, []int) {
+	return file_pkg_pb_service_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *GetVRFPublicKeyRequest) GetKeyId() string {
+	if x != nil {
+		return x.KeyId
+	}
+	return ""
+}
+
+func (x *GetVRFPublicKeyRequest) GetNamespace() string {
+	if x != nil {
+		return x.Namespace
+	}
+	return ""
+}
+
+type GetVRFPublicKeyResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	PublicKey     []byte                 `protobuf:"bytes,1,opt,name=public_key,json=publicKey,proto3" json:"public_key,omitempty"`
+	KeyId         string                 `protobuf:"bytes,2,opt,name=key_id,json=keyId,proto3" json:"key_id,omitempty"`
+	Epoch         int64                  `protobuf:"varint,3,opt,name=epoch,proto3" json:"epoch,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetVRFPublicKeyResponse) Reset() {
+	*x = GetVRFPublicKeyResponse{}
+	mi := &file_pkg_pb_service_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetVRFPublicKeyResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetVRFPublicKeyResponse) ProtoMessage() {}
+
+func (x *GetVRFPublicKeyResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_pb_service_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetVRFPublicKeyResponse.ProtoReflect.Descriptor instead.
+func (*GetVRFPublicKeyResponse) Descriptor() ([]byte, []int) {
+	return file_pkg_pb_service_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *GetVRFPublicKeyResponse) GetPublicKey() []byte {
+	if x != nil {
+		return x.PublicKey
+	}
+	return nil
+}
+
+func (x *GetVRFPublicKeyResponse) GetKeyId() string {
+	if x != nil {
+		return x.KeyId
+	}
+	return ""
+}
+
+func (x *GetVRFPublicKeyResponse) GetEpoch() int64 {
+	if x != nil {
+		return x.Epoch
+	}
+	return 0
+}
+
+type VerifyDeterministicRandomRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	PublicKey     []byte                 `protobuf:"bytes,1,opt,name=public_key,json=publicKey,proto3" json:"public_key,omitempty"`
+	Sequence      int64                  `protobuf:"varint,2,opt,name=sequence,proto3" json:"sequence,omitempty"`
+	Probabilities []float64              `protobuf:"fixed64,3,rep,packed,name=probabilities,proto3" json:"probabilities,omitempty"`
+	Number        int64                  `protobuf:"varint,4,opt,name=number,proto3" json:"number,omitempty"`
+	VrfProof      []byte                 `protobuf:"bytes,5,opt,name=vrf_proof,json=vrfProof,proto3" json:"vrf_proof,omitempty"`
+	KeyId         string                 `protobuf:"bytes,6,opt,name=key_id,json=keyId,proto3" json:"key_id,omitempty"`
+	Namespace     string                 `protobuf:"bytes,7,opt,name=namespace,proto3" json:"namespace,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *VerifyDeterministicRandomRequest) Reset() {
+	*x = VerifyDeterministicRandomRequest{}
+	mi := &file_pkg_pb_service_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *VerifyDeterministicRandomRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*VerifyDeterministicRandomRequest) ProtoMessage() {}
+
+func (x *VerifyDeterministicRandomRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_pb_service_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use VerifyDeterministicRandomRequest.ProtoReflect.Descriptor instead.
+func (*VerifyDeterministicRandomRequest) Descriptor() ([]byte, []int) {
+	return file_pkg_pb_service_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *VerifyDeterministicRandomRequest) GetPublicKey() []byte {
+	if x != nil {
+		return x.PublicKey
+	}
+	return nil
+}
+
+func (x *VerifyDeterministicRandomRequest) GetSequence() int64 {
+	if x != nil {
+		return x.Sequence
+	}
+	return 0
+}
+
+func (x *VerifyDeterministicRandomRequest) GetProbabilities() []float64 {
+	if x != nil {
+		return x.Probabilities
+	}
+	return nil
+}
+
+func (x *VerifyDeterministicRandomRequest) GetNumber() int64 {
+	if x != nil {
+		return x.Number
+	}
+	return 0
+}
+
+func (x *VerifyDeterministicRandomRequest) GetVrfProof() []byte {
+	if x != nil {
+		return x.VrfProof
+	}
+	return nil
+}
+
+func (x *VerifyDeterministicRandomRequest) GetKeyId() string {
+	if x != nil {
+		return x.KeyId
+	}
+	return ""
+}
+
+func (x *VerifyDeterministicRandomRequest) GetNamespace() string {
+	if x != nil {
+		return x.Namespace
+	}
+	return ""
+}
+
+type VerifyDeterministicRandomResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Valid         bool                   `protobuf:"varint,1,opt,name=valid,proto3" json:"valid,omitempty"`
+	Error         string                 `protobuf:"bytes,2,opt,name=error,proto3" json:"error,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *VerifyDeterministicRandomResponse) Reset() {
+	*x = VerifyDeterministicRandomResponse{}
+	mi := &file_pkg_pb_service_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *VerifyDeterministicRandomResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*VerifyDeterministicRandomResponse) ProtoMessage() {}
+
+func (x *VerifyDeterministicRandomResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_pb_service_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use VerifyDeterministicRandomResponse.ProtoReflect.Descriptor instead.
+func (*VerifyDeterministicRandomResponse) Descriptor() ([]byte, []int) {
+	return file_pkg_pb_service_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *VerifyDeterministicRandomResponse) GetValid() bool {
+	if x != nil {
+		return x.Valid
+	}
+	return false
+}
+
+func (x *VerifyDeterministicRandomResponse) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+type SeedCommitment struct {
+	state               protoimpl.MessageState `protogen:"open.v1"`
+	KeyId               string                 `protobuf:"bytes,1,opt,name=key_id,json=keyId,proto3" json:"key_id,omitempty"`
+	Epoch               int64                  `protobuf:"varint,2,opt,name=epoch,proto3" json:"epoch,omitempty"`
+	ActiveFromUnixNano  int64                  `protobuf:"varint,3,opt,name=active_from_unix_nano,json=activeFromUnixNano,proto3" json:"active_from_unix_nano,omitempty"`
+	ActiveUntilUnixNano int64                  `protobuf:"varint,4,opt,name=active_until_unix_nano,json=activeUntilUnixNano,proto3" json:"active_until_unix_nano,omitempty"`
+	Commitment          string                 `protobuf:"bytes,5,opt,name=commitment,proto3" json:"commitment,omitempty"`
+	PublishedAtUnixNano int64                  `protobuf:"varint,6,opt,name=published_at_unix_nano,json=publishedAtUnixNano,proto3" json:"published_at_unix_nano,omitempty"`
+	Late                bool                   `protobuf:"varint,7,opt,name=late,proto3" json:"late,omitempty"`
+	unknownFields       protoimpl.UnknownFields
+	sizeCache           protoimpl.SizeCache
+}
+
+func (x *SeedCommitment) Reset() {
+	*x = SeedCommitment{}
+	mi := &file_pkg_pb_service_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SeedCommitment) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SeedCommitment) ProtoMessage() {}
+
+func (x *SeedCommitment) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_pb_service_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SeedCommitment.ProtoReflect.Descriptor instead.
+func (*SeedCommitment) Descriptor() ([]byte, []int) {
+	return file_pkg_pb_service_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *SeedCommitment) GetKeyId() string {
+	if x != nil {
+		return x.KeyId
+	}
+	return ""
+}
+
+func (x *SeedCommitment) GetEpoch() int64 {
+	if x != nil {
+		return x.Epoch
+	}
+	return 0
+}
+
+func (x *SeedCommitment) GetActiveFromUnixNano() int64 {
+	if x != nil {
+		return x.ActiveFromUnixNano
+	}
+	return 0
+}
+
+func (x *SeedCommitment) GetActiveUntilUnixNano() int64 {
+	if x != nil {
+		return x.ActiveUntilUnixNano
+	}
+	return 0
+}
+
+func (x *SeedCommitment) GetCommitment() string {
+	if x != nil {
+		return x.Commitment
+	}
+	return ""
+}
+
+func (x *SeedCommitment) GetPublishedAtUnixNano() int64 {
+	if x != nil {
+		return x.PublishedAtUnixNano
+	}
+	return 0
+}
+
+func (x *SeedCommitment) GetLate() bool {
+	if x != nil {
+		return x.Late
+	}
+	return false
+}
+
+type ListSeedCommitmentsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListSeedCommitmentsRequest) Reset() {
+	*x = ListSeedCommitmentsRequest{}
+	mi := &file_pkg_pb_service_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListSeedCommitmentsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListSeedCommitmentsRequest) ProtoMessage() {}
+
+func (x *ListSeedCommitmentsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_pb_service_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListSeedCommitmentsRequest.ProtoReflect.Descriptor instead.
+func (*ListSeedCommitmentsRequest) Descriptor() ([]byte, []int) {
+	return file_pkg_pb_service_proto_rawDescGZIP(), []int{16}
+}
+
+type ListSeedCommitmentsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Commitments   []*SeedCommitment      `protobuf:"bytes,1,rep,name=commitments,proto3" json:"commitments,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListSeedCommitmentsResponse) Reset() {
+	*x = ListSeedCommitmentsResponse{}
+	mi := &file_pkg_pb_service_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListSeedCommitmentsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListSeedCommitmentsResponse) ProtoMessage() {}
+
+func (x *ListSeedCommitmentsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_pb_service_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListSeedCommitmentsResponse.ProtoReflect.Descriptor instead.
+func (*ListSeedCommitmentsResponse) Descriptor() ([]byte, []int) {
+	return file_pkg_pb_service_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *ListSeedCommitmentsResponse) GetCommitments() []*SeedCommitment {
+	if x != nil {
+		return x.Commitments
+	}
+	return nil
+}
+
+type RevealSeedRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	KeyId         string                 `protobuf:"bytes,1,opt,name=key_id,json=keyId,proto3" json:"key_id,omitempty"`
+	Epoch         int64                  `protobuf:"varint,2,opt,name=epoch,proto3" json:"epoch,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RevealSeedRequest) Reset() {
+	*x = RevealSeedRequest{}
+	mi := &file_pkg_pb_service_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RevealSeedRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RevealSeedRequest) ProtoMessage() {}
+
+func (x *RevealSeedRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_pb_service_proto_msgTypes[18]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RevealSeedRequest.ProtoReflect.Descriptor instead.
+func (*RevealSeedRequest) Descriptor() ([]byte, []int) {
+	return file_pkg_pb_service_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *RevealSeedRequest) GetKeyId() string {
+	if x != nil {
+		return x.KeyId
+	}
+	return ""
+}
+
+func (x *RevealSeedRequest) GetEpoch() int64 {
+	if x != nil {
+		return x.Epoch
+	}
+	return 0
+}
+
+type RevealSeedResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Commitment    *SeedCommitment        `protobuf:"bytes,1,opt,name=commitment,proto3" json:"commitment,omitempty"`
+	SeedHex       string                 `protobuf:"bytes,2,opt,name=seed_hex,json=seedHex,proto3" json:"seed_hex,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RevealSeedResponse) Reset() {
+	*x = RevealSeedResponse{}
+	mi := &file_pkg_pb_service_proto_msgTypes[19]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RevealSeedResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RevealSeedResponse) ProtoMessage() {}
+
+func (x *RevealSeedResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_pb_service_proto_msgTypes[19]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RevealSeedResponse.ProtoReflect.Descriptor instead.
+func (*RevealSeedResponse) Descriptor() ([]byte, []int) {
+	return file_pkg_pb_service_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *RevealSeedResponse) GetCommitment() *SeedCommitment {
+	if x != nil {
+		return x.Commitment
+	}
+	return nil
+}
+
+func (x *RevealSeedResponse) GetSeedHex() string {
+	if x != nil {
+		return x.SeedHex
+	}
+	return ""
+}
+
+type CrashRound struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Round         int64                  `protobuf:"varint,1,opt,name=round,proto3" json:"round,omitempty"`
+	Seed          []byte                 `protobuf:"bytes,2,opt,name=seed,proto3" json:"seed,omitempty"`
+	Multiplier    int64                  `protobuf:"varint,3,opt,name=multiplier,proto3" json:"multiplier,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CrashRound) Reset() {
+	*x = CrashRound{}
+	mi := &file_pkg_pb_service_proto_msgTypes[20]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CrashRound) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CrashRound) ProtoMessage() {}
+
+func (x *CrashRound) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_pb_service_proto_msgTypes[20]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CrashRound.ProtoReflect.Descriptor instead.
+func (*CrashRound) Descriptor() ([]byte, []int) {
+	return file_pkg_pb_service_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *CrashRound) GetRound() int64 {
+	if x != nil {
+		return x.Round
+	}
+	return 0
+}
+
+func (x *CrashRound) GetSeed() []byte {
+	if x != nil {
+		return x.Seed
+	}
+	return nil
+}
+
+func (x *CrashRound) GetMultiplier() int64 {
+	if x != nil {
+		return x.Multiplier
+	}
+	return 0
+}
+
+type GetCrashChainRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetCrashChainRequest) Reset() {
+	*x = GetCrashChainRequest{}
+	mi := &file_pkg_pb_service_proto_msgTypes[21]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetCrashChainRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetCrashChainRequest) ProtoMessage() {}
+
+func (x *GetCrashChainRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_pb_service_proto_msgTypes[21]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetCrashChainRequest.ProtoReflect.Descriptor instead.
+func (*GetCrashChainRequest) Descriptor() ([]byte, []int) {
+	return file_pkg_pb_service_proto_rawDescGZIP(), []int{21}
+}
+
+type GetCrashChainResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	TerminalHash  []byte                 `protobuf:"bytes,1,opt,name=terminal_hash,json=terminalHash,proto3" json:"terminal_hash,omitempty"`
+	Length        int64                  `protobuf:"varint,2,opt,name=length,proto3" json:"length,omitempty"`
+	RoundsPlayed  int64                  `protobuf:"varint,3,opt,name=rounds_played,json=roundsPlayed,proto3" json:"rounds_played,omitempty"`
+	HouseEdge     float64                `protobuf:"fixed64,4,opt,name=house_edge,json=houseEdge,proto3" json:"house_edge,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetCrashChainResponse) Reset() {
+	*x = GetCrashChainResponse{}
+	mi := &file_pkg_pb_service_proto_msgTypes[22]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetCrashChainResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetCrashChainResponse) ProtoMessage() {}
+
+func (x *GetCrashChainResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_pb_service_proto_msgTypes[22]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetCrashChainResponse.ProtoReflect.Descriptor instead.
+func (*GetCrashChainResponse) Descriptor() ([]byte, []int) {
+	return file_pkg_pb_service_proto_rawDescGZIP(), []int{22}
+}
+
+func (x *GetCrashChainResponse) GetTerminalHash() []byte {
+	if x != nil {
+		return x.TerminalHash
+	}
+	return nil
+}
+
+func (x *GetCrashChainResponse) GetLength() int64 {
+	if x != nil {
+		return x.Length
+	}
+	return 0
+}
+
+func (x *GetCrashChainResponse) GetRoundsPlayed() int64 {
+	if x != nil {
+		return x.RoundsPlayed
+	}
+	return 0
+}
+
+func (x *GetCrashChainResponse) GetHouseEdge() float64 {
+	if x != nil {
+		return x.HouseEdge
+	}
+	return 0
+}
+
+type NextCrashRoundRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *NextCrashRoundRequest) Reset() {
+	*x = NextCrashRoundRequest{}
+	mi := &file_pkg_pb_service_proto_msgTypes[23]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *NextCrashRoundRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*NextCrashRoundRequest) ProtoMessage() {}
+
+func (x *NextCrashRoundRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_pb_service_proto_msgTypes[23]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use NextCrashRoundRequest.ProtoReflect.Descriptor instead.
+func (*NextCrashRoundRequest) Descriptor() ([]byte, []int) {
+	return file_pkg_pb_service_proto_rawDescGZIP(), []int{23}
+}
+
+type NextCrashRoundResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Round         *CrashRound            `protobuf:"bytes,1,opt,name=round,proto3" json:"round,omitempty"`
+	TerminalHash  []byte                 `protobuf:"bytes,2,opt,name=terminal_hash,json=terminalHash,proto3" json:"terminal_hash,omitempty"`
+	Receipt       *Receipt               `protobuf:"bytes,15,opt,name=receipt,proto3" json:"receipt,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *NextCrashRoundResponse) Reset() {
+	*x = NextCrashRoundResponse{}
+	mi := &file_pkg_pb_service_proto_msgTypes[24]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *NextCrashRoundResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*NextCrashRoundResponse) ProtoMessage() {}
+
+func (x *NextCrashRoundResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_pb_service_proto_msgTypes[24]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use NextCrashRoundResponse.ProtoReflect.Descriptor instead.
+func (*NextCrashRoundResponse) Descriptor() ([]byte, []int) {
+	return file_pkg_pb_service_proto_rawDescGZIP(), []int{24}
+}
+
+func (x *NextCrashRoundResponse) GetRound() *CrashRound {
+	if x != nil {
+		return x.Round
+	}
+	return nil
+}
+
+func (x *NextCrashRoundResponse) GetTerminalHash() []byte {
+	if x != nil {
+		return x.TerminalHash
+	}
+	return nil
+}
+
+func (x *NextCrashRoundResponse) GetReceipt() *Receipt {
+	if x != nil {
+		return x.Receipt
+	}
+	return nil
+}
+
+type GetCrashRoundRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Round         int64                  `protobuf:"varint,1,opt,name=round,proto3" json:"round,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetCrashRoundRequest) Reset() {
+	*x = GetCrashRoundRequest{}
+	mi := &file_pkg_pb_service_proto_msgTypes[25]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetCrashRoundRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetCrashRoundRequest) ProtoMessage() {}
+
+func (x *GetCrashRoundRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_pb_service_proto_msgTypes[25]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetCrashRoundRequest.ProtoReflect.Descriptor instead.
+func (*GetCrashRoundRequest) Descriptor() ([]byte, []int) {
+	return file_pkg_pb_service_proto_rawDescGZIP(), []int{25}
+}
+
+func (x *GetCrashRoundRequest) GetRound() int64 {
+	if x != nil {
+		return x.Round
+	}
+	return 0
+}
+
+type GetCrashRoundResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Round         *CrashRound            `protobuf:"bytes,1,opt,name=round,proto3" json:"round,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetCrashRoundResponse) Reset() {
+	*x = GetCrashRoundResponse{}
+	mi := &file_pkg_pb_service_proto_msgTypes[26]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetCrashRoundResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetCrashRoundResponse) ProtoMessage() {}
+
+func (x *GetCrashRoundResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_pb_service_proto_msgTypes[26]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetCrashRoundResponse.ProtoReflect.Descriptor instead.
+func (*GetCrashRoundResponse) Descriptor() ([]byte, []int) {
+	return file_pkg_pb_service_proto_rawDescGZIP(), []int{26}
+}
+
+func (x *GetCrashRoundResponse) GetRound() *CrashRound {
+	if x != nil {
+		return x.Round
+	}
+	return nil
+}
+
+var File_pkg_pb_service_proto protoreflect.FileDescriptor
+
+const file_pkg_pb_service_proto_rawDesc = "" +
+	"\n" +
+	"\x14pkg/pb/service.proto\x12\x06random\"B\n" +
+	"\x17GetRandomFloat64Request\x12'\n" +
+	"\x0fidempotency_key\x18\x01 \x01(\tR\x0eidempotencyKey\"y\n" +
+	"\x18GetRandomFloat64Response\x12\x16\n" +
+	"\x06number\x18\x01 \x01(\x01R\x06number\x12\x1a\n" +
+	"\breplayed\x18\x02 \x01(\bR\breplayed\x12)\n" +
+	"\areceipt\x18\x0f \x01(\v2\x0f.random.ReceiptR\areceipt\"d\n" +
+	"\x15GetRandomInt64Request\x12\x10\n" +
+	"\x03min\x18\x01 \x01(\x05R\x03min\x12\x10\n" +
+	"\x03max\x18\x02 \x01(\x05R\x03max\x12'\n" +
+	"\x0fidempotency_key\x18\x03 \x01(\tR\x0eidempotencyKey\"w\n" +
+	"\x16GetRandomInt64Response\x12\x16\n" +
+	"\x06number\x18\x01 \x01(\x03R\x06number\x12\x1a\n" +
+	"\breplayed\x18\x02 \x01(\bR\breplayed\x12)\n" +
+	"\areceipt\x18\x0f \x01(\v2\x0f.random.ReceiptR\areceipt\"\xac\x01\n" +
+	"\x1dGetDeterministicRandomRequest\x12\x1a\n" +
+	"\bsequence\x18\x01 \x01(\x03R\bsequence\x12$\n" +
+	"\rprobabilities\x18\x02 \x03(\x01R\rprobabilities\x12\x1c\n" +
+	"\tnamespace\x18\x03 \x01(\tR\tnamespace\x12\x15\n" +
+	"\x06key_id\x18\x04 \x01(\tR\x05keyId\x12\x14\n" +
+	"\x05epoch\x18\x05 \x01(\x03R\x05epoch\"\xc9\x01\n" +
+	"\x1eGetDeterministicRandomResponse\x12\x16\n" +
+	"\x06number\x18\x01 \x01(\x03R\x06number\x12\x1a\n" +
+	"\breplayed\x18\x02 \x01(\bR\breplayed\x12\x1b\n" +
+	"\tvrf_proof\x18\x03 \x01(\fR\bvrfProof\x12\x15\n" +
+	"\x06key_id\x18\x04 \x01(\tR\x05keyId\x12\x14\n" +
+	"\x05epoch\x18\x05 \x01(\x03R\x05epoch\x12)\n" +
+	"\areceipt\x18\x0f \x01(\v2\x0f.random.ReceiptR\areceipt\"d\n" +
+	"\x1eDrawDeterministicRandomRequest\x12\x1c\n" +
+	"\tnamespace\x18\x01 \x01(\tR\tnamespace\x12$\n" +
+	"\rprobabilities\x18\x02 \x03(\x01R\rprobabilities\"\xca\x01\n" +
+	"\x1fDrawDeterministicRandomResponse\x12\x1a\n" +
+	"\bsequence\x18\x01 \x01(\x03R\bsequence\x12\x16\n" +
+	"\x06number\x18\x02 \x01(\x03R\x06number\x12\x1b\n" +
+	"\tvrf_proof\x18\x03 \x01(\fR\bvrfProof\x12\x15\n" +
+	"\x06key_id\x18\x04 \x01(\tR\x05keyId\x12\x14\n" +
+	"\x05epoch\x18\x05 \x01(\x03R\x05epoch\x12)\n" +
+	"\areceipt\x18\x0f \x01(\v2\x0f.random.ReceiptR\areceipt\"\xb6\x01\n" +
+	"\aReceipt\x12\x15\n" +
+	"\x06key_id\x18\x01 \x01(\tR\x05keyId\x12\x10\n" +
+	"\x03rpc\x18\x02 \x01(\tR\x03rpc\x12\x18\n" +
+	"\arequest\x18\x03 \x01(\fR\arequest\x12\x1a\n" +
+	"\bresponse\x18\x04 \x01(\fR\bresponse\x12.\n" +
+	"\x13timestamp_unix_nano\x18\x05 \x01(\x03R\x11timestampUnixNano\x12\x1c\n" +
+	"\tsignature\x18\x06 \x01(\fR\tsignature\"\x1c\n" +
+	"\x1aGetReceiptPublicKeyRequest\"S\n" +
+	"\x1bGetReceiptPublicKeyResponse\x12\x15\n" +
+	"\x06key_id\x18\x01 \x01(\tR\x05keyId\x12\x1d\n" +
+	"\n" +
+	"public_key\x18\x02 \x01(\fR\tpublicKey\"M\n" +
+	"\x16GetVRFPublicKeyRequest\x12\x15\n" +
+	"\x06key_id\x18\x01 \x01(\tR\x05keyId\x12\x1c\n" +
+	"\tnamespace\x18\x02 \x01(\tR\tnamespace\"e\n" +
+	"\x17GetVRFPublicKeyResponse\x12\x1d\n" +
+	"\n" +
+	"public_key\x18\x01 \x01(\fR\tpublicKey\x12\x15\n" +
+	"\x06key_id\x18\x02 \x01(\tR\x05keyId\x12\x14\n" +
+	"\x05epoch\x18\x03 \x01(\x03R\x05epoch\"\xed\x01\n" +
+	" VerifyDeterministicRandomRequest\x12\x1d\n" +
+	"\n" +
+	"public_key\x18\x01 \x01(\fR\tpublicKey\x12\x1a\n" +
+	"\bsequence\x18\x02 \x01(\x03R\bsequence\x12$\n" +
+	"\rprobabilities\x18\x03 \x03(\x01R\rprobabilities\x12\x16\n" +
+	"\x06number\x18\x04 \x01(\x03R\x06number\x12\x1b\n" +
+	"\tvrf_proof\x18\x05 \x01(\fR\bvrfProof\x12\x15\n" +
+	"\x06key_id\x18\x06 \x01(\tR\x05keyId\x12\x1c\n" +
+	"\tnamespace\x18\a \x01(\tR\tnamespace\"O\n" +
+	"!VerifyDeterministicRandomResponse\x12\x14\n" +
+	"\x05valid\x18\x01 \x01(\bR\x05valid\x12\x14\n" +
+	"\x05error\x18\x02 \x01(\tR\x05error\"\x8e\x02\n" +
+	"\x0eSeedCommitment\x12\x15\n" +
+	"\x06key_id\x18\x01 \x01(\tR\x05keyId\x12\x14\n" +
+	"\x05epoch\x18\x02 \x01(\x03R\x05epoch\x121\n" +
+	"\x15active_from_unix_nano\x18\x03 \x01(\x03R\x12activeFromUnixNano\x123\n" +
+	"\x16active_until_unix_nano\x18\x04 \x01(\x03R\x13activeUntilUnixNano\x12\x1e\n" +
+	"\n" +
+	"commitment\x18\x05 \x01(\tR\n" +
+	"commitment\x123\n" +
+	"\x16published_at_unix_nano\x18\x06 \x01(\x03R\x13publishedAtUnixNano\x12\x12\n" +
+	"\x04late\x18\a \x01(\bR\x04late\"\x1c\n" +
+	"\x1aListSeedCommitmentsRequest\"W\n" +
+	"\x1bListSeedCommitmentsResponse\x128\n" +
+	"\vcommitments\x18\x01 \x03(\v2\x16.random.SeedCommitmentR\vcommitments\"@\n" +
+	"\x11RevealSeedRequest\x12\x15\n" +
+	"\x06key_id\x18\x01 \x01(\tR\x05keyId\x12\x14\n" +
+	"\x05epoch\x18\x02 \x01(\x03R\x05epoch\"g\n" +
+	"\x12RevealSeedResponse\x126\n" +
+	"\n" +
+	"commitment\x18\x01 \x01(\v2\x16.random.SeedCommitmentR\n" +
+	"commitment\x12\x19\n" +
+	"\bseed_hex\x18\x02 \x01(\tR\aseedHex\"V\n" +
+	"\n" +
+	"CrashRound\x12\x14\n" +
+	"\x05round\x18\x01 \x01(\x03R\x05round\x12\x12\n" +
+	"\x04seed\x18\x02 \x01(\fR\x04seed\x12\x1e\n" +
+	"\n" +
+	"multiplier\x18\x03 \x01(\x03R\n" +
+	"multiplier\"\x16\n" +
+	"\x14GetCrashChainRequest\"\x98\x01\n" +
+	"\x15GetCrashChainResponse\x12#\n" +
+	"\rterminal_hash\x18\x01 \x01(\fR\fterminalHash\x12\x16\n" +
+	"\x06length\x18\x02 \x01(\x03R\x06length\x12#\n" +
+	"\rrounds_played\x18\x03 \x01(\x03R\froundsPlayed\x12\x1d\n" +
+	"\n" +
+	"house_edge\x18\x04 \x01(\x01R\thouseEdge\"\x17\n" +
+	"\x15NextCrashRoundRequest\"\x92\x01\n" +
+	"\x16NextCrashRoundResponse\x12(\n" +
+	"\x05round\x18\x01 \x01(\v2\x12.random.CrashRoundR\x05round\x12#\n" +
+	"\rterminal_hash\x18\x02 \x01(\fR\fterminalHash\x12)\n" +
+	"\areceipt\x18\x0f \x01(\v2\x0f.random.ReceiptR\areceipt\",\n" +
+	"\x14GetCrashRoundRequest\x12\x14\n" +
+	"\x05round\x18\x01 \x01(\x03R\x05round\"A\n" +
+	"\x15GetCrashRoundResponse\x12(\n" +
+	"\x05round\x18\x01 \x01(\v2\x12.random.CrashRoundR\x05round2\xbd\b\n" +
+	"\x06Random\x12U\n" +
+	"\x10GetRandomFloat64\x12\x1f.random.GetRandomFloat64Request\x1a .random.GetRandomFloat64Response\x12O\n" +
+	"\x0eGetRandomInt64\x12\x1d.random.GetRandomInt64Request\x1a\x1e.random.GetRandomInt64Response\x12g\n" +
+	"\x16GetDeterministicRandom\x12%.random.GetDeterministicRandomRequest\x1a&.random.GetDeterministicRandomResponse\x12j\n" +
+	"\x17DrawDeterministicRandom\x12&.random.DrawDeterministicRandomRequest\x1a'.random.DrawDeterministicRandomResponse\x12^\n" +
+	"\x13GetReceiptPublicKey\x12\".random.GetReceiptPublicKeyRequest\x1a#.random.GetReceiptPublicKeyResponse\x12R\n" +
+	"\x0fGetVRFPublicKey\x12\x1e.random.GetVRFPublicKeyRequest\x1a\x1f.random.GetVRFPublicKeyResponse\x12p\n" +
+	"\x19VerifyDeterministicRandom\x12(.random.VerifyDeterministicRandomRequest\x1a).random.VerifyDeterministicRandomResponse\x12^\n" +
+	"\x13ListSeedCommitments\x12\".random.ListSeedCommitmentsRequest\x1a#.random.ListSeedCommitmentsResponse\x12C\n" +
+	"\n" +
+	"RevealSeed\x12\x19.random.RevealSeedRequest\x1a\x1a.random.RevealSeedResponse\x12L\n" +
+	"\rGetCrashChain\x12\x1c.random.GetCrashChainRequest\x1a\x1d.random.GetCrashChainResponse\x12O\n" +
+	"\x0eNextCrashRound\x12\x1d.random.NextCrashRoundRequest\x1a\x1e.random.NextCrashRoundResponse\x12L\n" +
+	"\rGetCrashRound\x12\x1c.random.GetCrashRoundRequest\x1a\x1d.random.GetCrashRoundResponseB\x80\x01\n" +
 	"\n" +
 	"com.randomB\fServiceProtoP\x01Z,github.com/fasttrack-solutions/random/pkg/pb\xa2\x02\x03RXX\xaa\x02\x06Random\xca\x02\x06Random\xe2\x02\x12Random\\GPBMetadata\xea\x02\x06Randomb\x06proto3"
 
@@ -330,27 +1740,75 @@ func file_pkg_pb_service_proto_rawDescGZIP() []byte {
 	return file_pkg_pb_service_proto_rawDescData
 }
 
-var file_pkg_pb_service_proto_msgTypes = make([]protoimpl.MessageInfo, 6)
+var file_pkg_pb_service_proto_msgTypes = make([]protoimpl.MessageInfo, 27)
 var file_pkg_pb_service_proto_goTypes = []any{
-	(*GetRandomFloat64Request)(nil),        // 0: random.GetRandomFloat64Request
-	(*GetRandomFloat64Response)(nil),       // 1: random.GetRandomFloat64Response
-	(*GetRandomInt64Request)(nil),          // 2: random.GetRandomInt64Request
-	(*GetRandomInt64Response)(nil),         // 3: random.GetRandomInt64Response
-	(*GetDeterministicRandomRequest)(nil),  // 4: random.GetDeterministicRandomRequest
-	(*GetDeterministicRandomResponse)(nil), // 5: random.GetDeterministicRandomResponse
+	(*GetRandomFloat64Request)(nil),           // 0: random.GetRandomFloat64Request
+	(*GetRandomFloat64Response)(nil),          // 1: random.GetRandomFloat64Response
+	(*GetRandomInt64Request)(nil),             // 2: random.GetRandomInt64Request
+	(*GetRandomInt64Response)(nil),            // 3: random.GetRandomInt64Response
+	(*GetDeterministicRandomRequest)(nil),     // 4: random.GetDeterministicRandomRequest
+	(*GetDeterministicRandomResponse)(nil),    // 5: random.GetDeterministicRandomResponse
+	(*DrawDeterministicRandomRequest)(nil),    // 6: random.DrawDeterministicRandomRequest
+	(*DrawDeterministicRandomResponse)(nil),   // 7: random.DrawDeterministicRandomResponse
+	(*Receipt)(nil),                           // 8: random.Receipt
+	(*GetReceiptPublicKeyRequest)(nil),        // 9: random.GetReceiptPublicKeyRequest
+	(*GetReceiptPublicKeyResponse)(nil),       // 10: random.GetReceiptPublicKeyResponse
+	(*GetVRFPublicKeyRequest)(nil),            // 11: random.GetVRFPublicKeyRequest
+	(*GetVRFPublicKeyResponse)(nil),           // 12: random.GetVRFPublicKeyResponse
+	(*VerifyDeterministicRandomRequest)(nil),  // 13: random.VerifyDeterministicRandomRequest
+	(*VerifyDeterministicRandomResponse)(nil), // 14: random.VerifyDeterministicRandomResponse
+	(*SeedCommitment)(nil),                    // 15: random.SeedCommitment
+	(*ListSeedCommitmentsRequest)(nil),        // 16: random.ListSeedCommitmentsRequest
+	(*ListSeedCommitmentsResponse)(nil),       // 17: random.ListSeedCommitmentsResponse
+	(*RevealSeedRequest)(nil),                 // 18: random.RevealSeedRequest
+	(*RevealSeedResponse)(nil),                // 19: random.RevealSeedResponse
+	(*CrashRound)(nil),                        // 20: random.CrashRound
+	(*GetCrashChainRequest)(nil),              // 21: random.GetCrashChainRequest
+	(*GetCrashChainResponse)(nil),             // 22: random.GetCrashChainResponse
+	(*NextCrashRoundRequest)(nil),             // 23: random.NextCrashRoundRequest
+	(*NextCrashRoundResponse)(nil),            // 24: random.NextCrashRoundResponse
+	(*GetCrashRoundRequest)(nil),              // 25: random.GetCrashRoundRequest
+	(*GetCrashRoundResponse)(nil),             // 26: random.GetCrashRoundResponse
 }
 var file_pkg_pb_service_proto_depIdxs = []int32{
-	0, // 0: random.Random.GetRandomFloat64:input_type -> random.GetRandomFloat64Request
-	2, // 1: random.Random.GetRandomInt64:input_type -> random.GetRandomInt64Request
-	4, // 2: random.Random.GetDeterministicRandom:input_type -> random.GetDeterministicRandomRequest
-	1, // 3: random.Random.GetRandomFloat64:output_type -> random.GetRandomFloat64Response
-	3, // 4: random.Random.GetRandomInt64:output_type -> random.GetRandomInt64Response
-	5, // 5: random.Random.GetDeterministicRandom:output_type -> random.GetDeterministicRandomResponse
-	3, // [3:6] is the sub-list for method output_type
-	0, // [0:3] is the sub-list for method input_type
-	0, // [0:0] is the sub-list for extension type_name
-	0, // [0:0] is the sub-list for extension extendee
-	0, // [0:0] is the sub-list for field type_name
+	8,  // 0: random.GetRandomFloat64Response.receipt:type_name -> random.Receipt
+	8,  // 1: random.GetRandomInt64Response.receipt:type_name -> random.Receipt
+	8,  // 2: random.GetDeterministicRandomResponse.receipt:type_name -> random.Receipt
+	8,  // 3: random.DrawDeterministicRandomResponse.receipt:type_name -> random.Receipt
+	15, // 4: random.ListSeedCommitmentsResponse.commitments:type_name -> random.SeedCommitment
+	15, // 5: random.RevealSeedResponse.commitment:type_name -> random.SeedCommitment
+	20, // 6: random.NextCrashRoundResponse.round:type_name -> random.CrashRound
+	8,  // 7: random.NextCrashRoundResponse.receipt:type_name -> random.Receipt
+	20, // 8: random.GetCrashRoundResponse.round:type_name -> random.CrashRound
+	0,  // 9: random.Random.GetRandomFloat64:input_type -> random.GetRandomFloat64Request
+	2,  // 10: random.Random.GetRandomInt64:input_type -> random.GetRandomInt64Request
+	4,  // 11: random.Random.GetDeterministicRandom:input_type -> random.GetDeterministicRandomRequest
+	6,  // 12: random.Random.DrawDeterministicRandom:input_type -> random.DrawDeterministicRandomRequest
+	9,  // 13: random.Random.GetReceiptPublicKey:input_type -> random.GetReceiptPublicKeyRequest
+	11, // 14: random.Random.GetVRFPublicKey:input_type -> random.GetVRFPublicKeyRequest
+	13, // 15: random.Random.VerifyDeterministicRandom:input_type -> random.VerifyDeterministicRandomRequest
+	16, // 16: random.Random.ListSeedCommitments:input_type -> random.ListSeedCommitmentsRequest
+	18, // 17: random.Random.RevealSeed:input_type -> random.RevealSeedRequest
+	21, // 18: random.Random.GetCrashChain:input_type -> random.GetCrashChainRequest
+	23, // 19: random.Random.NextCrashRound:input_type -> random.NextCrashRoundRequest
+	25, // 20: random.Random.GetCrashRound:input_type -> random.GetCrashRoundRequest
+	1,  // 21: random.Random.GetRandomFloat64:output_type -> random.GetRandomFloat64Response
+	3,  // 22: random.Random.GetRandomInt64:output_type -> random.GetRandomInt64Response
+	5,  // 23: random.Random.GetDeterministicRandom:output_type -> random.GetDeterministicRandomResponse
+	7,  // 24: random.Random.DrawDeterministicRandom:output_type -> random.DrawDeterministicRandomResponse
+	10, // 25: random.Random.GetReceiptPublicKey:output_type -> random.GetReceiptPublicKeyResponse
+	12, // 26: random.Random.GetVRFPublicKey:output_type -> random.GetVRFPublicKeyResponse
+	14, // 27: random.Random.VerifyDeterministicRandom:output_type -> random.VerifyDeterministicRandomResponse
+	17, // 28: random.Random.ListSeedCommitments:output_type -> random.ListSeedCommitmentsResponse
+	19, // 29: random.Random.RevealSeed:output_type -> random.RevealSeedResponse
+	22, // 30: random.Random.GetCrashChain:output_type -> random.GetCrashChainResponse
+	24, // 31: random.Random.NextCrashRound:output_type -> random.NextCrashRoundResponse
+	26, // 32: random.Random.GetCrashRound:output_type -> random.GetCrashRoundResponse
+	21, // [21:33] is the sub-list for method output_type
+	9,  // [9:21] is the sub-list for method input_type
+	9,  // [9:9] is the sub-list for extension type_name
+	9,  // [9:9] is the sub-list for extension extendee
+	0,  // [0:9] is the sub-list for field type_name
 }
 
 func init() { file_pkg_pb_service_proto_init() }
@@ -364,7 +1822,7 @@ func file_pkg_pb_service_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_pkg_pb_service_proto_rawDesc), len(file_pkg_pb_service_proto_rawDesc)),
 			NumEnums:      0,
-			NumMessages:   6,
+			NumMessages:   27,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
@@ -19,9 +19,18 @@ import (
 const _ = grpc.SupportPackageIsVersion9
 
 const (
-	Random_GetRandomFloat64_FullMethodName       = "/random.Random/GetRandomFloat64"
-	Random_GetRandomInt64_FullMethodName         = "/random.Random/GetRandomInt64"
-	Random_GetDeterministicRandom_FullMethodName = "/random.Random/GetDeterministicRandom"
+	Random_GetRandomFloat64_FullMethodName          = "/random.Random/GetRandomFloat64"
+	Random_GetRandomInt64_FullMethodName            = "/random.Random/GetRandomInt64"
+	Random_GetDeterministicRandom_FullMethodName    = "/random.Random/GetDeterministicRandom"
+	Random_DrawDeterministicRandom_FullMethodName   = "/random.Random/DrawDeterministicRandom"
+	Random_GetReceiptPublicKey_FullMethodName       = "/random.Random/GetReceiptPublicKey"
+	Random_GetVRFPublicKey_FullMethodName           = "/random.Random/GetVRFPublicKey"
+	Random_VerifyDeterministicRandom_FullMethodName = "/random.Random/VerifyDeterministicRandom"
+	Random_ListSeedCommitments_FullMethodName       = "/random.Random/ListSeedCommitments"
+	Random_RevealSeed_FullMethodName                = "/random.Random/RevealSeed"
+	Random_GetCrashChain_FullMethodName             = "/random.Random/GetCrashChain"
+	Random_NextCrashRound_FullMethodName            = "/random.Random/NextCrashRound"
+	Random_GetCrashRound_FullMethodName             = "/random.Random/GetCrashRound"
 )
 
 // RandomClient is the client API for Random service.
@@ -31,6 +40,15 @@ type RandomClient interface {
 	GetRandomFloat64(ctx context.Context, in *GetRandomFloat64Request, opts ...grpc.CallOption) (*GetRandomFloat64Response, error)
 	GetRandomInt64(ctx context.Context, in *GetRandomInt64Request, opts ...grpc.CallOption) (*GetRandomInt64Response, error)
 	GetDeterministicRandom(ctx context.Context, in *GetDeterministicRandomRequest, opts ...grpc.CallOption) (*GetDeterministicRandomResponse, error)
+	DrawDeterministicRandom(ctx context.Context, in *DrawDeterministicRandomRequest, opts ...grpc.CallOption) (*DrawDeterministicRandomResponse, error)
+	GetReceiptPublicKey(ctx context.Context, in *GetReceiptPublicKeyRequest, opts ...grpc.CallOption) (*GetReceiptPublicKeyResponse, error)
+	GetVRFPublicKey(ctx context.Context, in *GetVRFPublicKeyRequest, opts ...grpc.CallOption) (*GetVRFPublicKeyResponse, error)
+	VerifyDeterministicRandom(ctx context.Context, in *VerifyDeterministicRandomRequest, opts ...grpc.CallOption) (*VerifyDeterministicRandomResponse, error)
+	ListSeedCommitments(ctx context.Context, in *ListSeedCommitmentsRequest, opts ...grpc.CallOption) (*ListSeedCommitmentsResponse, error)
+	RevealSeed(ctx context.Context, in *RevealSeedRequest, opts ...grpc.CallOption) (*RevealSeedResponse, error)
+	GetCrashChain(ctx context.Context, in *GetCrashChainRequest, opts ...grpc.CallOption) (*GetCrashChainResponse, error)
+	NextCrashRound(ctx context.Context, in *NextCrashRoundRequest, opts ...grpc.CallOption) (*NextCrashRoundResponse, error)
+	GetCrashRound(ctx context.Context, in *GetCrashRoundRequest, opts ...grpc.CallOption) (*GetCrashRoundResponse, error)
 }
 
 type randomClient struct {
@@ -71,6 +89,96 @@ func (c *randomClient) GetDeterministicRandom(ctx context.Context, in *GetDeterm
 	return out, nil
 }
 
+func (c *randomClient) DrawDeterministicRandom(ctx context.Context, in *DrawDeterministicRandomRequest, opts ...grpc.CallOption) (*DrawDeterministicRandomResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(DrawDeterministicRandomResponse)
+	err := c.cc.Invoke(ctx, Random_DrawDeterministicRandom_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *randomClient) GetReceiptPublicKey(ctx context.Context, in *GetReceiptPublicKeyRequest, opts ...grpc.CallOption) (*GetReceiptPublicKeyResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetReceiptPublicKeyResponse)
+	err := c.cc.Invoke(ctx, Random_GetReceiptPublicKey_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *randomClient) GetVRFPublicKey(ctx context.Context, in *GetVRFPublicKeyRequest, opts ...grpc.CallOption) (*GetVRFPublicKeyResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetVRFPublicKeyResponse)
+	err := c.cc.Invoke(ctx, Random_GetVRFPublicKey_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *randomClient) VerifyDeterministicRandom(ctx context.Context, in *VerifyDeterministicRandomRequest, opts ...grpc.CallOption) (*VerifyDeterministicRandomResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(VerifyDeterministicRandomResponse)
+	err := c.cc.Invoke(ctx, Random_VerifyDeterministicRandom_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *randomClient) ListSeedCommitments(ctx context.Context, in *ListSeedCommitmentsRequest, opts ...grpc.CallOption) (*ListSeedCommitmentsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListSeedCommitmentsResponse)
+	err := c.cc.Invoke(ctx, Random_ListSeedCommitments_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *randomClient) RevealSeed(ctx context.Context, in *RevealSeedRequest, opts ...grpc.CallOption) (*RevealSeedResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(RevealSeedResponse)
+	err := c.cc.Invoke(ctx, Random_RevealSeed_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *randomClient) GetCrashChain(ctx context.Context, in *GetCrashChainRequest, opts ...grpc.CallOption) (*GetCrashChainResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetCrashChainResponse)
+	err := c.cc.Invoke(ctx, Random_GetCrashChain_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *randomClient) NextCrashRound(ctx context.Context, in *NextCrashRoundRequest, opts ...grpc.CallOption) (*NextCrashRoundResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(NextCrashRoundResponse)
+	err := c.cc.Invoke(ctx, Random_NextCrashRound_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *randomClient) GetCrashRound(ctx context.Context, in *GetCrashRoundRequest, opts ...grpc.CallOption) (*GetCrashRoundResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetCrashRoundResponse)
+	err := c.cc.Invoke(ctx, Random_GetCrashRound_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // RandomServer is the server API for Random service.
 // All implementations should embed UnimplementedRandomServer
 // for forward compatibility.
@@ -78,6 +186,15 @@ type RandomServer interface {
 	GetRandomFloat64(context.Context, *GetRandomFloat64Request) (*GetRandomFloat64Response, error)
 	GetRandomInt64(context.Context, *GetRandomInt64Request) (*GetRandomInt64Response, error)
 	GetDeterministicRandom(context.Context, *GetDeterministicRandomRequest) (*GetDeterministicRandomResponse, error)
+	DrawDeterministicRandom(context.Context, *DrawDeterministicRandomRequest) (*DrawDeterministicRandomResponse, error)
+	GetReceiptPublicKey(context.Context, *GetReceiptPublicKeyRequest) (*GetReceiptPublicKeyResponse, error)
+	GetVRFPublicKey(context.Context, *GetVRFPublicKeyRequest) (*GetVRFPublicKeyResponse, error)
+	VerifyDeterministicRandom(context.Context, *VerifyDeterministicRandomRequest) (*VerifyDeterministicRandomResponse, error)
+	ListSeedCommitments(context.Context, *ListSeedCommitmentsRequest) (*ListSeedCommitmentsResponse, error)
+	RevealSeed(context.Context, *RevealSeedRequest) (*RevealSeedResponse, error)
+	GetCrashChain(context.Context, *GetCrashChainRequest) (*GetCrashChainResponse, error)
+	NextCrashRound(context.Context, *NextCrashRoundRequest) (*NextCrashRoundResponse, error)
+	GetCrashRound(context.Context, *GetCrashRoundRequest) (*GetCrashRoundResponse, error)
 }
 
 // UnimplementedRandomServer should be embedded to have
@@ -96,6 +213,33 @@ func (UnimplementedRandomServer) GetRandomInt64(context.Context, *GetRandomInt64
 func (UnimplementedRandomServer) GetDeterministicRandom(context.Context, *GetDeterministicRandomRequest) (*GetDeterministicRandomResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method GetDeterministicRandom not implemented")
 }
+func (UnimplementedRandomServer) DrawDeterministicRandom(context.Context, *DrawDeterministicRandomRequest) (*DrawDeterministicRandomResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DrawDeterministicRandom not implemented")
+}
+func (UnimplementedRandomServer) GetReceiptPublicKey(context.Context, *GetReceiptPublicKeyRequest) (*GetReceiptPublicKeyResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetReceiptPublicKey not implemented")
+}
+func (UnimplementedRandomServer) GetVRFPublicKey(context.Context, *GetVRFPublicKeyRequest) (*GetVRFPublicKeyResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetVRFPublicKey not implemented")
+}
+func (UnimplementedRandomServer) VerifyDeterministicRandom(context.Context, *VerifyDeterministicRandomRequest) (*VerifyDeterministicRandomResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method VerifyDeterministicRandom not implemented")
+}
+func (UnimplementedRandomServer) ListSeedCommitments(context.Context, *ListSeedCommitmentsRequest) (*ListSeedCommitmentsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListSeedCommitments not implemented")
+}
+func (UnimplementedRandomServer) RevealSeed(context.Context, *RevealSeedRequest) (*RevealSeedResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RevealSeed not implemented")
+}
+func (UnimplementedRandomServer) GetCrashChain(context.Context, *GetCrashChainRequest) (*GetCrashChainResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetCrashChain not implemented")
+}
+func (UnimplementedRandomServer) NextCrashRound(context.Context, *NextCrashRoundRequest) (*NextCrashRoundResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method NextCrashRound not implemented")
+}
+func (UnimplementedRandomServer) GetCrashRound(context.Context, *GetCrashRoundRequest) (*GetCrashRoundResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetCrashRound not implemented")
+}
 func (UnimplementedRandomServer) testEmbeddedByValue() {}
 
 // UnsafeRandomServer may be embedded to opt out of forward compatibility for this service.
@@ -170,6 +314,168 @@ func _Random_GetDeterministicRandom_Handler(srv interface{}, ctx context.Context
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Random_DrawDeterministicRandom_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DrawDeterministicRandomRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RandomServer).DrawDeterministicRandom(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Random_DrawDeterministicRandom_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RandomServer).DrawDeterministicRandom(ctx, req.(*DrawDeterministicRandomRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Random_GetReceiptPublicKey_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetReceiptPublicKeyRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RandomServer).GetReceiptPublicKey(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Random_GetReceiptPublicKey_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RandomServer).GetReceiptPublicKey(ctx, req.(*GetReceiptPublicKeyRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Random_GetVRFPublicKey_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetVRFPublicKeyRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RandomServer).GetVRFPublicKey(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Random_GetVRFPublicKey_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RandomServer).GetVRFPublicKey(ctx, req.(*GetVRFPublicKeyRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Random_VerifyDeterministicRandom_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(VerifyDeterministicRandomRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RandomServer).VerifyDeterministicRandom(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Random_VerifyDeterministicRandom_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RandomServer).VerifyDeterministicRandom(ctx, req.(*VerifyDeterministicRandomRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Random_ListSeedCommitments_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListSeedCommitmentsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RandomServer).ListSeedCommitments(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Random_ListSeedCommitments_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RandomServer).ListSeedCommitments(ctx, req.(*ListSeedCommitmentsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Random_RevealSeed_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RevealSeedRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RandomServer).RevealSeed(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Random_RevealSeed_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RandomServer).RevealSeed(ctx, req.(*RevealSeedRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Random_GetCrashChain_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetCrashChainRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RandomServer).GetCrashChain(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Random_GetCrashChain_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RandomServer).GetCrashChain(ctx, req.(*GetCrashChainRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Random_NextCrashRound_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(NextCrashRoundRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RandomServer).NextCrashRound(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Random_NextCrashRound_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RandomServer).NextCrashRound(ctx, req.(*NextCrashRoundRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Random_GetCrashRound_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetCrashRoundRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RandomServer).GetCrashRound(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Random_GetCrashRound_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RandomServer).GetCrashRound(ctx, req.(*GetCrashRoundRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // Random_ServiceDesc is the grpc.ServiceDesc for Random service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -189,6 +495,42 @@ var Random_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "GetDeterministicRandom",
 			Handler:    _Random_GetDeterministicRandom_Handler,
 		},
+		{
+			MethodName: "DrawDeterministicRandom",
+			Handler:    _Random_DrawDeterministicRandom_Handler,
+		},
+		{
+			MethodName: "GetReceiptPublicKey",
+			Handler:    _Random_GetReceiptPublicKey_Handler,
+		},
+		{
+			MethodName: "GetVRFPublicKey",
+			Handler:    _Random_GetVRFPublicKey_Handler,
+		},
+		{
+			MethodName: "VerifyDeterministicRandom",
+			Handler:    _Random_VerifyDeterministicRandom_Handler,
+		},
+		{
+			MethodName: "ListSeedCommitments",
+			Handler:    _Random_ListSeedCommitments_Handler,
+		},
+		{
+			MethodName: "RevealSeed",
+			Handler:    _Random_RevealSeed_Handler,
+		},
+		{
+			MethodName: "GetCrashChain",
+			Handler:    _Random_GetCrashChain_Handler,
+		},
+		{
+			MethodName: "NextCrashRound",
+			Handler:    _Random_NextCrashRound_Handler,
+		},
+		{
+			MethodName: "GetCrashRound",
+			Handler:    _Random_GetCrashRound_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "pkg/pb/service.proto",
@@ -0,0 +1,253 @@
+// Package receipt signs and verifies receipts of draws, so downstream services
+// can store proof that an outcome was produced by the random service.
+package receipt
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/fasttrack-solutions/random/pkg/pb"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// domain separates receipt signatures from other uses of the key
+const domain = "fasttrack-random/receipt/v1"
+
+// receiptField is the name of the receipt field in the response messages
+const receiptField = "receipt"
+
+var (
+	// ErrInvalidSignature is returned when a receipt was not signed by the key
+	ErrInvalidSignature = errors.New("invalid receipt signature")
+	// ErrNoReceipt is returned for a response without a receipt
+	ErrNoReceipt = errors.New("response has no receipt")
+	// ErrResponseMismatch is returned when a response differs from the one in its receipt
+	ErrResponseMismatch = errors.New("response does not match its receipt")
+	// ErrInvalidPublicKey is returned for a public key that is not an Ed25519 key
+	ErrInvalidPublicKey = fmt.Errorf("public key must be %v bytes", ed25519.PublicKeySize)
+)
+
+// KeyID returns the id of a public key, the first 8 bytes of its SHA-256 in hex
+func KeyID(publicKey ed25519.PublicKey) string {
+	sum := sha256.Sum256(publicKey)
+	return hex.EncodeToString(sum[:8])
+}
+
+// SigningBytes returns the canonical encoding of the receipt that is signed:
+// the domain followed by the key id, rpc, request and response, each prefixed
+// with their big endian uint32 length, and the big endian timestamp.
+func SigningBytes(r *pb.Receipt) []byte {
+	var b bytes.Buffer
+	b.WriteString(domain)
+	for _, field := range [][]byte{[]byte(r.KeyId), []byte(r.Rpc), r.Request, r.Response} {
+		_ = binary.Write(&b, binary.BigEndian, uint32(len(field))) // #nosec G115 -- fields are far below 4 GiB
+		b.Write(field)
+	}
+	_ = binary.Write(&b, binary.BigEndian, r.TimestampUnixNano)
+	return b.Bytes()
+}
+
+// Signer signs receipts with an Ed25519 key
+type Signer struct {
+	key   ed25519.PrivateKey
+	keyID string
+	now   func() time.Time
+}
+
+// NewSigner creates a signer for the key
+func NewSigner(key ed25519.PrivateKey) *Signer {
+	return &Signer{
+		key:   key,
+		keyID: KeyID(key.Public().(ed25519.PublicKey)),
+		now:   time.Now,
+	}
+}
+
+// LoadSigner creates a signer from a PEM encoded PKCS #8 Ed25519 private key file
+func LoadSigner(path string) (*Signer, error) {
+	b, err := os.ReadFile(filepath.Clean(path))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read receipt key: %w", err)
+	}
+
+	block, _ := pem.Decode(b)
+	if block == nil || block.Type != "PRIVATE KEY" {
+		return nil, errors.New("receipt key must be a PEM encoded PKCS #8 private key")
+	}
+
+	key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("invalid receipt key: %w", err)
+	}
+
+	edKey, ok := key.(ed25519.PrivateKey)
+	if !ok {
+		return nil, errors.New("receipt key must be an Ed25519 key")
+	}
+	return NewSigner(edKey), nil
+}
+
+// GenerateKey creates a new Ed25519 key and returns it PEM encoded
+func GenerateKey() (ed25519.PublicKey, []byte, error) {
+	publicKey, privateKey, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	der, err := x509.MarshalPKCS8PrivateKey(privateKey)
+	if err != nil {
+		return nil, nil, err
+	}
+	return publicKey, pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der}), nil
+}
+
+// KeyID returns the id of the signing key
+func (s *Signer) KeyID() string {
+	return s.keyID
+}
+
+// PublicKey returns the public key to verify receipts with
+func (s *Signer) PublicKey() ed25519.PublicKey {
+	return s.key.Public().(ed25519.PublicKey)
+}
+
+// Sign creates the receipt of a draw. resp must not carry a receipt yet.
+func (s *Signer) Sign(rpc string, req proto.Message, resp proto.Message) (*pb.Receipt, error) {
+	request, err := proto.MarshalOptions{Deterministic: true}.Marshal(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode %s request: %w", rpc, err)
+	}
+
+	response, err := proto.MarshalOptions{Deterministic: true}.Marshal(resp)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode %s response: %w", rpc, err)
+	}
+
+	r := &pb.Receipt{
+		KeyId:             s.keyID,
+		Rpc:               rpc,
+		Request:           request,
+		Response:          response,
+		TimestampUnixNano: s.now().UnixNano(),
+	}
+	r.Signature = ed25519.Sign(s.key, SigningBytes(r))
+	return r, nil
+}
+
+// Attach signs resp and sets the receipt field of it to the returned receipt
+func (s *Signer) Attach(rpc string, req proto.Message, resp proto.Message) (*pb.Receipt, error) {
+	field := resp.ProtoReflect().Descriptor().Fields().ByName(receiptField)
+	if field == nil {
+		return nil, fmt.Errorf("%s response has no receipt field", rpc)
+	}
+
+	r, err := s.Sign(rpc, req, resp)
+	if err != nil {
+		return nil, err
+	}
+
+	resp.ProtoReflect().Set(field, protoreflect.ValueOfMessage(r.ProtoReflect()))
+	return r, nil
+}
+
+// Verify checks that the receipt was signed by publicKey
+func Verify(publicKey ed25519.PublicKey, r *pb.Receipt) error {
+	if len(publicKey) != ed25519.PublicKeySize {
+		return ErrInvalidPublicKey
+	} else if r == nil {
+		return ErrNoReceipt
+	} else if r.KeyId != KeyID(publicKey) {
+		return fmt.Errorf("receipt was signed with key %s, not %s", r.KeyId, KeyID(publicKey))
+	} else if !ed25519.Verify(publicKey, SigningBytes(r), r.Signature) {
+		return ErrInvalidSignature
+	}
+	return nil
+}
+
+// VerifyResponse checks the receipt carried by a response message and that
+// the response is the one it was issued for
+func VerifyResponse(publicKey ed25519.PublicKey, resp proto.Message) error {
+	field := resp.ProtoReflect().Descriptor().Fields().ByName(receiptField)
+	if field == nil || !resp.ProtoReflect().Has(field) {
+		return ErrNoReceipt
+	}
+
+	r, ok := resp.ProtoReflect().Get(field).Message().Interface().(*pb.Receipt)
+	if !ok {
+		return ErrNoReceipt
+	}
+
+	err := Verify(publicKey, r)
+	if err != nil {
+		return err
+	}
+
+	signed := resp.ProtoReflect().New().Interface()
+	err = proto.Unmarshal(r.Response, signed)
+	if err != nil {
+		return fmt.Errorf("invalid response in receipt: %w", err)
+	}
+
+	unsigned := proto.Clone(resp)
+	unsigned.ProtoReflect().Clear(field)
+	if !proto.Equal(signed, unsigned) {
+		return ErrResponseMismatch
+	}
+	return nil
+}
+
+// Open verifies the receipt and decodes its request and response into req and resp
+func Open(publicKey ed25519.PublicKey, r *pb.Receipt, req proto.Message, resp proto.Message) error {
+	err := Verify(publicKey, r)
+	if err != nil {
+		return err
+	}
+
+	err = proto.Unmarshal(r.Request, req)
+	if err != nil {
+		return fmt.Errorf("invalid request in receipt: %w", err)
+	}
+
+	err = proto.Unmarshal(r.Response, resp)
+	if err != nil {
+		return fmt.Errorf("invalid response in receipt: %w", err)
+	}
+	return nil
+}
+
+// Encode returns the receipt as base64 protobuf, as sent in the X-Receipt header of the HTTP server
+func Encode(r *pb.Receipt) (string, error) {
+	b, err := proto.Marshal(r)
+	if err != nil {
+		return "", err
+	}
+	return base64.StdEncoding.EncodeToString(b), nil
+}
+
+// Decode parses a receipt encoded with Encode
+func Decode(s string) (*pb.Receipt, error) {
+	b, err := base64.StdEncoding.DecodeString(s)
+	if err != nil {
+		return nil, fmt.Errorf("invalid receipt encoding: %w", err)
+	}
+
+	r := &pb.Receipt{}
+	err = proto.Unmarshal(b, r)
+	if err != nil {
+		return nil, fmt.Errorf("invalid receipt: %w", err)
+	}
+	return r, nil
+}
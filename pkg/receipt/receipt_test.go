@@ -0,0 +1,100 @@
+package receipt
+
+import (
+	"crypto/ed25519"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/fasttrack-solutions/random/pkg/pb"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/protobuf/proto"
+)
+
+func newTestSigner(t *testing.T) *Signer {
+	_, keyPEM, err := GenerateKey()
+	assert.Nil(t, err)
+
+	path := filepath.Join(t.TempDir(), "receipt.pem")
+	assert.Nil(t, os.WriteFile(path, keyPEM, 0600))
+
+	signer, err := LoadSigner(path)
+	assert.Nil(t, err)
+	signer.now = func() time.Time { return time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC) }
+	return signer
+}
+
+func Test_Attach_VerifyResponse(t *testing.T) {
+	signer := newTestSigner(t)
+	req := &pb.GetDeterministicRandomRequest{Sequence: 42, Probabilities: []float64{0.1, 0.9}, Namespace: "campaign"}
+	resp := &pb.GetDeterministicRandomResponse{Number: 1}
+
+	r, err := signer.Attach("GetDeterministicRandom", req, resp)
+	assert.Nil(t, err)
+	assert.Equal(t, r, resp.Receipt)
+	assert.Equal(t, signer.KeyID(), resp.Receipt.KeyId)
+	assert.Nil(t, VerifyResponse(signer.PublicKey(), resp))
+
+	// The receipt survives the wire
+	b, err := proto.Marshal(resp)
+	assert.Nil(t, err)
+	received := &pb.GetDeterministicRandomResponse{}
+	assert.Nil(t, proto.Unmarshal(b, received))
+	assert.Nil(t, VerifyResponse(signer.PublicKey(), received))
+
+	// The request and response can be read back from the receipt
+	openedReq, openedResp := &pb.GetDeterministicRandomRequest{}, &pb.GetDeterministicRandomResponse{}
+	assert.Nil(t, Open(signer.PublicKey(), received.Receipt, openedReq, openedResp))
+	assert.True(t, proto.Equal(req, openedReq))
+	assert.Equal(t, int64(1), openedResp.Number)
+
+	// A changed outcome does not match its receipt
+	received.Number = 0
+	assert.ErrorIs(t, VerifyResponse(signer.PublicKey(), received), ErrResponseMismatch)
+
+	// A changed receipt fails the signature
+	resp.Receipt.Request, _ = proto.Marshal(&pb.GetDeterministicRandomRequest{Sequence: 43, Probabilities: []float64{0.1, 0.9}})
+	assert.ErrorIs(t, VerifyResponse(signer.PublicKey(), resp), ErrInvalidSignature)
+
+	// Other keys are refused
+	otherKey, _, err := ed25519.GenerateKey(nil)
+	assert.Nil(t, err)
+	assert.NotNil(t, Verify(otherKey, received.Receipt))
+
+	assert.ErrorIs(t, VerifyResponse(signer.PublicKey(), &pb.GetDeterministicRandomResponse{}), ErrNoReceipt)
+
+	// Keys of another length are refused rather than panic
+	for _, key := range []ed25519.PublicKey{nil, signer.PublicKey()[:16], append(signer.PublicKey(), 0)} {
+		assert.ErrorIs(t, Verify(key, received.Receipt), ErrInvalidPublicKey)
+		assert.ErrorIs(t, VerifyResponse(key, resp), ErrInvalidPublicKey)
+		assert.ErrorIs(t, Open(key, received.Receipt, openedReq, openedResp), ErrInvalidPublicKey)
+	}
+}
+
+func Test_Encode_Decode(t *testing.T) {
+	signer := newTestSigner(t)
+	r, err := signer.Sign("GetRandomFloat64", &pb.GetRandomFloat64Request{}, &pb.GetRandomFloat64Response{Number: 0.25})
+	assert.Nil(t, err)
+
+	s, err := Encode(r)
+	assert.Nil(t, err)
+
+	decoded, err := Decode(s)
+	assert.Nil(t, err)
+	assert.Nil(t, Verify(signer.PublicKey(), decoded))
+
+	_, err = Decode("not base64!")
+	assert.NotNil(t, err)
+}
+
+func Test_LoadSigner_Invalid(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "receipt.pem")
+	assert.Nil(t, os.WriteFile(path, []byte("not a key"), 0600))
+
+	_, err := LoadSigner(path)
+	assert.NotNil(t, err)
+
+	_, err = LoadSigner(filepath.Join(t.TempDir(), "missing.pem"))
+	assert.NotNil(t, err)
+}
@@ -0,0 +1,206 @@
+// Package vrf implements the ECVRF-EDWARDS25519-SHA512-TAI verifiable random
+// function of RFC 9381. A proof of an input can only be created with the
+// secret key, and anyone holding the public key can verify it and derive the
+// same pseudorandom output from it.
+package vrf
+
+import (
+	"bytes"
+	"crypto/sha512"
+	"crypto/subtle"
+	"errors"
+
+	"filippo.io/edwards25519"
+)
+
+const (
+	// SecretKeySize is the size of a secret key, an Ed25519 seed
+	SecretKeySize = 32
+	// PublicKeySize is the size of an encoded public key
+	PublicKeySize = 32
+	// ProofSize is the size of a proof: an encoded point, a 16 byte challenge and a scalar
+	ProofSize = 80
+	// OutputSize is the size of the pseudorandom output of a proof
+	OutputSize = 64
+)
+
+const (
+	suite    = 0x03
+	cLen     = 16
+	cofactor = 8
+)
+
+var (
+	// ErrInvalidProof is returned when a proof does not verify for the public key and input
+	ErrInvalidProof = errors.New("invalid vrf proof")
+	// ErrInvalidPublicKey is returned for a public key that is not a valid point or of small order
+	ErrInvalidPublicKey = errors.New("invalid vrf public key")
+)
+
+// PrivateKey is a secret key and its expanded form
+type PrivateKey struct {
+	x      *edwards25519.Scalar
+	prefix []byte
+	public []byte
+}
+
+// NewPrivateKey expands a 32 byte secret key the same way Ed25519 does
+func NewPrivateKey(secretKey []byte) (*PrivateKey, error) {
+	if len(secretKey) != SecretKeySize {
+		return nil, errors.New("vrf secret key must be 32 bytes")
+	}
+
+	h := sha512.Sum512(secretKey)
+	x, err := edwards25519.NewScalar().SetBytesWithClamping(h[:32])
+	if err != nil {
+		return nil, err
+	}
+
+	return &PrivateKey{
+		x:      x,
+		prefix: append([]byte(nil), h[32:]...),
+		public: new(edwards25519.Point).ScalarBaseMult(x).Bytes(),
+	}, nil
+}
+
+// PublicKey returns the encoded public key
+func (k *PrivateKey) PublicKey() []byte {
+	return append([]byte(nil), k.public...)
+}
+
+// Prove creates the proof of alpha
+func (k *PrivateKey) Prove(alpha []byte) []byte {
+	h := encodeToCurve(k.public, alpha)
+	hString := h.Bytes()
+	gamma := new(edwards25519.Point).ScalarMult(k.x, h)
+
+	nonce := sha512.New()
+	nonce.Write(k.prefix)
+	nonce.Write(hString)
+	kScalar, _ := edwards25519.NewScalar().SetUniformBytes(nonce.Sum(nil))
+
+	kB := new(edwards25519.Point).ScalarBaseMult(kScalar)
+	kH := new(edwards25519.Point).ScalarMult(kScalar, h)
+	c := challenge(k.public, hString, gamma.Bytes(), kB.Bytes(), kH.Bytes())
+
+	s := edwards25519.NewScalar().MultiplyAdd(scalarFromChallenge(c), k.x, kScalar)
+
+	pi := make([]byte, 0, ProofSize)
+	pi = append(pi, gamma.Bytes()...)
+	pi = append(pi, c...)
+	return append(pi, s.Bytes()...)
+}
+
+// Verify checks the proof of alpha with the public key and returns its output
+func Verify(publicKey []byte, alpha []byte, pi []byte) ([]byte, error) {
+	y, err := decodePoint(publicKey)
+	if err != nil || isSmallOrder(y) {
+		return nil, ErrInvalidPublicKey
+	}
+
+	gamma, c, s, err := decodeProof(pi)
+	if err != nil {
+		return nil, err
+	}
+
+	h := encodeToCurve(publicKey, alpha)
+	cScalar := scalarFromChallenge(c)
+	negC := edwards25519.NewScalar().Negate(cScalar)
+
+	// U = s*B - c*Y, V = s*H - c*Gamma
+	u := new(edwards25519.Point).VarTimeDoubleScalarBaseMult(negC, y, s)
+	v := new(edwards25519.Point).Add(
+		new(edwards25519.Point).ScalarMult(s, h),
+		new(edwards25519.Point).ScalarMult(negC, gamma),
+	)
+
+	expected := challenge(publicKey, h.Bytes(), gamma.Bytes(), u.Bytes(), v.Bytes())
+	if subtle.ConstantTimeCompare(c, expected) != 1 {
+		return nil, ErrInvalidProof
+	}
+	return proofToHash(gamma), nil
+}
+
+// ProofToHash returns the output of a proof without verifying it.
+// Only use it on proofs that were verified or created with the secret key.
+func ProofToHash(pi []byte) ([]byte, error) {
+	gamma, _, _, err := decodeProof(pi)
+	if err != nil {
+		return nil, err
+	}
+	return proofToHash(gamma), nil
+}
+
+func proofToHash(gamma *edwards25519.Point) []byte {
+	h := sha512.New()
+	h.Write([]byte{suite, 0x03})
+	h.Write(new(edwards25519.Point).MultByCofactor(gamma).Bytes())
+	h.Write([]byte{0x00})
+	return h.Sum(nil)
+}
+
+// encodeToCurve hashes alpha to a point with the try-and-increment method, salted with the public key
+func encodeToCurve(publicKey []byte, alpha []byte) *edwards25519.Point {
+	for ctr := 0; ; ctr++ {
+		h := sha512.New()
+		h.Write([]byte{suite, 0x01})
+		h.Write(publicKey)
+		h.Write(alpha)
+		h.Write([]byte{byte(ctr), 0x00})
+
+		p, err := decodePoint(h.Sum(nil)[:32])
+		if err == nil {
+			return p.MultByCofactor(p)
+		}
+		// About half of the candidates are points, so the counter never overflows in practice
+	}
+}
+
+func challenge(points ...[]byte) []byte {
+	h := sha512.New()
+	h.Write([]byte{suite, 0x02})
+	for _, p := range points {
+		h.Write(p)
+	}
+	h.Write([]byte{0x00})
+	return h.Sum(nil)[:cLen]
+}
+
+func scalarFromChallenge(c []byte) *edwards25519.Scalar {
+	var b [32]byte
+	copy(b[:], c)
+	s, _ := edwards25519.NewScalar().SetCanonicalBytes(b[:])
+	return s
+}
+
+func decodeProof(pi []byte) (*edwards25519.Point, []byte, *edwards25519.Scalar, error) {
+	if len(pi) != ProofSize {
+		return nil, nil, nil, ErrInvalidProof
+	}
+
+	gamma, err := decodePoint(pi[:32])
+	if err != nil {
+		return nil, nil, nil, ErrInvalidProof
+	}
+
+	s, err := edwards25519.NewScalar().SetCanonicalBytes(pi[32+cLen:])
+	if err != nil {
+		return nil, nil, nil, ErrInvalidProof
+	}
+	return gamma, pi[32 : 32+cLen], s, nil
+}
+
+// decodePoint decodes a point as specified in RFC 8032, refusing non-canonical encodings
+func decodePoint(b []byte) (*edwards25519.Point, error) {
+	p, err := new(edwards25519.Point).SetBytes(b)
+	if err != nil {
+		return nil, err
+	} else if !bytes.Equal(p.Bytes(), b) {
+		return nil, errors.New("non-canonical point encoding")
+	}
+	return p, nil
+}
+
+func isSmallOrder(p *edwards25519.Point) bool {
+	return new(edwards25519.Point).MultByCofactor(p).Equal(edwards25519.NewIdentityPoint()) == 1
+}
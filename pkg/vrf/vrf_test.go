@@ -0,0 +1,92 @@
+package vrf
+
+import (
+	"encoding/hex"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func mustHex(t *testing.T, s string) []byte {
+	b, err := hex.DecodeString(s)
+	assert.Nil(t, err)
+	return b
+}
+
+// Test vectors of ECVRF-EDWARDS25519-SHA512-TAI in appendix B.3 of RFC 9381
+func Test_Prove_Verify_RFC9381(t *testing.T) {
+	vectors := []struct {
+		sk, pk, alpha, pi, beta string
+	}{
+		{
+			sk:    "9d61b19deffd5a60ba844af492ec2cc44449c5697b326919703bac031cae7f60",
+			pk:    "d75a980182b10ab7d54bfed3c964073a0ee172f3daa62325af021a68f707511a",
+			alpha: "",
+			pi:    "8657106690b5526245a92b003bb079ccd1a92130477671f6fc01ad16f26f723f26f8a57ccaed74ee1b190bed1f479d9727d2d0f9b005a6e456a35d4fb0daab1268a1b0db10836d9826a528ca76567805",
+			beta:  "90cf1df3b703cce59e2a35b925d411164068269d7b2d29f3301c03dd757876ff66b71dda49d2de59d03450451af026798e8f81cd2e333de5cdf4f3e140fdd8ae",
+		},
+		{
+			sk:    "4ccd089b28ff96da9db6c346ec114e0f5b8a319f35aba624da8cf6ed4fb8a6fb",
+			pk:    "3d4017c3e843895a92b70aa74d1b7ebc9c982ccf2ec4968cc0cd55f12af4660c",
+			alpha: "72",
+			beta:  "eb4440665d3891d668e7e0fcaf587f1b4bd7fbfe99d0eb2211ccec90496310eb5e33821bc613efb94db5e5b54c70a848a0bef4553a41befc57663b56373a5031",
+		},
+	}
+
+	for _, v := range vectors {
+		key, err := NewPrivateKey(mustHex(t, v.sk))
+		assert.Nil(t, err)
+		assert.Equal(t, v.pk, hex.EncodeToString(key.PublicKey()))
+
+		pi := key.Prove(mustHex(t, v.alpha))
+		if len(v.pi) > 0 {
+			assert.Equal(t, v.pi, hex.EncodeToString(pi))
+		}
+
+		beta, err := Verify(key.PublicKey(), mustHex(t, v.alpha), pi)
+		assert.Nil(t, err)
+		assert.Equal(t, v.beta, hex.EncodeToString(beta))
+
+		beta, err = ProofToHash(pi)
+		assert.Nil(t, err)
+		assert.Equal(t, v.beta, hex.EncodeToString(beta))
+	}
+}
+
+func Test_Verify_Invalid(t *testing.T) {
+	key, err := NewPrivateKey(mustHex(t, "9d61b19deffd5a60ba844af492ec2cc44449c5697b326919703bac031cae7f60"))
+	assert.Nil(t, err)
+	other, err := NewPrivateKey(mustHex(t, "4ccd089b28ff96da9db6c346ec114e0f5b8a319f35aba624da8cf6ed4fb8a6fb"))
+	assert.Nil(t, err)
+
+	alpha := []byte("sequence")
+	pi := key.Prove(alpha)
+
+	// Another input
+	_, err = Verify(key.PublicKey(), []byte("sequencf"), pi)
+	assert.ErrorIs(t, err, ErrInvalidProof)
+
+	// Another key
+	_, err = Verify(other.PublicKey(), alpha, pi)
+	assert.ErrorIs(t, err, ErrInvalidProof)
+
+	// Tampered proof
+	for _, i := range []int{0, 40, 79} {
+		tampered := append([]byte(nil), pi...)
+		tampered[i] ^= 1
+		_, err = Verify(key.PublicKey(), alpha, tampered)
+		assert.NotNil(t, err)
+	}
+
+	_, err = Verify(key.PublicKey(), alpha, pi[:79])
+	assert.ErrorIs(t, err, ErrInvalidProof)
+
+	// The identity is of small order
+	identity := make([]byte, 32)
+	identity[0] = 1
+	_, err = Verify(identity, alpha, pi)
+	assert.ErrorIs(t, err, ErrInvalidPublicKey)
+
+	_, err = NewPrivateKey([]byte("short"))
+	assert.NotNil(t, err)
+}
@@ -1,6 +1,7 @@
 package random
 
 import (
+	"crypto/hkdf"
 	"crypto/rand"
 	"crypto/sha256"
 	"encoding/binary"
@@ -9,8 +10,71 @@ import (
 	"fmt"
 	"math"
 	"math/big"
+	"strings"
+
+	"github.com/fasttrack-solutions/random/pkg/vrf"
+)
+
+// AlgorithmVersion identifies how outcomes are derived from their inputs.
+// It changes whenever the same seed, sequence and probabilities could produce another outcome.
+const AlgorithmVersion = "1"
+
+// AlgorithmVersionVRF identifies outcomes derived with VRFDeterministicRandom
+const AlgorithmVersionVRF = "vrf-1"
+
+// AlgorithmVersionHKDF and AlgorithmVersionVRFHKDF identify outcomes of DeterministicRandom
+// and VRFDeterministicRandom drawn with a seed derived per namespace with DeriveSeed
+const (
+	AlgorithmVersionHKDF    = "hkdf-1"
+	AlgorithmVersionVRFHKDF = "vrf-hkdf-1"
 )
 
+// PurposeDeterministic is the purpose of seeds derived for deterministic draws
+const PurposeDeterministic = "deterministic-draw"
+
+// DeriveSeed derives an independent seed from the master seed with HKDF-SHA256,
+// labelled with a purpose and a namespace such as a tenant or campaign.
+// Every (purpose, namespace) pair gets its own stream of outcomes, and a derived
+// seed reveals nothing about the master seed or the seeds of other namespaces.
+func DeriveSeed(seedHex string, purpose string, namespace string) (string, error) {
+	seed, err := hex.DecodeString(seedHex)
+	if err != nil {
+		return "", fmt.Errorf("invalid seed hex: %w", err)
+	} else if len(seed) != 32 {
+		return "", errors.New("seed must decode to exactly 32 bytes")
+	} else if len(purpose) == 0 {
+		return "", errors.New("purpose must not be empty")
+	} else if strings.IndexByte(purpose, 0) >= 0 {
+		return "", errors.New("purpose must not contain a zero byte")
+	}
+
+	// The purpose never holds a zero byte, so the label is unambiguous
+	info := make([]byte, 0, len(purpose)+1+len(namespace))
+	info = append(info, purpose...)
+	info = append(info, 0)
+	info = append(info, namespace...)
+
+	derived, err := hkdf.Key(sha256.New, seed, []byte("random/hkdf/v1"), string(info), 32)
+	if err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(derived), nil
+}
+
+// SeedFingerprint returns a short identifier of a seed that does not reveal it,
+// the first 8 bytes of a domain separated SHA-256 of the decoded seed in hex.
+func SeedFingerprint(seedHex string) (string, error) {
+	seed, err := hex.DecodeString(seedHex)
+	if err != nil {
+		return "", fmt.Errorf("invalid seed hex: %w", err)
+	}
+
+	h := sha256.New()
+	h.Write([]byte("random/seed-fingerprint/v1"))
+	h.Write(seed)
+	return hex.EncodeToString(h.Sum(nil)[:8]), nil
+}
+
 // UniformInt64 generates an int64 in the range (min, max) using a uniform distribution
 func UniformInt64(min int32, max int32) (int64, error) {
 	if min < 0 {
@@ -53,35 +117,141 @@ func UniformFloat64() (float64, error) {
 // DeterministicRandom creates deterministic random numbers using a seed.
 // The same seed, sequence number and probabilities generate the same outcome.
 func DeterministicRandom(seedHex string, sequence int64, probabilities []float64) (int64, error) {
+	seed, thresholds, err := validateDeterministic(seedHex, sequence, probabilities)
+	if err != nil {
+		return 0, err
+	}
+
+	// Compute random number
+	h := sha256.New()
+	h.Write(seed)
+	h.Write(sequenceBytes(sequence))
+	hash := h.Sum(nil)
+
+	return selectIndex(thresholds, binary.BigEndian.Uint64(hash[:8]))
+}
+
+// VRFPublicKey returns the ECVRF public key of the seed, which verifies the proofs of VRFDeterministicRandom
+func VRFPublicKey(seedHex string) ([]byte, error) {
+	key, err := vrfKey(seedHex)
+	if err != nil {
+		return nil, err
+	}
+	return key.PublicKey(), nil
+}
+
+// VRFDeterministicRandom creates deterministic random numbers using an ECVRF
+// (RFC 9381, ECVRF-EDWARDS25519-SHA512-TAI) keyed by the seed. Along with the
+// outcome it returns a proof that anyone with the public key can verify with
+// VerifyVRFDeterministicRandom, without learning the seed.
+// The same seed, sequence number and probabilities generate the same outcome and proof.
+func VRFDeterministicRandom(seedHex string, sequence int64, probabilities []float64) (int64, []byte, error) {
+	_, thresholds, err := validateDeterministic(seedHex, sequence, probabilities)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	key, err := vrfKey(seedHex)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	proof := key.Prove(sequenceBytes(sequence))
+	output, err := vrf.ProofToHash(proof)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	number, err := selectIndex(thresholds, binary.BigEndian.Uint64(output[:8]))
+	if err != nil {
+		return 0, nil, err
+	}
+	return number, proof, nil
+}
+
+// VerifyVRFDeterministicRandom checks that number is the outcome of VRFDeterministicRandom
+// for the sequence and probabilities, proven by the key of publicKey
+func VerifyVRFDeterministicRandom(publicKey []byte, sequence int64, probabilities []float64, number int64, proof []byte) error {
+	if sequence < 0 {
+		return errors.New("sequence must be larger than than or equal to 0")
+	}
+
+	thresholds, err := cumulativeThresholds(probabilities)
+	if err != nil {
+		return err
+	}
+
+	output, err := vrf.Verify(publicKey, sequenceBytes(sequence), proof)
+	if err != nil {
+		return err
+	}
+
+	expected, err := selectIndex(thresholds, binary.BigEndian.Uint64(output[:8]))
+	if err != nil {
+		return err
+	} else if expected != number {
+		return fmt.Errorf("proof is for outcome %v, not %v", expected, number)
+	}
+	return nil
+}
+
+// vrfKey derives the ECVRF secret key from the seed, so the seed itself is never used as a signing key
+func vrfKey(seedHex string) (*vrf.PrivateKey, error) {
+	seed, err := hex.DecodeString(seedHex)
+	if err != nil {
+		return nil, fmt.Errorf("invalid seed hex: %w", err)
+	}
+
+	h := sha256.New()
+	h.Write([]byte("random/vrf-key/v1"))
+	h.Write(seed)
+	return vrf.NewPrivateKey(h.Sum(nil))
+}
+
+// validateDeterministic checks the input of a deterministic draw and returns the decoded seed and thresholds
+func validateDeterministic(seedHex string, sequence int64, probabilities []float64) ([]byte, []uint64, error) {
 	// Validate input
 	if len(seedHex) != 64 {
-		return 0, errors.New("seedHex must be 64 bytes")
+		return nil, nil, errors.New("seedHex must be 64 bytes")
 	} else if sequence < 0 {
-		return 0, errors.New("sequence must be larger than than or equal to 0")
+		return nil, nil, errors.New("sequence must be larger than than or equal to 0")
 	} else if len(probabilities) == 0 {
-		return 0, errors.New("probabilities must not be empty")
+		return nil, nil, errors.New("probabilities must not be empty")
 	}
 
 	// Decode the seed
 	seed, err := hex.DecodeString(seedHex)
 	if err != nil {
-		return 0, fmt.Errorf("invalid seed hex: %w", err)
+		return nil, nil, fmt.Errorf("invalid seed hex: %w", err)
 	} else if len(seed) != 32 {
-		return 0, errors.New("seed must decode to exactly 32 bytes")
+		return nil, nil, errors.New("seed must decode to exactly 32 bytes")
+	}
+
+	thresholds, err := cumulativeThresholds(probabilities)
+	if err != nil {
+		return nil, nil, err
+	}
+	return seed, thresholds, nil
+}
+
+// cumulativeThresholds validates the probabilities and maps them onto the uint64 range
+func cumulativeThresholds(probabilities []float64) ([]uint64, error) {
+	if len(probabilities) == 0 {
+		return nil, errors.New("probabilities must not be empty")
 	}
 
 	// Validate and sum probabilities
 	sum := 0.0
 	for _, p := range probabilities {
 		if p < 0 || p > 1 {
-			return 0, fmt.Errorf("invalid input %v; valid range 0 <= p <= 1", p)
+			return nil, fmt.Errorf("invalid input %v; valid range 0 <= p <= 1", p)
 		}
 		sum += p
 	}
 
 	const epsilon = 1e-12 // allow for minor float faults
 	if math.Abs(sum-1.0) > epsilon {
-		return 0, fmt.Errorf("sum of probabilities %v; must be exactly 1.0", sum)
+		return nil, fmt.Errorf("sum of probabilities %v; must be exactly 1.0", sum)
 	}
 
 	// Build cumulative thresholds
@@ -95,18 +265,17 @@ func DeterministicRandom(seedHex string, sequence int64, probabilities []float64
 			thresholds[i] = uint64(cumulative * math.Pow(2, 64))
 		}
 	}
+	return thresholds, nil
+}
 
-	// Compute random number
+func sequenceBytes(sequence int64) []byte {
 	var buf [8]byte
 	binary.BigEndian.PutUint64(buf[:], uint64(sequence))
+	return buf[:]
+}
 
-	h := sha256.New()
-	h.Write(seed)
-	h.Write(buf[:])
-	hash := h.Sum(nil)
-	x := binary.BigEndian.Uint64(hash[:8])
-
-	// Find the selected index
+// selectIndex finds the index of the first threshold above x
+func selectIndex(thresholds []uint64, x uint64) (int64, error) {
 	for i, t := range thresholds {
 		if x < t {
 			if i < math.MinInt64 || i > math.MaxInt64 {
@@ -89,3 +89,103 @@ func Test_DeterministicRandom(t *testing.T) {
 		assert.Equal(t, testCase.expectedIndex, selectedIndex)
 	}
 }
+
+func Test_SeedFingerprint(t *testing.T) {
+	fingerprint, err := SeedFingerprint("9912f3bcf715a55ae5c9d47f9f6562599912f3bcf715a55ae5c9d47f9f656259")
+	assert.Nil(t, err)
+	assert.Len(t, fingerprint, 16)
+
+	other, err := SeedFingerprint("0000000000000000000000000000000000000000000000000000000000000000")
+	assert.Nil(t, err)
+	assert.NotEqual(t, fingerprint, other)
+
+	_, err = SeedFingerprint("zz")
+	assert.NotNil(t, err)
+}
+
+func Test_VRFDeterministicRandom(t *testing.T) {
+	seedHex := "9912f3bcf715a55ae5c9d47f9f6562599912f3bcf715a55ae5c9d47f9f656259"
+	probabilities := []float64{0.2, 0.2, 0.2, 0.2, 0.2}
+
+	publicKey, err := VRFPublicKey(seedHex)
+	assert.Nil(t, err)
+
+	counts := make([]int, len(probabilities))
+	for sequence := int64(0); sequence < 100; sequence++ {
+		number, proof, err := VRFDeterministicRandom(seedHex, sequence, probabilities)
+		assert.Nil(t, err)
+		counts[number]++
+
+		again, againProof, err := VRFDeterministicRandom(seedHex, sequence, probabilities)
+		assert.Nil(t, err)
+		assert.Equal(t, number, again)
+		assert.Equal(t, proof, againProof)
+
+		assert.Nil(t, VerifyVRFDeterministicRandom(publicKey, sequence, probabilities, number, proof))
+		assert.NotNil(t, VerifyVRFDeterministicRandom(publicKey, sequence, probabilities, (number+1)%5, proof))
+		assert.NotNil(t, VerifyVRFDeterministicRandom(publicKey, sequence+1, probabilities, number, proof))
+	}
+	for _, count := range counts {
+		assert.True(t, count > 0)
+	}
+
+	otherKey, err := VRFPublicKey("0000000000000000000000000000000000000000000000000000000000000001")
+	assert.Nil(t, err)
+	number, proof, err := VRFDeterministicRandom(seedHex, 7, probabilities)
+	assert.Nil(t, err)
+	assert.NotNil(t, VerifyVRFDeterministicRandom(otherKey, 7, probabilities, number, proof))
+
+	_, _, err = VRFDeterministicRandom(seedHex, -1, probabilities)
+	assert.EqualError(t, err, "sequence must be larger than than or equal to 0")
+
+	_, _, err = VRFDeterministicRandom(seedHex, 0, []float64{0.5})
+	assert.EqualError(t, err, "sum of probabilities 0.5; must be exactly 1.0")
+}
+
+func Test_DeriveSeed(t *testing.T) {
+	seedHex := "9912f3bcf715a55ae5c9d47f9f6562599912f3bcf715a55ae5c9d47f9f656259"
+
+	a, err := DeriveSeed(seedHex, PurposeDeterministic, "campaign-a")
+	assert.Nil(t, err)
+	assert.Len(t, a, 64)
+	assert.NotEqual(t, seedHex, a)
+
+	again, err := DeriveSeed(seedHex, PurposeDeterministic, "campaign-a")
+	assert.Nil(t, err)
+	assert.Equal(t, a, again)
+
+	b, err := DeriveSeed(seedHex, PurposeDeterministic, "campaign-b")
+	assert.Nil(t, err)
+	assert.NotEqual(t, a, b)
+
+	other, err := DeriveSeed(seedHex, "other-purpose", "campaign-a")
+	assert.Nil(t, err)
+	assert.NotEqual(t, a, other)
+
+	// The first 20 sequences of two campaigns are independent draws
+	probabilities := make([]float64, 100)
+	for i := range probabilities {
+		probabilities[i] = 0.01
+	}
+	same := 0
+	for sequence := int64(0); sequence < 20; sequence++ {
+		numberA, errA := DeterministicRandom(a, sequence, probabilities)
+		assert.Nil(t, errA)
+		numberB, errB := DeterministicRandom(b, sequence, probabilities)
+		assert.Nil(t, errB)
+		if numberA == numberB {
+			same++
+		}
+	}
+	assert.Less(t, same, 5)
+
+	_, err = DeriveSeed("abcd", PurposeDeterministic, "campaign-a")
+	assert.EqualError(t, err, "seed must decode to exactly 32 bytes")
+
+	_, err = DeriveSeed(seedHex, "", "campaign-a")
+	assert.EqualError(t, err, "purpose must not be empty")
+
+	// A zero byte in the purpose would make ("a\x00b", "c") and ("a", "b\x00c") the same label
+	_, err = DeriveSeed(seedHex, "a\x00b", "c")
+	assert.EqualError(t, err, "purpose must not contain a zero byte")
+}
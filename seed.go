@@ -0,0 +1,109 @@
+package random
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+)
+
+// ErrWeakSeed is returned by ValidateSeed for a seed that is guessable
+var ErrWeakSeed = errors.New("weak seed")
+
+// knownSeeds are seeds published in examples and tests, they must never draw production outcomes
+var knownSeeds = map[string]bool{
+	// README and Docker examples
+	"0000000000000000000000000000000000000000000000000000000000000000": true,
+	// random_test.go
+	"9912f3bcf715a55ae5c9d47f9f6562599912f3bcf715a55ae5c9d47f9f656259": true,
+	// SHA-256 of the empty string
+	"e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855": true,
+}
+
+// minDistinctBytes is the least number of distinct bytes a seed must hold.
+// 32 random bytes hold about 30, fewer than 16 happens with a negligible probability.
+const minDistinctBytes = 16
+
+// GenerateSeed returns a new seed from crypto/rand in hex
+func GenerateSeed() (string, error) {
+	for {
+		seed := make([]byte, 32)
+		_, err := rand.Read(seed)
+		if err != nil {
+			return "", err
+		}
+
+		// A random seed failing validation is all but impossible, but never hand one out
+		seedHex := hex.EncodeToString(seed)
+		if ValidateSeed(seedHex) == nil {
+			return seedHex, nil
+		}
+	}
+}
+
+// ValidateSeed checks that a seed is 64 hex characters and rejects guessable seeds:
+// published example seeds, seeds of few distinct bytes, repeating patterns of up to
+// 16 bytes and sequences of bytes that increase or decrease by the same step.
+func ValidateSeed(seedHex string) error {
+	if len(seedHex) != 64 {
+		return errors.New("seed must be 64 hex characters")
+	}
+	seed, err := hex.DecodeString(seedHex)
+	if err != nil {
+		return errors.New("seed must only hold hex characters")
+	}
+
+	if knownSeeds[hex.EncodeToString(seed)] {
+		return fmt.Errorf("%w: the seed is a published example, a unique seed value is required", ErrWeakSeed)
+	}
+
+	if repeats(seed, 1) {
+		return fmt.Errorf("%w: the seed repeats a single byte", ErrWeakSeed)
+	}
+	for period := 2; period <= len(seed)/2; period++ {
+		if repeats(seed, period) {
+			return fmt.Errorf("%w: the seed repeats a pattern of %v bytes", ErrWeakSeed, period)
+		}
+	}
+
+	distinct := map[byte]bool{}
+	for _, b := range seed {
+		distinct[b] = true
+	}
+	if len(distinct) < minDistinctBytes {
+		return fmt.Errorf("%w: the seed holds only %v distinct bytes", ErrWeakSeed, len(distinct))
+	}
+
+	step := seed[1] - seed[0]
+	for i := 2; i < len(seed); i++ {
+		if seed[i]-seed[i-1] != step {
+			return nil
+		}
+	}
+	return fmt.Errorf("%w: the seed is a sequence of bytes with a step of %v", ErrWeakSeed, int8(step))
+}
+
+func repeats(seed []byte, period int) bool {
+	for i := period; i < len(seed); i++ {
+		if seed[i] != seed[i-period] {
+			return false
+		}
+	}
+	return true
+}
+
+// SeedCommitment returns a hex SHA-256 commitment to a seed. Published before the
+// seed is used, it proves after the seed is revealed that the seed was not changed.
+// It does not reveal the seed, which holds 256 bits of entropy.
+func SeedCommitment(seedHex string) (string, error) {
+	seed, err := hex.DecodeString(seedHex)
+	if err != nil {
+		return "", fmt.Errorf("invalid seed hex: %w", err)
+	}
+
+	h := sha256.New()
+	h.Write([]byte("random/seed-commitment/v1"))
+	h.Write(seed)
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
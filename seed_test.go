@@ -0,0 +1,61 @@
+package random
+
+import (
+	"github.com/stretchr/testify/assert"
+	"strings"
+	"testing"
+)
+
+func Test_GenerateSeed(t *testing.T) {
+	seed, err := GenerateSeed()
+	assert.Nil(t, err)
+	assert.Len(t, seed, 64)
+	assert.Nil(t, ValidateSeed(seed))
+
+	other, err := GenerateSeed()
+	assert.Nil(t, err)
+	assert.NotEqual(t, seed, other)
+}
+
+func Test_ValidateSeed(t *testing.T) {
+	assert.Nil(t, ValidateSeed("0f6aa358754a2b1fac2206849282b6c68dd6e086f635df2796acea52ae920ddc"))
+	assert.Nil(t, ValidateSeed("0F6AA358754A2B1FAC2206849282B6C68DD6E086F635DF2796ACEA52AE920DDC"))
+
+	testCases := []struct {
+		seed string
+		err  string
+	}{
+		{"abcd", "seed must be 64 hex characters"},
+		{"0f6aa358754a2b1fac2206849282b6c68dd6e086f635df2796acea52ae920ddg", "seed must only hold hex characters"},
+		{strings.Repeat("0", 64), "weak seed: the seed is a published example, a unique seed value is required"},
+		{"9912F3BCF715A55AE5C9D47F9F6562599912F3BCF715A55AE5C9D47F9F656259", "weak seed: the seed is a published example, a unique seed value is required"},
+		{strings.Repeat("ff", 32), "weak seed: the seed repeats a single byte"},
+		{strings.Repeat("deadbeef", 8), "weak seed: the seed repeats a pattern of 4 bytes"},
+		{strings.Repeat("0123456789abcdef", 4), "weak seed: the seed repeats a pattern of 8 bytes"},
+		{strings.Repeat("01", 16) + strings.Repeat("02", 16), "weak seed: the seed holds only 2 distinct bytes"},
+		{"000102030405060708090a0b0c0d0e0f101112131415161718191a1b1c1d1e1f", "weak seed: the seed is a sequence of bytes with a step of 1"},
+		{"fffefdfcfbfaf9f8f7f6f5f4f3f2f1f0efeeedecebeae9e8e7e6e5e4e3e2e1e0", "weak seed: the seed is a sequence of bytes with a step of -1"},
+	}
+
+	for _, tc := range testCases {
+		err := ValidateSeed(tc.seed)
+		assert.EqualError(t, err, tc.err, tc.seed)
+	}
+}
+
+func Test_SeedCommitment(t *testing.T) {
+	commitment, err := SeedCommitment("0f6aa358754a2b1fac2206849282b6c68dd6e086f635df2796acea52ae920ddc")
+	assert.Nil(t, err)
+	assert.Len(t, commitment, 64)
+
+	other, err := SeedCommitment("c8bfdd8e79e4a001038ac5c0370f1eb7cd222f97333c9657e17483233729f282")
+	assert.Nil(t, err)
+	assert.NotEqual(t, commitment, other)
+
+	fingerprint, err := SeedFingerprint("0f6aa358754a2b1fac2206849282b6c68dd6e086f635df2796acea52ae920ddc")
+	assert.Nil(t, err)
+	assert.NotEqual(t, fingerprint, commitment[:16])
+
+	_, err = SeedCommitment("xyz")
+	assert.NotNil(t, err)
+}